@@ -4,42 +4,78 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
-	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/migrations"
 	"github.com/nprasad2077/NBA_Go/utils/metrics"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// Database drivers accepted in DB_DRIVER.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// defaultSQLitePath matches the data directory the Docker image creates.
+const defaultSQLitePath = "data/nba_go.db"
+
+// Dialector picks the database from DB_DRIVER (default postgres).
+//
+//	postgres: DB_DSN, or a DSN built from DB_HOST, DB_USER, DB_PASSWORD, DB_NAME, DB_PORT
+//	sqlite:   DB_DSN, or the file at DB_PATH (default data/nba_go.db)
+func Dialector() (gorm.Dialector, error) {
+	dsn := os.Getenv("DB_DSN")
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", DriverPostgres:
+		if dsn == "" {
+			dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
+				os.Getenv("DB_HOST"),
+				os.Getenv("DB_USER"),
+				os.Getenv("DB_PASSWORD"),
+				os.Getenv("DB_NAME"),
+				os.Getenv("DB_PORT"),
+			)
+		}
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		if dsn == "" {
+			path := os.Getenv("DB_PATH")
+			if path == "" {
+				path = defaultSQLitePath
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return nil, err
+			}
+			// WAL and a busy timeout let the API read while an importer writes.
+			dsn = "file:" + path + "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000"
+		}
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q (want %s or %s)", driver, DriverPostgres, DriverSQLite)
+	}
+}
+
 func InitDB(shouldMigrate bool) *gorm.DB {
-	// CHANGE: Build the DSN from environment variables
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{}) // <- CHANGE: Use the postgres driver
+	dialector, err := Dialector()
+	if err != nil {
+		log.Fatalf("database config: %v", err)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 	metrics.DBOperationsTotal.WithLabelValues("connect", "database").Inc()
 
 	if shouldMigrate {
-		if err := db.AutoMigrate(&models.PlayerAdvancedStat{}); err != nil {
-			log.Fatalf("migrate PlayerAdvancedStat: %v", err)
-		}
-		if err := db.AutoMigrate(&models.PlayerTotalStat{}); err != nil {
-			log.Fatalf("migrate PlayerTotalStat: %v", err)
-		}
-		if err := db.AutoMigrate(&models.PlayerShotChart{}); err != nil {
-			log.Fatalf("migrate PlayerShotChart: %v", err)
-		}
-		if err := db.AutoMigrate(&models.APIKey{}); err != nil {
-			log.Fatalf("migrate APIKey: %v", err)
+		n, err := migrations.Up(db)
+		if err != nil {
+			log.Fatalf("migrate: %v", err)
 		}
+		log.Printf("Schema at version %d (%d migrations applied)", migrations.Latest(), n)
 		metrics.DBOperationsTotal.WithLabelValues("migrate", "database").Inc()
 	}
 
@@ -6,46 +6,51 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"github.com/nprasad2077/NBA_Go/utils/security"
-	"gorm.io/gorm"
 )
 
-// very small middleware: require header X‑Admin‑Secret == $ADMIN_SECRET
+// very small middleware: require header X‑Admin‑Secret == $ADMIN_SECRET;
+// with no secret configured every request is refused
 func adminGuard() fiber.Handler {
 	secret := os.Getenv("ADMIN_SECRET")
 	return func(c *fiber.Ctx) error {
-		if c.Get("X-Admin-Secret") != secret {
+		if secret == "" || c.Get("X-Admin-Secret") != secret {
 			return c.SendStatus(http.StatusUnauthorized)
 		}
 		return c.Next()
 	}
 }
 
-func RegisterKeyAdminRoutes(app *fiber.App, db *gorm.DB) {
+func RegisterKeyAdminRoutes(app *fiber.App, keys repository.APIKeyRepository) {
 	admin := app.Group("/admin/keys", adminGuard())
 
 	admin.Get("/", func(c *fiber.Ctx) error {
-		var keys []models.APIKey
-		db.Find(&keys)
-		return c.JSON(keys)
+		list, err := keys.List()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(list)
 	})
 
 	admin.Post("/", func(c *fiber.Ctx) error {
 		var body struct{ Label string }
 		if err := c.BodyParser(&body); err != nil {
-			return err
+			return c.Status(400).JSON(fiber.Map{"error": "invalid body"})
 		}
 
 		raw, err := security.GenerateRawKey()
 		if err != nil {
-			return err
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		key := models.APIKey{
 			Hash:  security.HashKey(raw),
 			Label: body.Label,
 		}
-		db.Create(&key)
+		if err := keys.Create(&key); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
 
 		// return ONLY the raw key once
 		return c.JSON(fiber.Map{
@@ -55,8 +60,13 @@ func RegisterKeyAdminRoutes(app *fiber.App, db *gorm.DB) {
 	})
 
 	admin.Post("/:id/revoke", func(c *fiber.Ctx) error {
-		id := c.Params("id")
-		db.Model(&models.APIKey{}).Where("id = ?", id).Update("revoked", true)
-		return c.JSON(fiber.Map{"revoked": id})
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "invalid key id"})
+		}
+		if err := keys.Revoke(uint(id)); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"revoked": c.Params("id")})
 	})
-}
\ No newline at end of file
+}
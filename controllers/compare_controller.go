@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+// Season alignments for comparisons.
+const (
+	AlignSeason     = "season"
+	AlignAge        = "age"
+	AlignExperience = "experience"
+)
+
+const maxComparePlayers = 10
+
+var defaultCompareStats = []string{
+	"games", "points", "totalRb", "assists", "fieldPercent", "threePercent", "ftPercent",
+	"per", "tsPercent", "winShares",
+}
+
+// comparePeakLowest are the stats whose peak is their lowest season.
+var comparePeakLowest = map[string]bool{"turnoverPercent": true}
+
+// compareNoPeak are counts where neither extreme is a best season: the
+// fewest turnovers or fouls just means the fewest minutes.
+var compareNoPeak = map[string]bool{"turnovers": true, "personalFouls": true}
+
+// CompareSeason is one player's season in a comparison.
+type CompareSeason struct {
+	Season int    `json:"season"`
+	Age    int    `json:"age"`
+	Year   int    `json:"year"` // 1 for the player's first regular season
+	Team   string `json:"team"`
+	// Stats holds the requested stats; advanced stats are missing when the
+	// season has no advanced row.
+	Stats map[string]interface{} `json:"stats"`
+}
+
+// ComparePeak is a player's best season for one stat.
+type ComparePeak struct {
+	Season int     `json:"season"`
+	Age    int     `json:"age"`
+	Value  float64 `json:"value"`
+}
+
+// ComparePlayer is one player's career and peaks.
+type ComparePlayer struct {
+	PlayerID   string                   `json:"playerId"`
+	PlayerName string                   `json:"playerName"`
+	Career     *models.PlayerCareerStat `json:"career"`
+	// Peaks maps each stat to its best qualifying season, the lowest for
+	// turnoverPercent; nil when no season qualifies and always nil for
+	// turnovers and personalFouls.
+	Peaks map[string]*ComparePeak `json:"peaks"`
+}
+
+// CompareRow is one aligned row: each player's season at Key, in the
+// order of Players, or null.
+type CompareRow struct {
+	Key     int              `json:"key"`
+	Seasons []*CompareSeason `json:"seasons"`
+}
+
+// CompareResponse lays several players' seasons side by side.
+type CompareResponse struct {
+	Align   string          `json:"align"`
+	Stats   []string        `json:"stats"`
+	Players []ComparePlayer `json:"players"`
+	Rows    []CompareRow    `json:"rows"`
+}
+
+// compareStat is a requested stat and the dataset it is read from.
+type compareStat struct {
+	dataset string
+	field   repository.Field
+}
+
+// compareSeason is a season being assembled from both datasets.
+type compareSeason struct {
+	out      CompareSeason
+	totals   *models.PlayerTotalStat
+	advanced *models.PlayerAdvancedStat
+}
+
+// ComparePlayers godoc
+// //@Security ApiKeyAuth
+// @Summary Compare players
+// @Description Two to ten players' seasons side by side, aligned by calendar season, age or years in the league,
+// @Description with each player's career totals and peak season per stat. Peaks of rate stats only count seasons that qualify for the leaderboards;
+// @Description turnoverPercent peaks at its lowest season and turnovers and personalFouls have no peak. Experience counts regular seasons, also for playoff rows.
+// @Tags Players
+// @Produce  json
+// @Param players query string true "Comma-separated player IDs, BR or NBA.com (e.g. curryst01,duranke01)"
+// @Param stats query string false "Comma-separated totals or advanced stats (default games,points,totalRb,assists,fieldPercent,threePercent,ftPercent,per,tsPercent,winShares)"
+// @Param align query string false "Row alignment" Enums(season, age, experience) default(season)
+// @Param isPlayoff query bool false "Playoffs instead of regular season"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Success 200 {object} CompareResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/compare [get]
+func ComparePlayers(
+	totals repository.TotalStatsRepository,
+	advanced repository.AdvancedStatsRepository,
+	careers repository.CareerStatsRepository,
+	ids repository.PlayerIDResolver,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		var players []string
+		for _, p := range strings.Split(c.Query("players"), ",") {
+			if p = strings.TrimSpace(p); p != "" && !slices.Contains(players, p) {
+				players = append(players, p)
+			}
+		}
+		if len(players) < 2 || len(players) > maxComparePlayers {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("players needs 2 to %d player IDs", maxComparePlayers)})
+		}
+		align := c.Query("align", AlignSeason)
+		if align != AlignSeason && align != AlignAge && align != AlignExperience {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("invalid align %q (want season, age or experience)", align)})
+		}
+		statNames := defaultCompareStats
+		if s := c.Query("stats"); s != "" {
+			statNames = strings.Split(s, ",")
+		}
+		stats, err := parseCompareStats(statNames)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		isPlayoff := c.QueryBool("isPlayoff", false)
+
+		resp := CompareResponse{Align: align, Players: make([]ComparePlayer, len(players))}
+		for _, s := range stats {
+			resp.Stats = append(resp.Stats, s.field.Name)
+		}
+		byKey := map[int][]*CompareSeason{}
+		for i, player := range players {
+			playerIDs, err := ids.ResolvePlayerIDs(player)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			q := repository.StatsQuery{
+				Filter: repository.StatsFilter{
+					League: league, PlayerIDs: playerIDs, IsPlayoff: &isPlayoff, TeamMode: repository.TeamModeCombined,
+				},
+				Sort:      repository.Sort{Column: "season", Ascending: true},
+				SkipCount: true,
+			}
+			totalRows, _, err := totals.List(q)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			advancedRows, _, err := advanced.List(q)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			if len(totalRows) == 0 && len(advancedRows) == 0 {
+				return c.Status(404).JSON(fiber.Map{"error": "no stats for player " + player})
+			}
+			careerRows, err := careers.List(repository.CareerQuery{League: league, IsPlayoff: isPlayoff, PlayerIDs: playerIDs})
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+
+			seasons, name := mergeCompareSeasons(totalRows, advancedRows)
+			// Experience is years in the league, not playoff appearances.
+			regular := seasons
+			if isPlayoff {
+				regularSeason := false
+				q.Filter.IsPlayoff = &regularSeason
+				q.Columns = []string{"season"}
+				regularTotals, _, err := totals.List(q)
+				if err != nil {
+					return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				}
+				regularAdvanced, _, err := advanced.List(q)
+				if err != nil {
+					return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				}
+				regular, _ = mergeCompareSeasons(regularTotals, regularAdvanced)
+			}
+			numberCompareSeasons(seasons, regular)
+			if err := fillCompareStats(seasons, stats); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			resp.Players[i] = ComparePlayer{
+				PlayerID: player, PlayerName: name,
+				Peaks: comparePeaks(seasons, stats, league, isPlayoff),
+			}
+			if len(careerRows) > 0 {
+				resp.Players[i].Career = &careerRows[0]
+			}
+
+			for _, s := range seasons {
+				key := s.out.Season
+				switch align {
+				case AlignAge:
+					key = s.out.Age
+				case AlignExperience:
+					key = s.out.Year
+				}
+				row := byKey[key]
+				if row == nil {
+					row = make([]*CompareSeason, len(players))
+					byKey[key] = row
+				}
+				if row[i] == nil { // a second season at the same age keeps the first
+					row[i] = &s.out
+				}
+			}
+		}
+
+		keys := make([]int, 0, len(byKey))
+		for k := range byKey {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			resp.Rows = append(resp.Rows, CompareRow{Key: k, Seasons: byKey[k]})
+		}
+		return c.JSON(resp)
+	}
+}
+
+// parseCompareStats looks each stat up in totals, then advanced.
+func parseCompareStats(names []string) ([]compareStat, error) {
+	var stats []compareStat
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		dataset, field, _, err := leaderStat(name, "", false)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, compareStat{dataset: dataset, field: field})
+	}
+	return stats, nil
+}
+
+// mergeCompareSeasons pairs the totals and advanced rows of each season,
+// in season order. Rows come sorted by season; when both ID styles have a
+// season, the first row wins.
+func mergeCompareSeasons(totalRows []models.PlayerTotalStat, advancedRows []models.PlayerAdvancedStat) ([]*compareSeason, string) {
+	bySeason := map[int]*compareSeason{}
+	var seasons []*compareSeason
+	var name string
+	get := func(season, age int, team, playerName string) *compareSeason {
+		s := bySeason[season]
+		if s == nil {
+			s = &compareSeason{out: CompareSeason{Season: season, Age: age, Team: team}}
+			bySeason[season] = s
+			seasons = append(seasons, s)
+		}
+		name = cmp.Or(name, playerName)
+		return s
+	}
+	for i := range totalRows {
+		t := &totalRows[i]
+		if s := get(t.Season, t.Age, t.Team, t.PlayerName); s.totals == nil {
+			s.totals = t
+		}
+	}
+	for i := range advancedRows {
+		a := &advancedRows[i]
+		if s := get(a.Season, a.Age, a.Team, a.PlayerName); s.advanced == nil {
+			s.advanced = a
+		}
+	}
+	slices.SortFunc(seasons, func(a, b *compareSeason) int { return a.out.Season - b.out.Season })
+	return seasons, name
+}
+
+// numberCompareSeasons sets each season's Year to the number of regular
+// seasons played up to and including it.
+func numberCompareSeasons(seasons, regular []*compareSeason) {
+	for _, s := range seasons {
+		s.out.Year = 0
+		for _, r := range regular {
+			if r.out.Season <= s.out.Season {
+				s.out.Year++
+			}
+		}
+	}
+}
+
+// fillCompareStats reads the requested stats off each season's rows.
+func fillCompareStats(seasons []*compareSeason, stats []compareStat) error {
+	var totalStats, advancedStats []repository.Field
+	for _, s := range stats {
+		if s.dataset == services.LeaderAdvanced {
+			advancedStats = append(advancedStats, s.field)
+		} else {
+			totalStats = append(totalStats, s.field)
+		}
+	}
+	for _, s := range seasons {
+		s.out.Stats = map[string]interface{}{}
+		if s.totals != nil && len(totalStats) > 0 {
+			rows, err := repository.Project([]models.PlayerTotalStat{*s.totals}, totalStats)
+			if err != nil {
+				return err
+			}
+			for k, v := range rows[0] {
+				s.out.Stats[k] = v
+			}
+		}
+		if s.advanced != nil && len(advancedStats) > 0 {
+			rows, err := repository.Project([]models.PlayerAdvancedStat{*s.advanced}, advancedStats)
+			if err != nil {
+				return err
+			}
+			for k, v := range rows[0] {
+				s.out.Stats[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// comparePeaks finds each stat's best season among those that meet the
+// leaderboard qualification: the highest, or the lowest for
+// comparePeakLowest stats. The earlier season wins a tie.
+func comparePeaks(seasons []*compareSeason, stats []compareStat, league string, isPlayoff bool) map[string]*ComparePeak {
+	peaks := make(map[string]*ComparePeak, len(stats))
+	for _, st := range stats {
+		name := st.field.Name
+		peaks[name] = nil
+		if compareNoPeak[name] {
+			continue
+		}
+		lowest := comparePeakLowest[name]
+		for _, s := range seasons {
+			v, ok := s.out.Stats[name]
+			if !ok || !compareQualifies(s, st, league, isPlayoff) {
+				continue
+			}
+			value := services.NumberValue(v)
+			if best := peaks[name]; best == nil || (lowest && value < best.Value) || (!lowest && value > best.Value) {
+				peaks[name] = &ComparePeak{Season: s.out.Season, Age: s.out.Age, Value: value}
+			}
+		}
+	}
+	return peaks
+}
+
+// compareQualifies holds a season to the stat's leaderboard floor.
+func compareQualifies(s *compareSeason, st compareStat, league string, isPlayoff bool) bool {
+	q := services.LeaderQualification(st.dataset, st.field.Name, league, s.out.Season, isPlayoff)
+	if st.dataset == services.LeaderAdvanced {
+		return q.Meets(s.advanced.Games, float64(s.advanced.MinutesPlayed), 0)
+	}
+	made := 0
+	if q.MadeField != "" {
+		field, _ := totalFields.Lookup(q.MadeField)
+		rows, err := repository.Project([]models.PlayerTotalStat{*s.totals}, []repository.Field{field})
+		if err != nil {
+			return false
+		}
+		made = int(services.NumberValue(rows[0][field.Name]))
+	}
+	return q.Meets(s.totals.Games, s.totals.MinutesPG, made)
+}
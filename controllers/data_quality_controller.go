@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/services"
+	"gorm.io/gorm"
+)
+
+// RegisterDataQualityRoutes exposes the data quality report behind the admin secret.
+func RegisterDataQualityRoutes(app *fiber.App, db *gorm.DB) {
+	admin := app.Group("/admin/dataquality", adminGuard())
+
+	admin.Get("/", GetDataQualityReport(db))
+	admin.Post("/run", RunDataQuality(db))
+}
+
+// GetDataQualityReport godoc
+// @ignore
+// @Summary     Data quality report for a season
+// @Description Violation counts per rule plus the offending rows from the last validation run.
+// @Tags        Admin
+// @Produce     json
+// @Param       season  query  int  true   "Season (e.g. 2024)"
+// @Param       limit   query  int  false  "Max violation rows returned, 1 to 1000"  default(500)
+// @Param       league  query  string false "League (default NBA)"
+// @Success     200     {object} map[string]interface{}
+// @Failure     400,500 {object} map[string]string
+// //@Router      /admin/dataquality [get]
+func GetDataQualityReport(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		season := c.QueryInt("season", 0)
+		if season == 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "season is required"})
+		}
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		limit := min(max(c.QueryInt("limit", 500), 1), maxPageLimit)
+		report, err := services.GetDataQualityReport(db, league, season, limit)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(report)
+	}
+}
+
+// RunDataQuality godoc
+// @ignore
+// @Summary     Re-validate a season on demand
+// @Tags        Admin
+// @Produce     json
+// @Param       season  query  int  true  "Season (e.g. 2024)"
+// @Param       league  query  string false "League (default NBA)"
+// @Success     200     {object} map[string]interface{}
+// @Failure     400,500 {object} map[string]string
+// //@Router      /admin/dataquality/run [post]
+func RunDataQuality(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		season := c.QueryInt("season", 0)
+		if season == 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "season is required"})
+		}
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		total, err := services.RunSeasonDataQuality(db, league, season)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"league": league, "season": season, "violations": total})
+	}
+}
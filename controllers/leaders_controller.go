@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+const (
+	defaultLeaderLimit = 25
+	maxLeaderLimit     = 100
+	// maxLeaderTies caps the rows tied with the last place that are kept
+	// beyond the limit.
+	maxLeaderTies = 100
+	// leaderBatch is how many candidates are read per query.
+	leaderBatch = 500
+)
+
+// careerFields are the fields a career leaderboard can rank.
+var careerFields = repository.ModelFields(&models.PlayerCareerStat{})
+
+// minutesColumns hold each dataset's total minutes.
+var minutesColumns = map[string]string{
+	services.LeaderTotals:   "minutes_pg", // season total despite the name
+	services.LeaderAdvanced: "minutes_played",
+	services.LeaderCareer:   "minutes",
+}
+
+// LeadersResponse is one stat's leaderboard.
+type LeadersResponse struct {
+	Stat      string `json:"stat"`
+	Dataset   string `json:"dataset"`
+	League    string `json:"league"`
+	Season    int    `json:"season,omitempty"` // 0 ranks every season
+	IsPlayoff bool   `json:"isPlayoff"`
+	// Qualification is the floor for the requested season, or for a full
+	// schedule when ranking every season; each season is prorated by its
+	// length unless overridden.
+	Qualification services.Qualification `json:"qualification"`
+	Leaders       []services.Leader      `json:"leaders"`
+}
+
+// leaderCandidate is one row read for a leaderboard, before qualifying.
+type leaderCandidate struct {
+	leader  services.Leader
+	minutes float64
+	made    int
+}
+
+// GetStatLeaders godoc
+// @Summary Stat leaderboard
+// @Description Ranks players on any numeric totals or advanced stat, applying Basketball-Reference-style qualification (games, minutes or makes) prorated for shortened seasons. Tied players share a rank.
+// @Tags Leaders
+// @Produce  json
+// @Param stat path string true "Stat JSON name (e.g. points, threePercent, per)"
+// @Param dataset query string false "Where to look the stat up when both have it" Enums(totals, advanced)
+// @Param career query bool false "Rank career totals instead of seasons (totals stats only)"
+// @Param season query int false "Season; omit to rank every season"
+// @Param isPlayoff query bool false "Playoffs instead of regular season"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Param ascending query bool false "Rank the lowest values first"
+// @Param limit query int false "Number of places (max 100)" default(25)
+// @Param minGames query int false "Override the games floor"
+// @Param minMinutes query number false "Override the minutes floor"
+// @Param minMade query int false "Override the makes floor of a shooting percentage"
+// @Success 200 {object} LeadersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// //@Security ApiKeyAuth
+// @Router /api/leaders/{stat} [get]
+func GetStatLeaders(
+	totals repository.TotalStatsRepository,
+	advanced repository.AdvancedStatsRepository,
+	careers repository.CareerStatsRepository,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		career := c.QueryBool("career", false)
+		dataset, stat, fields, err := leaderStat(c.Params("stat"), c.Query("dataset"), career)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		limit := c.QueryInt("limit", defaultLeaderLimit)
+		if limit < 1 || limit > maxLeaderLimit {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxLeaderLimit)})
+		}
+		season := c.QueryInt("season", 0)
+		if career {
+			season = 0
+		}
+		isPlayoff := c.QueryBool("isPlayoff", false)
+
+		override, err := parseQualificationOverride(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		floor := func(season int) services.Qualification {
+			return override(services.LeaderQualification(dataset, stat.Name, league, season, isPlayoff))
+		}
+		resp := LeadersResponse{
+			Stat: stat.Name, Dataset: dataset, League: league, Season: season, IsPlayoff: isPlayoff,
+			Qualification: floor(season),
+		}
+		if resp.Qualification.MadeField == "" && c.Query("minMade") != "" {
+			return c.Status(400).JSON(fiber.Map{"error": "minMade only applies to shooting percentages"})
+		}
+
+		// The loosest floor narrows the candidates in SQL; each row is then
+		// held to its own season's floor.
+		loosest := resp.Qualification
+		if season == 0 && !career {
+			loosest = override(services.LowestLeaderQualification(dataset, stat.Name, league, isPlayoff))
+		}
+		var made repository.Field
+		if loosest.MadeField != "" {
+			made, _ = fields.Lookup(loosest.MadeField)
+		}
+		conds := qualificationConditions(loosest, minutesColumns[dataset], made.Column)
+		sort := repository.Sort{Column: stat.Column, Ascending: c.QueryBool("ascending", false)}
+
+		fetch := func(offset int) ([]leaderCandidate, error) {
+			switch dataset {
+			case services.LeaderCareer:
+				rows, err := careers.List(repository.CareerQuery{
+					League: league, IsPlayoff: isPlayoff, Conditions: conds,
+					Sort: sort, Limit: leaderBatch, Offset: offset,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return leaderCandidates(rows, stat, made, func(s *models.PlayerCareerStat) leaderCandidate {
+					return leaderCandidate{
+						leader:  services.Leader{PlayerID: s.PlayerID, PlayerName: s.PlayerName, Games: s.Games},
+						minutes: s.Minutes,
+					}
+				})
+			case services.LeaderAdvanced:
+				rows, _, err := advanced.List(leaderQuery(league, season, isPlayoff, conds, sort, offset))
+				if err != nil {
+					return nil, err
+				}
+				return leaderCandidates(rows, stat, made, func(s *models.PlayerAdvancedStat) leaderCandidate {
+					return leaderCandidate{
+						leader: services.Leader{
+							PlayerID: s.PlayerID, PlayerName: s.PlayerName, Season: s.Season, Team: s.Team, Games: s.Games,
+						},
+						minutes: float64(s.MinutesPlayed),
+					}
+				})
+			default:
+				rows, _, err := totals.List(leaderQuery(league, season, isPlayoff, conds, sort, offset))
+				if err != nil {
+					return nil, err
+				}
+				return leaderCandidates(rows, stat, made, func(s *models.PlayerTotalStat) leaderCandidate {
+					return leaderCandidate{
+						leader: services.Leader{
+							PlayerID: s.PlayerID, PlayerName: s.PlayerName, Season: s.Season, Team: s.Team, Games: s.Games,
+						},
+						minutes: s.MinutesPG,
+					}
+				})
+			}
+		}
+
+		leaders := []services.Leader{}
+	scan:
+		for offset := 0; ; offset += leaderBatch {
+			batch, err := fetch(offset)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			for _, cand := range batch {
+				if n := len(leaders); n >= limit &&
+					(cand.leader.Value != leaders[n-1].Value || n >= limit+maxLeaderTies) {
+					break scan
+				}
+				if floor(cand.leader.Season).Meets(cand.leader.Games, cand.minutes, cand.made) {
+					leaders = append(leaders, cand.leader)
+				}
+			}
+			if len(batch) < leaderBatch {
+				break
+			}
+		}
+		services.RankLeaders(leaders)
+		resp.Leaders = leaders
+		return c.JSON(resp)
+	}
+}
+
+// leaderStat resolves a stat name to its dataset and field. Totals win
+// when both datasets have the stat, unless dataset says otherwise.
+func leaderStat(name, dataset string, career bool) (string, repository.Field, repository.Fields, error) {
+	var candidates []string
+	switch {
+	case career && dataset == services.LeaderAdvanced:
+		return "", repository.Field{}, nil, fmt.Errorf("career leaderboards cover totals stats only")
+	case career:
+		candidates = []string{services.LeaderCareer}
+	case dataset == "":
+		candidates = []string{services.LeaderTotals, services.LeaderAdvanced}
+	case dataset == services.LeaderTotals || dataset == services.LeaderAdvanced:
+		candidates = []string{dataset}
+	default:
+		return "", repository.Field{}, nil, fmt.Errorf("invalid dataset %q (want totals or advanced)", dataset)
+	}
+	for _, ds := range candidates {
+		fields := map[string]repository.Fields{
+			services.LeaderTotals:   totalFields,
+			services.LeaderAdvanced: advancedFields,
+			services.LeaderCareer:   careerFields,
+		}[ds]
+		// "id" is the source's row ID, not a stat.
+		if f, ok := fields.Lookup(name); ok && f.Kind == repository.FieldNumber && f.Name != "id" {
+			return ds, f, fields, nil
+		}
+	}
+	return "", repository.Field{}, nil, fmt.Errorf("unknown stat %q", name)
+}
+
+// parseQualificationOverride reads minGames, minMinutes and minMade; the
+// returned function replaces the matching floors.
+func parseQualificationOverride(c *fiber.Ctx) (func(services.Qualification) services.Qualification, error) {
+	var games, made *int
+	var minutes *float64
+	for _, name := range []string{"minGames", "minMade"} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q", name, raw)
+		}
+		if name == "minGames" {
+			games = &n
+		} else {
+			made = &n
+		}
+	}
+	if raw := c.Query("minMinutes"); raw != "" {
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("invalid minMinutes %q", raw)
+		}
+		minutes = &f
+	}
+	return func(q services.Qualification) services.Qualification {
+		if games != nil {
+			q.MinGames = *games
+		}
+		if minutes != nil {
+			q.MinMinutes = *minutes
+		}
+		if made != nil {
+			q.MinMade = *made
+		}
+		return q
+	}, nil
+}
+
+// qualificationConditions expresses q as filter conditions.
+func qualificationConditions(q services.Qualification, minutesColumn, madeColumn string) []repository.Condition {
+	var conds []repository.Condition
+	if q.MinGames > 0 {
+		conds = append(conds, repository.Condition{Column: "games", Op: repository.OpGte, Values: []interface{}{q.MinGames}})
+	}
+	if q.MinMinutes > 0 {
+		conds = append(conds, repository.Condition{Column: minutesColumn, Op: repository.OpGte, Values: []interface{}{q.MinMinutes}})
+	}
+	if q.MinMade > 0 && madeColumn != "" {
+		conds = append(conds, repository.Condition{Column: madeColumn, Op: repository.OpGte, Values: []interface{}{q.MinMade}})
+	}
+	return conds
+}
+
+// leaderQuery is one batch of season rows, one row per player-season.
+func leaderQuery(league string, season int, isPlayoff bool, conds []repository.Condition, sort repository.Sort, offset int) repository.StatsQuery {
+	return repository.StatsQuery{
+		Filter: repository.StatsFilter{
+			League: league, Season: season, IsPlayoff: &isPlayoff,
+			TeamMode: repository.TeamModeCombined, Conditions: conds,
+		},
+		Sort: sort, Limit: leaderBatch, Offset: offset, SkipCount: true,
+	}
+}
+
+// leaderCandidates reads stat (and made, when set) from each row; base
+// fills in the rest.
+func leaderCandidates[T any](rows []T, stat, made repository.Field, base func(*T) leaderCandidate) ([]leaderCandidate, error) {
+	fields := []repository.Field{stat}
+	if made.Column != "" {
+		fields = append(fields, made)
+	}
+	values, err := repository.Project(rows, fields)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]leaderCandidate, len(rows))
+	for i := range rows {
+		out[i] = base(&rows[i])
+		out[i].leader.Value = services.NumberValue(values[i][stat.Name])
+		if made.Column != "" {
+			out[i].made = int(services.NumberValue(values[i][made.Name]))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+// leagueParam reads the league query parameter; it defaults to NBA.
+func leagueParam(c *fiber.Ctx) (string, error) {
+	return services.ParseLeague(c.Query("league"))
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+const maxPageLimit = 1000
+
+// pageParams are a list request's pagination options. page/pageSize is
+// the default offset mode; cursor= or limit= switches to keyset mode,
+// which stays stable while rows are inserted and never scans skipped rows.
+type pageParams struct {
+	keyset       bool
+	page         int
+	pageSize     int
+	limit        int
+	after        *repository.Cursor
+	includeTotal bool
+}
+
+// parsePage reads the pagination options for a list ordered by sort. An
+// out-of-range page or pageSize is an error, not a clamp, so a client
+// never gets a page it did not ask for.
+func parsePage(c *fiber.Ctx, sort repository.Sort) (pageParams, error) {
+	p := pageParams{
+		page:         c.QueryInt("page", 1),
+		pageSize:     c.QueryInt("pageSize", 20),
+		includeTotal: c.QueryBool("includeTotal", true),
+	}
+	if p.page < 1 {
+		return p, fmt.Errorf("page must be at least 1")
+	}
+	if p.pageSize < 1 || p.pageSize > maxPageLimit {
+		return p, fmt.Errorf("pageSize must be between 1 and %d", maxPageLimit)
+	}
+	cursor := c.Query("cursor")
+	if cursor == "" && c.Query("limit") == "" {
+		return p, nil
+	}
+	p.keyset = true
+	p.limit = min(max(c.QueryInt("limit", 20), 1), maxPageLimit)
+	if cursor != "" {
+		var err error
+		if p.after, err = repository.DecodeCursor(cursor, sort); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// query copies the pagination into q. Keyset mode asks for one extra row
+// to learn whether another page follows.
+func (p pageParams) query(q repository.StatsQuery) repository.StatsQuery {
+	q.SkipCount = !p.includeTotal
+	q.Limit, q.Offset, q.After = p.window()
+	return q
+}
+
+// careerQuery copies the pagination into a career query; the caller
+// counts separately when p.includeTotal is set.
+func (p pageParams) careerQuery(q repository.CareerQuery) repository.CareerQuery {
+	q.Limit, q.Offset, q.After = p.window()
+	return q
+}
+
+// window is the page's row limit, offset and keyset start.
+func (p pageParams) window() (limit, offset int, after *repository.Cursor) {
+	if p.keyset {
+		return p.limit + 1, 0, p.after
+	}
+	return p.pageSize, (p.page - 1) * p.pageSize, nil
+}
+
+// finish trims the look-ahead row and returns the rows with the response's
+// "pagination" object.
+func finish[T any](p pageParams, rows []T, total int64, sort repository.Sort) ([]T, fiber.Map, error) {
+	if !p.keyset {
+		pg := fiber.Map{"page": p.page, "pageSize": p.pageSize}
+		if p.includeTotal {
+			pg["total"] = total
+			pg["pages"] = (total + int64(p.pageSize) - 1) / int64(p.pageSize)
+		}
+		return rows, pg, nil
+	}
+
+	pg := fiber.Map{"limit": p.limit, "nextCursor": nil}
+	if p.includeTotal {
+		pg["total"] = total
+	}
+	if len(rows) > p.limit {
+		rows = rows[:p.limit]
+		next, err := repository.NextCursor(&rows[len(rows)-1], sort)
+		if err != nil {
+			return nil, nil, err
+		}
+		pg["nextCursor"] = next
+	}
+	return rows, pg, nil
+}
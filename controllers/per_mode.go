@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+// perMode rescales the totals' counting stats: each is multiplied by
+// scale and divided by the per column. The zero value is plain totals.
+type perMode struct {
+	name  string
+	per   string // "games" or "minutes_pg" (which holds season minutes)
+	scale float64
+}
+
+var perModes = []perMode{
+	{name: "Totals"},
+	{name: "PerGame", per: "games", scale: 1},
+	{name: "Per36", per: "minutes_pg", scale: 36},
+	{name: "Per48", per: "minutes_pg", scale: 48},
+}
+
+// countingStats are the totals fields a perMode rescales; percentages,
+// games and ages stay as stored.
+var countingStats = []string{
+	"fieldGoals", "fieldAttempts", "threeFg", "threeAttempts", "twoFg", "twoAttempts",
+	"ft", "ftAttempts", "offensiveRb", "defensiveRb", "totalRb",
+	"assists", "steals", "blocks", "turnovers", "personalFouls", "points",
+}
+
+// parsePerMode reads perMode=, ignoring case; "" means Totals.
+func parsePerMode(s string) (perMode, error) {
+	if s == "" {
+		return perModes[0], nil
+	}
+	names := make([]string, len(perModes))
+	for i, m := range perModes {
+		if strings.EqualFold(s, m.name) {
+			return m, nil
+		}
+		names[i] = m.name
+	}
+	return perMode{}, fmt.Errorf("invalid perMode %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// sort orders by the rate instead of the total when s is on a stat apply
+// rescales: a counting stat, or minutesPg per game.
+func (m perMode) sort(s repository.Sort) repository.Sort {
+	if m.per == "" {
+		return s
+	}
+	rated := countingStats
+	if m.per == "games" {
+		rated = append(rated[:len(rated):len(rated)], "minutesPg")
+	}
+	for _, name := range rated {
+		if f, _ := totalFields.Lookup(name); f.Column == s.Column {
+			s.Per, s.Scale = m.per, m.scale
+			break
+		}
+	}
+	return s
+}
+
+// columns adds what the rates are computed from to a sparse projection.
+func (m perMode) columns(cols []string) []string {
+	if m.per == "" || cols == nil {
+		return cols
+	}
+	return append(cols, "games", "minutes_pg")
+}
+
+// apply rewrites the counting stats in rows, the shaped form of stats,
+// rounded to one decimal. Per game, minutesPg becomes minutes per game;
+// per 36 and 48 it stays the season's minutes.
+func (m perMode) apply(rows []map[string]interface{}, stats []models.PlayerTotalStat) {
+	if m.per == "" {
+		return
+	}
+	for i := range stats {
+		s := &stats[i]
+		per := float64(s.Games)
+		if m.per == "minutes_pg" {
+			per = s.MinutesPG
+		}
+		rate := func(v float64) float64 {
+			if per == 0 {
+				return 0
+			}
+			return math.Round(v*m.scale/per*10) / 10
+		}
+		for _, name := range countingStats {
+			if v, ok := rows[i][name]; ok {
+				rows[i][name] = rate(services.NumberValue(v))
+			}
+		}
+		if _, ok := rows[i]["minutesPg"]; ok && m.per == "games" {
+			rows[i]["minutesPg"] = rate(s.MinutesPG)
+		}
+	}
+}
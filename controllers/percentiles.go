@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// attachPercentiles adds "percentiles" and "positionPercentiles" to each
+// shaped row, ids[i] being rows[i]'s record ID. Only the stats a row
+// carries are kept, so fields= narrows them too; both are null until the
+// season's percentiles have been computed.
+func attachPercentiles(rows []map[string]interface{}, ids []uint, dataset string, repo repository.PercentileRepository) error {
+	found, err := repo.ListByRecords(dataset, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.PlayerStatPercentile, len(found))
+	for i := range found {
+		byID[found[i].RecordID] = &found[i]
+	}
+	for i, row := range rows {
+		p := byID[ids[i]]
+		if p == nil {
+			row["percentiles"], row["positionPercentiles"] = nil, nil
+			continue
+		}
+		row["percentiles"] = percentilesIn(p.Percentiles, row)
+		row["positionPercentiles"] = percentilesIn(p.PositionPercentiles, row)
+	}
+	return nil
+}
+
+// percentilesIn keeps the percentiles of the stats present in row.
+func percentilesIn(pcts map[string]float64, row map[string]interface{}) map[string]float64 {
+	if pcts == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(pcts))
+	for stat, p := range pcts {
+		if _, ok := row[stat]; ok {
+			out[stat] = p
+		}
+	}
+	return out
+}
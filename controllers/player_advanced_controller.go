@@ -5,6 +5,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"github.com/nprasad2077/NBA_Go/services"
 )
 
@@ -17,15 +18,20 @@ var advancedSortMap = map[string]string{
     "team":        "team",
 }
 
+// advancedFields whitelists the fields usable in filter expressions.
+var advancedFields = repository.ModelFields(&models.PlayerAdvancedStat{})
+
 // AdvancedStatsResponse is the swagger response model for GetAllAdvancedPlayerStats
 // It wraps the returned player advanced stats and pagination metadata.
 type AdvancedStatsResponse struct {
 	Data       []models.PlayerAdvancedStat `json:"data"`
 	Pagination struct {
-		Total    int64 `json:"total"`
-		Page     int   `json:"page"`
-		PageSize int   `json:"pageSize"`
-		Pages    int64 `json:"pages"`
+		Total      int64   `json:"total"`
+		Page       int     `json:"page"`
+		PageSize   int     `json:"pageSize"`
+		Pages      int64   `json:"pages"`
+		Limit      int     `json:"limit"`      // cursor mode only
+		NextCursor *string `json:"nextCursor"` // cursor mode only; null on the last page
 	} `json:"pagination"`
 }
 
@@ -48,8 +54,10 @@ type AdvancedStatsResponse struct {
 // @ignore
 // @Summary     Scrape player advanced stats from BR website
 // @Tags        PlayerStats
-// @Param       season    query  int  true  "Season (e.g. 2025)"
-// @Param       isPlayoff query  bool false "Whether playoffs?"
+// @Param       season    query  int    true  "Season (e.g. 2025)"
+// @Param       isPlayoff query  bool   false "Whether playoffs?"
+// @Param       provider  query  string false "Data source: br (default) or nba"
+// @Param       league    query  string false "League: NBA (default), WNBA or ABA"
 // @Success     200       {object} map[string]string
 // @Failure     400,500   {object} map[string]string
 // //@Router      /api/playeradvancedstats/scrape [get]
@@ -60,8 +68,16 @@ func ScrapePlayerAdvancedStats(db *gorm.DB) fiber.Handler {
 			return c.Status(400).JSON(fiber.Map{"error": "season is required"})
 		}
 		isPlayoff := c.QueryBool("isPlayoff", false)
+		provider, err := services.ProviderByName(c.Query("provider"))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
 
-		if err := services.FetchAndStorePlayerAdvancedScrapedStats(db, season, isPlayoff); err != nil {
+		if err := services.ImportPlayerAdvanced(db, provider, league, season, isPlayoff); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.JSON(fiber.Map{"message": "scrape+store complete"})
@@ -77,19 +93,27 @@ func ScrapePlayerAdvancedStats(db *gorm.DB) fiber.Handler {
 // @Produce     json
 // @Param       season     query  int     false  "Season (e.g., 2025)"
 // @Param       team       query  string  false  "Team abbreviation (e.g., MIL)"
-// @Param       playerId   query  string  false  "Player ID (e.g., greenaj01)"
+// @Param       playerId   query  string  false  "Player ID, BR or NBA.com (e.g., greenaj01)"
 // @Param       page       query  int     false  "Page number"       default(1)
 // @Param       pageSize   query  int     false  "Page size"         default(20)
+// @Param       cursor     query  string  false  "Keyset pagination: nextCursor from the previous page; must be used with the same sortBy and ascending"
+// @Param       limit      query  int     false  "Keyset pagination: rows per page (max 1000); switches from page/pageSize to cursor mode"  default(20)
+// @Param       includeTotal query bool   false  "Count the total matches (skip it for faster pages)"  default(true)
 // @Param       sortBy     query  string  false  "Field to sort by"  default(winShares)
 // @Param       ascending  query  bool    false  "Sort ascending"    default(false)
 // @Param       isPlayoff  query  bool    false  "Whether playoffs?"
+// @Param       teamMode   query  string  false  "Traded players: combined, split or both"  Enums(combined, split, both)  default(combined)
+// @Param       league     query  string  false  "League"  Enums(NBA, WNBA, ABA)  default(NBA)
+// @Param       fields     query  string  false  "Comma-separated fields to return (e.g. playerName,team,per,vorp)"
+// @Param       include    query  string  false  "Attach related rows: totals (the matching season totals row)"  Enums(totals)
+// @Param       filter     query  string  false  "Filter expression: field:op:value clauses, comma-separated; ops eq, ne, gt, gte, lt, lte, between (two values), in (|-separated). E.g. per:gte:20,games:gte:50,age:lt:25"
+// @Param       withPercentiles query bool false "Attach each row's within-season percentiles (overall and by position)"
 // @Success     200        {object} controllers.AdvancedStatsResponse
+// @Failure     400        {object} map[string]string
 // @Failure     500        {object} map[string]string
 // @Router      /api/playeradvancedstats [get]
-func GetAllAdvancedPlayerStats(db *gorm.DB) fiber.Handler {
+func GetAllAdvancedPlayerStats(advanced repository.AdvancedStatsRepository, totals repository.TotalStatsRepository, percentiles repository.PercentileRepository, ids repository.PlayerIDResolver) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var stats []models.PlayerAdvancedStat
-
 		// --- MODIFICATION FOR FILTERS ---
         // Allow both "playerId" and "player_id"
         playerId := c.Query("playerId")
@@ -102,11 +126,6 @@ func GetAllAdvancedPlayerStats(db *gorm.DB) fiber.Handler {
 		team := c.Query("team")
 		// playerId := c.Query("playerId")
 
-		// Pagination
-		page := c.QueryInt("page", 1)
-		pageSize := c.QueryInt("pageSize", 20)
-		offset := (page - 1) * pageSize
-
 		// --- MODIFICATION FOR SORTING ---
         // Sorting
         sortByParam := c.Query("sortBy", "winShares") // Default to a common field
@@ -118,46 +137,95 @@ func GetAllAdvancedPlayerStats(db *gorm.DB) fiber.Handler {
             sortBy = "win_shares" // Safe default
         }
 
-        order := sortBy + " DESC"
-        if ascending {
-            order = sortBy + " ASC"
-        }
+		// Pagination
+		sort := repository.Sort{Column: sortBy, Ascending: ascending}
+		paging, err := parsePage(c, sort)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
 
-		// Build query
-		query := db.Model(&models.PlayerAdvancedStat{})
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		filter := repository.StatsFilter{League: league, Season: season, Team: team}
+
+		if playerId != "" {
+			if filter.PlayerIDs, err = ids.ResolvePlayerIDs(playerId); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
 
-		if season != 0 {
-			query = query.Where("season = ?", season)
+		filter.Conditions, err = repository.ParseFilter(c.Query("filter"), advancedFields)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
-		if team != "" {
-			query = query.Where("team = ?", team)
+		filter.TeamMode, err = repository.ParseTeamMode(c.Query("teamMode"), team, filter.Conditions)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
-		if playerId != "" {
-			query = query.Where("player_id = ?", playerId)
+
+		sparse, err := parseSparse(c, advancedFields, "totals")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		if c.Query("isPlayoff") != "" {
 			isPlayoff := c.QueryBool("isPlayoff", false)
-			query = query.Where("is_playoff = ?", isPlayoff)
+			filter.IsPlayoff = &isPlayoff
 		}
 
-		// Count total
-		var total int64
-		query.Count(&total)
-
-		// Fetch page
-		err := query.Order(order).Limit(pageSize).Offset(offset).Find(&stats).Error
+		stats, total, err := advanced.List(paging.query(repository.StatsQuery{
+			Filter:  filter,
+			Sort:    sort,
+			Columns: sparse.columns(),
+		}))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		stats, pagination, err := finish(paging, stats, total, sort)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		// Build response
-		resp := AdvancedStatsResponse{Data: stats}
-		resp.Pagination.Total = total
-		resp.Pagination.Page = page
-		resp.Pagination.PageSize = pageSize
-		resp.Pagination.Pages = (total + int64(pageSize) - 1) / int64(pageSize)
+		var data interface{} = stats
+		withPercentiles := c.QueryBool("withPercentiles", false)
+		if sparse.active() || withPercentiles {
+			rows, err := shapeAdvanced(stats, sparse, totals)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			if withPercentiles {
+				ids := make([]uint, len(stats))
+				for i := range stats {
+					ids[i] = stats[i].ID
+				}
+				if err := attachPercentiles(rows, ids, services.DatasetAdvanced, percentiles); err != nil {
+					return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				}
+			}
+			data = rows
+		}
+		return c.JSON(fiber.Map{"data": data, "pagination": pagination})
+	}
+}
 
-		return c.JSON(resp)
+// shapeAdvanced applies fields= and include=totals to a page of advanced stats.
+func shapeAdvanced(stats []models.PlayerAdvancedStat, p sparseParams, totals repository.TotalStatsRepository) ([]map[string]interface{}, error) {
+	rows, err := project(stats, p, advancedFields)
+	if err != nil || !p.includes("totals") {
+		return rows, err
+	}
+	related, err := totals.ListByKeys(seasonKeysOf(stats, repository.AdvancedSeasonKey))
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[repository.SeasonKey]*models.PlayerTotalStat, len(related))
+	for i := range related {
+		byKey[repository.TotalSeasonKey(&related[i])] = &related[i]
+	}
+	for i := range stats {
+		rows[i]["totals"] = byKey[repository.AdvancedSeasonKey(&stats[i])]
 	}
+	return rows, nil
 }
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+var careerSortMap = map[string]string{
+	"points":          "points",
+	"assists":         "assists",
+	"totalRb":         "total_rb",
+	"steals":          "steals",
+	"blocks":          "blocks",
+	"games":           "games",
+	"minutes":         "minutes",
+	"seasons":         "seasons",
+	"threeFg":         "three_fg",
+	"fieldPercent":    "field_percent",
+	"threePercent":    "three_percent",
+	"ftPercent":       "ft_percent",
+	"effectFgPercent": "effect_fg_percent",
+	"playerId":        "player_id",
+}
+
+// PlayerCareerResponse is a player's regular-season and playoff career totals.
+type PlayerCareerResponse struct {
+	PlayerID      string                   `json:"playerId"`
+	RegularSeason *models.PlayerCareerStat `json:"regularSeason"`
+	Playoffs      *models.PlayerCareerStat `json:"playoffs"`
+}
+
+// GetPlayerCareer godoc
+// @Summary Get a player's career totals
+// @Description Regular-season and playoff career totals, with traded seasons counted once and percentages recomputed from the summed totals
+// @Tags Players
+// @Produce  json
+// @Param id path string true "Player ID, BR or NBA.com (e.g. jamesle01)"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Success 200 {object} PlayerCareerResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/players/{id}/career [get]
+func GetPlayerCareer(careers repository.CareerStatsRepository, playerIDs repository.PlayerIDResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		ids, err := playerIDs.ResolvePlayerIDs(c.Params("id"))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// Careers are stored per provider ID; use the first ID that has one.
+		for _, id := range ids {
+			resp := PlayerCareerResponse{PlayerID: id}
+			for _, isPlayoff := range []bool{false, true} {
+				rows, err := careers.List(repository.CareerQuery{League: league, IsPlayoff: isPlayoff, PlayerIDs: []string{id}})
+				if err != nil {
+					return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				}
+				if len(rows) == 0 {
+					continue
+				}
+				if isPlayoff {
+					resp.Playoffs = &rows[0]
+				} else {
+					resp.RegularSeason = &rows[0]
+				}
+			}
+			if resp.RegularSeason != nil || resp.Playoffs != nil {
+				return c.JSON(resp)
+			}
+		}
+		return c.Status(404).JSON(fiber.Map{"error": "no career stats for player"})
+	}
+}
+
+// GetCareerLeaders godoc
+// @Summary Career leaderboard
+// @Description Sort and paginate career totals
+// @Tags Players
+// @Produce  json
+// @Param sortBy query string false "Field to sort by (e.g. points, assists, threePercent)" default(points)
+// @Param ascending query bool false "Sort ascending (default false)"
+// @Param isPlayoff query bool false "Playoff careers instead of regular season"
+// @Param minGames query int false "Only careers with at least this many games"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Param cursor query string false "Keyset pagination: nextCursor from the previous page; must be used with the same sortBy and ascending"
+// @Param limit query int false "Keyset pagination: rows per page (max 1000); switches from page/pageSize to cursor mode" default(20)
+// @Param includeTotal query bool false "Count the total matches (skip it for faster pages)" default(true)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/players/career [get]
+func GetCareerLeaders(careers repository.CareerStatsRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		sortBy, ok := careerSortMap[c.Query("sortBy", "points")]
+		if !ok {
+			return c.Status(400).JSON(fiber.Map{"error": "invalid sortBy " + c.Query("sortBy")})
+		}
+		// The repository breaks ties by ID, so pages are stable.
+		sort := repository.Sort{Column: sortBy, Ascending: c.QueryBool("ascending", false)}
+		paging, err := parsePage(c, sort)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		q := repository.CareerQuery{League: league, IsPlayoff: c.QueryBool("isPlayoff", false)}
+		if minGames := c.QueryInt("minGames", 0); minGames > 0 {
+			q.Conditions = []repository.Condition{{Column: "games", Op: repository.OpGte, Values: []interface{}{minGames}}}
+		}
+
+		var total int64
+		if paging.includeTotal {
+			if total, err = careers.Count(q); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+		q.Sort = sort
+		stats, err := careers.List(paging.careerQuery(q))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		stats, pagination, err := finish(paging, stats, total, sort)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"data": stats, "pagination": pagination})
+	}
+}
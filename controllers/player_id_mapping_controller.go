@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/services"
+	"gorm.io/gorm"
+)
+
+// RegisterPlayerIDMappingRoutes exposes the BR ↔ NBA.com ID mapping and its
+// review queue behind the admin secret.
+func RegisterPlayerIDMappingRoutes(app *fiber.App, db *gorm.DB) {
+	admin := app.Group("/admin/playerids", adminGuard())
+
+	admin.Get("/", ListPlayerIDMappings(db))
+	admin.Post("/import", ImportPlayerIDMappings(db))
+	admin.Post("/infer", InferPlayerIDMappings(db))
+	admin.Post("/:id/approve", ReviewPlayerIDMapping(db, true))
+	admin.Post("/:id/reject", ReviewPlayerIDMapping(db, false))
+}
+
+// ListPlayerIDMappings godoc
+// @ignore
+// @Summary     List player ID mappings
+// @Description Use status=pending for the review queue.
+// @Tags        Admin
+// @Produce     json
+// @Param       status  query  string  false  "confirmed, pending or rejected"
+// @Param       limit   query  int     false  "Max rows returned"  default(500)
+// @Success     200     {array}  map[string]interface{}
+// @Failure     400,500 {object} map[string]string
+// //@Router      /admin/playerids [get]
+func ListPlayerIDMappings(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status := c.Query("status")
+		switch status {
+		case "", models.MappingConfirmed, models.MappingPending, models.MappingRejected:
+		default:
+			return c.Status(400).JSON(fiber.Map{"error": "invalid status: " + status})
+		}
+		mappings, err := services.ListPlayerIDMappings(db, status, c.QueryInt("limit", 500))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(mappings)
+	}
+}
+
+// ImportPlayerIDMappings godoc
+// @ignore
+// @Summary     Import confirmed mappings from CSV
+// @Description Body is CSV with a header row: br_id,nba_id[,name,birth_date].
+// @Tags        Admin
+// @Accept      text/csv
+// @Produce     json
+// @Success     200     {object} map[string]interface{}
+// @Failure     400     {object} map[string]string
+// //@Router      /admin/playerids/import [post]
+func ImportPlayerIDMappings(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		n, err := services.ImportPlayerIDMappingsCSV(db, bytes.NewReader(c.Body()))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"imported": n})
+	}
+}
+
+// InferPlayerIDMappings godoc
+// @ignore
+// @Summary     Infer mappings from stored totals
+// @Tags        Admin
+// @Produce     json
+// @Success     200     {object} map[string]interface{}
+// @Failure     500     {object} map[string]string
+// //@Router      /admin/playerids/infer [post]
+func InferPlayerIDMappings(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := services.InferPlayerIDMappings(db)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(result)
+	}
+}
+
+// ReviewPlayerIDMapping godoc
+// @ignore
+// @Summary     Approve or reject a queued mapping
+// @Tags        Admin
+// @Produce     json
+// @Param       id          path   int  true  "Mapping ID"
+// @Success     200         {object} map[string]interface{}
+// @Failure     400,404,409 {object} map[string]string
+// //@Router      /admin/playerids/{id}/approve [post]
+// //@Router      /admin/playerids/{id}/reject [post]
+func ReviewPlayerIDMapping(db *gorm.DB, approve bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "invalid id"})
+		}
+		mapping, err := services.ReviewPlayerIDMapping(db, uint(id), approve)
+		switch {
+		case errors.Is(err, services.ErrMappingNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, services.ErrMappingConflict):
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		case err != nil:
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(mapping)
+	}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+// ProfileSeason is one (season, team, season type) of a player's profile:
+// the totals and advanced rows stored under it, either of which may be
+// missing.
+type ProfileSeason struct {
+	Season      int                        `json:"season"`
+	Team        string                     `json:"team"`
+	IsPlayoff   bool                       `json:"isPlayoff"`
+	IsAggregate bool                       `json:"isAggregate"`
+	Totals      *models.PlayerTotalStat    `json:"totals"`
+	Advanced    *models.PlayerAdvancedStat `json:"advanced"`
+}
+
+// PlayerProfileResponse is everything a player page needs in one response.
+type PlayerProfileResponse struct {
+	PlayerID   string                       `json:"playerId"`
+	PlayerName string                       `json:"playerName"`
+	Seasons    []ProfileSeason              `json:"seasons"`
+	Shots      []services.ShotSeasonSummary `json:"shots"`
+}
+
+// GetPlayerProfile godoc
+// //@Security ApiKeyAuth
+// @Summary Get a player's profile
+// @Description Season-by-season totals and advanced stats merged per (season, team, season type),
+// @Description traded seasons' aggregate rows included, plus a per-season shot summary by distance band.
+// @Tags Players
+// @Produce  json
+// @Param id path string true "Player ID, BR or NBA.com (e.g. jamesle01)"
+// @Param fromSeason query int false "First season to include (e.g. 2015)"
+// @Param toSeason query int false "Last season to include (e.g. 2024)"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Success 200 {object} PlayerProfileResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/players/{id}/profile [get]
+func GetPlayerProfile(totals repository.TotalStatsRepository, advanced repository.AdvancedStatsRepository, shots repository.ShotChartRepository, ids repository.PlayerIDResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		from, to := c.QueryInt("fromSeason", 0), c.QueryInt("toSeason", 0)
+		if from != 0 && to != 0 && from > to {
+			return c.Status(400).JSON(fiber.Map{"error": "fromSeason must be <= toSeason"})
+		}
+		playerIDs, err := ids.ResolvePlayerIDs(c.Params("id"))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		filter := repository.StatsFilter{League: league, PlayerIDs: playerIDs, TeamMode: repository.TeamModeBoth}
+		if from != 0 {
+			filter.Conditions = append(filter.Conditions, repository.Condition{Column: "season", Op: repository.OpGte, Values: []interface{}{from}})
+		}
+		if to != 0 {
+			filter.Conditions = append(filter.Conditions, repository.Condition{Column: "season", Op: repository.OpLte, Values: []interface{}{to}})
+		}
+		q := repository.StatsQuery{Filter: filter, Sort: repository.Sort{Column: "season", Ascending: true}, SkipCount: true}
+
+		totalRows, _, err := totals.List(q)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		advancedRows, _, err := advanced.List(q)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		shotTallies, err := shots.Tally(
+			repository.ShotFilter{League: league, PlayerIDs: playerIDs, FromSeason: from, ToSeason: to},
+			services.ShotSummaryGroups,
+		)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if len(totalRows) == 0 && len(advancedRows) == 0 && len(shotTallies) == 0 {
+			return c.Status(404).JSON(fiber.Map{"error": "no stats for player"})
+		}
+
+		resp := PlayerProfileResponse{
+			PlayerID: c.Params("id"),
+			Seasons:  mergeProfileSeasons(totalRows, advancedRows),
+			Shots:    services.SummarizeShotSeasons(shotTallies),
+		}
+		for _, s := range resp.Seasons {
+			if s.Totals != nil && s.Totals.PlayerName != "" {
+				resp.PlayerName = s.Totals.PlayerName
+			} else if s.Advanced != nil && s.Advanced.PlayerName != "" {
+				resp.PlayerName = s.Advanced.PlayerName
+			}
+		}
+		return c.JSON(resp)
+	}
+}
+
+// mergeProfileSeasons pairs totals and advanced rows on season, team and
+// season type. Rows stored under another ID style of the same player merge
+// too; the first row seen for a key wins. The result is ordered by season,
+// regular season first, the traded-season aggregate before its teams.
+func mergeProfileSeasons(totals []models.PlayerTotalStat, advanced []models.PlayerAdvancedStat) []ProfileSeason {
+	type key struct {
+		season    int
+		team      string
+		isPlayoff bool
+	}
+	byKey := map[key]*ProfileSeason{}
+	var seasons []*ProfileSeason
+	at := func(k key, isAggregate bool) *ProfileSeason {
+		if s, ok := byKey[k]; ok {
+			return s
+		}
+		s := &ProfileSeason{Season: k.season, Team: k.team, IsPlayoff: k.isPlayoff, IsAggregate: isAggregate}
+		byKey[k] = s
+		seasons = append(seasons, s)
+		return s
+	}
+	for i := range totals {
+		t := &totals[i]
+		if s := at(key{t.Season, t.Team, t.IsPlayoff}, t.IsAggregate); s.Totals == nil {
+			s.Totals = t
+		}
+	}
+	for i := range advanced {
+		a := &advanced[i]
+		if s := at(key{a.Season, a.Team, a.IsPlayoff}, a.IsAggregate); s.Advanced == nil {
+			s.Advanced = a
+		}
+	}
+
+	out := make([]ProfileSeason, len(seasons))
+	for i, s := range seasons {
+		out[i] = *s
+	}
+	slices.SortFunc(out, func(a, b ProfileSeason) int {
+		return cmp.Or(
+			cmp.Compare(a.Season, b.Season),
+			services.CmpBool(a.IsPlayoff, b.IsPlayoff),
+			services.CmpBool(b.IsAggregate, a.IsAggregate),
+			cmp.Compare(a.Team, b.Team),
+		)
+	})
+	return out
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+// maxSearchResults caps the limit parameter of the player search.
+const maxSearchResults = 50
+
+// SearchPlayers godoc
+// @Summary Search players by name
+// @Description Accent- and case-insensitive name search with prefix and typo tolerance, ranked by relevance and career minutes. The returned playerId works with every other endpoint.
+// @Tags Players
+// @Produce  json
+// @Param q query string true "Name or part of a name (e.g. jokic, lebron, doncic)"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Param limit query int false "Maximum results (at most 50)" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/players/search [get]
+func SearchPlayers(careers repository.CareerStatsRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		limit := c.QueryInt("limit", 10)
+		if limit < 1 {
+			limit = 10
+		}
+		limit = min(limit, maxSearchResults)
+
+		query := services.FoldName(c.Query("q"))
+		if query == "" {
+			return c.Status(400).JSON(fiber.Map{"error": "q is required"})
+		}
+		rows, err := careers.SearchCandidates(league, query)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"data": services.RankPlayerSearch(query, league, rows, limit)})
+	}
+}
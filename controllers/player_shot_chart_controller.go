@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"github.com/nprasad2077/NBA_Go/services"
 	"gorm.io/gorm"
 )
@@ -39,9 +42,10 @@ import (
 // @Tags        PlayerShotChart
 // @Accept      json
 // @Produce     json
-// @Param       playerId    query  string true  "Player ID (e.g. derozde01)"
+// @Param       playerId    query  string true  "Player ID, BR or NBA.com (e.g. derozde01)"
 // @Param       startSeason query  int    true  "Start season (e.g. 2024)"
 // @Param       endSeason   query  int    true  "End season (e.g. 2021)"
+// @Param       provider    query  string false "Data source: br (default) or nba"
 // @Success     200         {object} map[string]string
 // @Failure     400,500     {object} map[string]string
 // //@Router      /api/playershotchart/scrape [get]
@@ -60,41 +64,216 @@ func ScrapePlayerShotChart(db *gorm.DB) fiber.Handler {
 				"error": "startSeason must be >= endSeason",
 			})
 		}
-		if err := services.FetchAndStoreShotChartScrapedForPlayer(db, pid, start, end); err != nil {
+		provider, err := services.ProviderByName(c.Query("provider"))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		// Accept either ID style; scrape under the provider's own ID.
+		pid, err = services.PlayerIDForProvider(db, pid, provider)
+		if errors.Is(err, services.ErrUnmappedPlayerID) {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		} else if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err := services.ImportShotChart(db, provider, pid, start, end); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.JSON(fiber.Map{"message": "Shot chart scraped and saved for " + pid})
 	}
 }
 
+// GetShotChartImportStatus godoc
+// @ignore
+// @Summary     Progress of a season-wide shot chart import
+// @Description Player counts per import status (done, failed, pending) for one season.
+// @Tags        PlayerShotChart
+// @Produce     json
+// @Param       season  query  int  true  "Season (e.g. 2024)"
+// @Success     200     {object} map[string]interface{}
+// @Failure     400,500 {object} map[string]string
+// //@Router      /api/playershotchart/import/status [get]
+func GetShotChartImportStatus(db *gorm.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		season := c.QueryInt("season", 0)
+		if season == 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "season is required"})
+		}
+		progress, err := services.GetSeasonShotChartProgress(db, season)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(progress)
+	}
+}
+
+// shotSort is the keyset order of shot pages: by primary key.
+var shotSort = repository.Sort{Ascending: true}
+
+// ShotChartResponse is the swagger response model for GetPlayerShotChart.
+type ShotChartResponse struct {
+	Data       []models.PlayerShotChart `json:"data"`
+	Pagination struct {
+		Total      int64   `json:"total"`
+		Page       int     `json:"page"`
+		PageSize   int     `json:"pageSize"`
+		Pages      int64   `json:"pages"`
+		Limit      int     `json:"limit"`      // cursor mode only
+		NextCursor *string `json:"nextCursor"` // cursor mode only; null on the last page
+	} `json:"pagination"`
+}
+
 // GetPlayerShotChart godoc
 // //@Security    ApiKeyAuth
 // @Summary     Get shot-chart data
-// @Description Returns shot-chart points, optionally filtered by playerId and/or season
+// @Description Returns shot-chart points matching the filters as {data, pagination}, paged
+// @Description like the other list endpoints (pageSize 20 by default, at most 1000).
 // @Tags        PlayerShotChart
 // @Accept      json
 // @Produce     json
-// @Param       playerId query  string false "Player ID (e.g., hardeja01)"
-// @Param       season   query  int    false "Season (e.g., 2023)"
-// @Success     200      {array}  models.PlayerShotChart
+// @Param       playerId    query  string false "Player ID, BR or NBA.com (e.g., hardeja01 or 201935)"
+// @Param       season      query  int    false "Season (e.g., 2023)"
+// @Param       isPlayoff   query  bool   false "Playoff (true) or regular-season (false) shots only"
+// @Param       dateFrom    query  string false "First game date, YYYY-MM-DD"
+// @Param       dateTo      query  string false "Last game date, YYYY-MM-DD"
+// @Param       quarter     query  string false "Comma-separated quarters 1-4 and/or OT (every overtime), e.g. 4,OT"
+// @Param       result      query  string false "made or missed" Enums(made, missed)
+// @Param       shotType    query  string false "2 or 3 pointers" Enums(2, 3)
+// @Param       minDistance query  int    false "Min shot distance in feet"
+// @Param       maxDistance query  int    false "Max shot distance in feet"
+// @Param       team        query  string false "Shooter's team (e.g. GSW)"
+// @Param       opponent    query  string false "Opponent (e.g. LAL)"
+// @Param       scoreState  query  string false "Shooting team's score state (BR shots only; NBA.com shots have no running score)" Enums(leading, trailing, tied)
+// @Param       minMargin   query  int    false "Min score margin, team minus opponent (negative = trailing; BR shots only)"
+// @Param       maxMargin   query  int    false "Max score margin, team minus opponent (BR shots only)"
+// @Param       zone        query  string false "Comma-separated zones (restricted_area, paint_non_ra, mid_range, left_corner_3, right_corner_3, above_break_3, backcourt)"
+// @Param       minX        query  number false "Min feet right of the basket (negative = left)"
+// @Param       maxX        query  number false "Max feet right of the basket"
+// @Param       minY        query  number false "Min feet out from the basket"
+// @Param       maxY        query  number false "Max feet out from the basket"
+// @Param       league      query  string false "League (shot charts are NBA-only)" default(NBA)
+// @Param       page        query  int    false "Page number"
+// @Param       pageSize    query  int    false "Page size" default(20)
+// @Param       cursor      query  string false "Keyset pagination: nextCursor from the previous page"
+// @Param       limit       query  int    false "Keyset pagination: shots per page (max 1000)"
+// @Param       includeTotal query bool   false "Count the total matches" default(true)
+// @Success     200      {object} controllers.ShotChartResponse
+// @Failure     400      {object} map[string]string
 // @Failure     500      {object} map[string]string
 // @Router      /api/playershotchart [get]
-func GetPlayerShotChart(db *gorm.DB) fiber.Handler {
+func GetPlayerShotChart(shots repository.ShotChartRepository, ids repository.PlayerIDResolver) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var shots []models.PlayerShotChart
+		filter, err := parseShotFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		if pid := c.Query("playerId"); pid != "" {
+			if filter.PlayerIDs, err = ids.ResolvePlayerIDs(pid); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		paging, err := parsePage(c, shotSort)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		q := repository.ShotQuery{Filter: filter, SkipCount: !paging.includeTotal}
+		q.Limit, q.Offset, q.After = paging.window()
+		rows, total, err := shots.List(q)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		rows, pagination, err := finish(paging, rows, total, shotSort)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"data": rows, "pagination": pagination})
+	}
+}
 
-		query := db.Model(&models.PlayerShotChart{})
+// ShotAggregateResponse is the swagger response model for GetShotChartAggregate.
+type ShotAggregateResponse struct {
+	Bin    string             `json:"bin"`
+	Size   float64            `json:"size"`
+	Totals services.ShotBin   `json:"totals"`
+	Bins   []services.ShotBin `json:"bins"`
+}
 
-		if pid := c.Query("playerId"); pid != "" {
-			query = query.Where("player_id = ?", pid)
+// GetShotChartAggregate godoc
+// //@Security    ApiKeyAuth
+// @Summary     Binned shot-chart aggregates
+// @Description Groups the matching shots into hexagons or squares over the chart's Top/Left
+// @Description pixels (10 px to the foot), or into distance bands, and returns attempts,
+// @Description makes, FG% and points per shot per bin. Takes every /api/playershotchart
+// @Description filter; one of playerId, team or season is required. withLeague=true adds
+// @Description the league FG% in each bin, over the same filters minus playerId and team.
+// @Tags        PlayerShotChart
+// @Produce     json
+// @Param       playerId    query  string false "Player ID, BR or NBA.com"
+// @Param       team        query  string false "Shooter's team (e.g. GSW)"
+// @Param       season      query  int    false "Season (e.g., 2024)"
+// @Param       bin         query  string false "Bin kind" Enums(hex, grid, distance) default(hex)
+// @Param       size        query  number false "Hex radius or grid side in pixels, or band width in whole feet (defaults 15, 20, 3)"
+// @Param       minAttempts query  int    false "Drop bins with fewer attempts" default(1)
+// @Param       withLeague  query  bool   false "Add the league-average FG% per bin"
+// @Success     200         {object} controllers.ShotAggregateResponse
+// @Failure     400         {object} map[string]string
+// @Failure     500         {object} map[string]string
+// @Router      /api/playershotchart/aggregate [get]
+func GetShotChartAggregate(shots repository.ShotChartRepository, ids repository.PlayerIDResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter, err := parseShotFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		pid := c.Query("playerId")
+		if pid == "" && filter.Team == "" && filter.Season == 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "playerId, team or season is required"})
 		}
-		if s := c.QueryInt("season", 0); s != 0 {
-			query = query.Where("season = ?", s)
+		if pid != "" {
+			if filter.PlayerIDs, err = ids.ResolvePlayerIDs(pid); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
 		}
 
-		if err := query.Find(&shots).Error; err != nil {
+		kind := c.Query("bin", services.BinHex)
+		size := c.QueryFloat("size", services.DefaultBinSize[kind])
+		minAttempts := c.QueryInt("minAttempts", 1)
+		if err := services.CheckShotBin(kind, size); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// The database counts shots per spot (or distance); only those
+		// tallies are binned here.
+		tallies, err := shots.Tally(filter, services.ShotBinGroups[kind])
+		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
-		return c.JSON(shots)
+		bins, totals, err := services.BinShots(tallies, kind, size)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if c.QueryBool("withLeague", false) {
+			league := filter
+			league.PlayerIDs, league.Team = nil, ""
+			tallies, err := shots.Tally(league, services.ShotBinGroups[kind])
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			leagueBins, leagueTotals, err := services.BinShots(tallies, kind, size)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			services.AttachLeagueFG(bins, leagueBins)
+			totals.LeagueFGPercent = &leagueTotals.FGPercent
+		}
+
+		kept := bins[:0]
+		for _, b := range bins {
+			if b.Attempts >= minAttempts {
+				kept = append(kept, b)
+			}
+		}
+		return c.JSON(ShotAggregateResponse{Bin: kind, Size: size, Totals: totals, Bins: kept})
 	}
 }
@@ -15,6 +15,7 @@ package controllers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"github.com/nprasad2077/NBA_Go/services"
 	"gorm.io/gorm"
 )
@@ -23,7 +24,14 @@ var totalSortMap = map[string]string{
     "points":        "points",
     "assists":       "assists",
     "totalRb":       "total_rb",
+    "steals":        "steals",
+    "blocks":        "blocks",
+    "turnovers":     "turnovers",
+    "threeFg":       "three_fg",
+    "fieldGoals":    "field_goals",
+    "ft":            "ft",
     "gamesStarted":  "games_started",
+    "minutesPg":     "minutes_pg",
     "fieldPercent":  "field_percent",
     "threePercent":  "three_percent",
     "ftPercent":     "ft_percent",
@@ -32,6 +40,9 @@ var totalSortMap = map[string]string{
     "team":          "team",
 }
 
+// totalFields whitelists the fields usable in filter expressions.
+var totalFields = repository.ModelFields(&models.PlayerTotalStat{})
+
 // func FetchPlayerTotalStats(db *gorm.DB) fiber.Handler {
 // 	return func(c *fiber.Ctx) error {
 // 		season := c.QueryInt("season", 2025)
@@ -50,8 +61,10 @@ var totalSortMap = map[string]string{
 // @ignore
 // @Summary     Scrape player total stats from BR website
 // @Tags        PlayerTotals
-// @Param       season    query  int  true  "Season (e.g. 2025)"
-// @Param       isPlayoff query  bool false "Whether playoffs?"
+// @Param       season    query  int    true  "Season (e.g. 2025)"
+// @Param       isPlayoff query  bool   false "Whether playoffs?"
+// @Param       provider  query  string false "Data source: br (default) or nba"
+// @Param       league    query  string false "League: NBA (default), WNBA or ABA"
 // @Success     200       {object} map[string]string
 // @Failure     400,500   {object} map[string]string
 // //@Router      /api/playertotals/scrape [get]
@@ -62,8 +75,16 @@ func ScrapePlayerTotalStats(db *gorm.DB) fiber.Handler {
 			return c.Status(400).JSON(fiber.Map{"error": "season is required"})
 		}
 		isPlayoff := c.QueryBool("isPlayoff", false)
+		provider, err := services.ProviderByName(c.Query("provider"))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
 
-		if err := services.FetchAndStorePlayerTotalScrapedStats(db, season, isPlayoff); err != nil {
+		if err := services.ImportPlayerTotals(db, provider, league, season, isPlayoff); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.JSON(fiber.Map{"message": "scrapestore complete"})
@@ -79,19 +100,28 @@ func ScrapePlayerTotalStats(db *gorm.DB) fiber.Handler {
 // @Produce  json
 // @Param season query int false "Season (e.g. 2000)"
 // @Param team query string false "Team abbreviation (e.g. LAL)"
-// @Param playerId query string false "Player ID (e.g. greenac01)"
+// @Param playerId query string false "Player ID, BR or NBA.com (e.g. greenac01)"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(20)
+// @Param cursor query string false "Keyset pagination: nextCursor from the previous page; must be used with the same sortBy and ascending"
+// @Param limit query int false "Keyset pagination: rows per page (max 1000); switches from page/pageSize to cursor mode" default(20)
+// @Param includeTotal query bool false "Count the total matches (skip it for faster pages)" default(true)
 // @Param sortBy query string false "Field to sort by (e.g. points, assists)"
 // @Param ascending query bool false "Sort ascending (default false)"
 // @Param isPlayoff query bool false "Whether the stats are for playoffs"
+// @Param teamMode query string false "Traded players: combined (aggregate row only), split (per-team rows only) or both" Enums(combined, split, both) default(combined)
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Param fields query string false "Comma-separated fields to return (e.g. playerName,team,points)"
+// @Param include query string false "Attach related rows: advanced (the matching advanced stats row)" Enums(advanced)
+// @Param filter query string false "Filter expression: field:op:value clauses, comma-separated; ops eq, ne, gt, gte, lt, lte, between (two values), in (|-separated). E.g. points:gte:1500,season:between:2015:2024,team:in:LAL|BOS"
+// @Param withPercentiles query bool false "Attach each row's within-season percentiles (overall and by position) of the stored totals"
+// @Param perMode query string false "Counting stats as season totals, per game, per 36 or per 48 minutes; sortBy ranks the derived values" Enums(Totals, PerGame, Per36, Per48) default(Totals)
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/playertotals [get]
-func GetPlayerTotalStats(db *gorm.DB) fiber.Handler {
+func GetPlayerTotalStats(totals repository.TotalStatsRepository, advanced repository.AdvancedStatsRepository, percentiles repository.PercentileRepository, ids repository.PlayerIDResolver) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var stats []models.PlayerTotalStat
-
 		// --- MODIFICATION FOR FILTERS ---
         playerId := c.Query("playerId")
         if playerId == "" {
@@ -101,8 +131,6 @@ func GetPlayerTotalStats(db *gorm.DB) fiber.Handler {
 		season := c.QueryInt("season", 0)
 		team := c.Query("team")
 		// playerId := c.Query("playerId")
-		page := c.QueryInt("page", 1)
-		pageSize := c.QueryInt("pageSize", 20)
 
 		// --- MODIFICATION FOR SORTING ---
         sortByParam := c.Query("sortBy", "points")
@@ -114,46 +142,101 @@ func GetPlayerTotalStats(db *gorm.DB) fiber.Handler {
             sortBy = "points" // Safe default
         }
 
-        offset := (page - 1) * pageSize
-        order := sortBy + " DESC"
-        if ascending {
-            order = sortBy + " ASC"
-        }
+		mode, err := parsePerMode(c.Query("perMode"))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		sort := mode.sort(repository.Sort{Column: sortBy, Ascending: ascending})
+		paging, err := parsePage(c, sort)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
 
-		query := db.Model(&models.PlayerTotalStat{})
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		filter := repository.StatsFilter{League: league, Season: season, Team: team}
 
-		if season != 0 {
-			query = query.Where("season = ?", season)
+		if playerId != "" {
+			if filter.PlayerIDs, err = ids.ResolvePlayerIDs(playerId); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
 		}
-		if team != "" {
-			query = query.Where("team = ?", team)
+
+		filter.Conditions, err = repository.ParseFilter(c.Query("filter"), totalFields)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
-		if playerId != "" {
-			query = query.Where("player_id = ?", playerId)
+		filter.TeamMode, err = repository.ParseTeamMode(c.Query("teamMode"), team, filter.Conditions)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		sparse, err := parseSparse(c, totalFields, "advanced")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		isPlayoffStr := c.Query("isPlayoff")
 		if isPlayoffStr != "" {
 			isPlayoff := c.QueryBool("isPlayoff", false)
-			query = query.Where("is_playoff = ?", isPlayoff)
+			filter.IsPlayoff = &isPlayoff
 		}
 
-		var total int64
-		query.Count(&total)
-
-		err := query.Order(order).Limit(pageSize).Offset(offset).Find(&stats).Error
+		stats, total, err := totals.List(paging.query(repository.StatsQuery{
+			Filter:  filter,
+			Sort:    sort,
+			Columns: mode.columns(sparse.columns()),
+		}))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		stats, pagination, err := finish(paging, stats, total, sort)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		return c.JSON(fiber.Map{
-			"data": stats,
-			"pagination": fiber.Map{
-				"total":    total,
-				"page":     page,
-				"pageSize": pageSize,
-				"pages":    (total + int64(pageSize) - 1) / int64(pageSize),
-			},
-		})
+		var data interface{} = stats
+		withPercentiles := c.QueryBool("withPercentiles", false)
+		if sparse.active() || mode.per != "" || withPercentiles {
+			rows, err := shapeTotals(stats, sparse, advanced)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+			mode.apply(rows, stats)
+			if withPercentiles {
+				ids := make([]uint, len(stats))
+				for i := range stats {
+					ids[i] = stats[i].ID
+				}
+				if err := attachPercentiles(rows, ids, services.DatasetTotals, percentiles); err != nil {
+					return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+				}
+			}
+			data = rows
+		}
+
+		return c.JSON(fiber.Map{"data": data, "pagination": pagination})
+	}
+}
+
+// shapeTotals applies fields= and include=advanced to a page of totals.
+func shapeTotals(stats []models.PlayerTotalStat, p sparseParams, advanced repository.AdvancedStatsRepository) ([]map[string]interface{}, error) {
+	rows, err := project(stats, p, totalFields)
+	if err != nil || !p.includes("advanced") {
+		return rows, err
+	}
+	related, err := advanced.ListByKeys(seasonKeysOf(stats, repository.TotalSeasonKey))
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[repository.SeasonKey]*models.PlayerAdvancedStat, len(related))
+	for i := range related {
+		byKey[repository.AdvancedSeasonKey(&related[i])] = &related[i]
+	}
+	for i := range stats {
+		rows[i]["advanced"] = byKey[repository.TotalSeasonKey(&stats[i])]
 	}
+	return rows, nil
 }
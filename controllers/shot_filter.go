@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+// shotTypes maps the shotType option onto the stored shot types.
+var shotTypes = map[string]string{"2": "2-pointer", "3": "3-pointer"}
+
+// scoreStates are the accepted scoreState options.
+var scoreStates = []string{repository.ScoreLeading, repository.ScoreTrailing, repository.ScoreTied}
+
+// parseShotFilter reads the shot filters shared by the shot chart
+// endpoints. Player IDs are left for the caller to resolve.
+func parseShotFilter(c *fiber.Ctx) (repository.ShotFilter, error) {
+	league, err := leagueParam(c)
+	if err != nil {
+		return repository.ShotFilter{}, err
+	}
+	f := repository.ShotFilter{
+		League:   league,
+		Season:   c.QueryInt("season", 0),
+		Team:     c.Query("team"),
+		Opponent: c.Query("opponent"),
+	}
+
+	if z := c.Query("zone"); z != "" {
+		zones := strings.Split(z, ",")
+		for _, zone := range zones {
+			if !slices.Contains(services.ShotZones, zone) {
+				return f, fmt.Errorf("invalid zone: %s", zone)
+			}
+		}
+		f.Zones = zones
+	}
+	if q := c.Query("quarter"); q != "" {
+		for _, p := range strings.Split(q, ",") {
+			if strings.EqualFold(p, "OT") {
+				f.Overtime = true
+				continue
+			}
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 1 || n > 4 {
+				return f, fmt.Errorf("invalid quarter: %s (want 1-4 or OT)", p)
+			}
+			f.Periods = append(f.Periods, n)
+		}
+	}
+	switch r := c.Query("result"); r {
+	case "":
+	case "made", "missed":
+		made := r == "made"
+		f.Made = &made
+	default:
+		return f, fmt.Errorf("invalid result: %s (want made or missed)", r)
+	}
+	if t := c.Query("shotType"); t != "" {
+		if f.ShotType = shotTypes[t]; f.ShotType == "" {
+			return f, fmt.Errorf("invalid shotType: %s (want 2 or 3)", t)
+		}
+	}
+	if s := c.Query("scoreState"); s != "" {
+		if !slices.Contains(scoreStates, s) {
+			return f, fmt.Errorf("invalid scoreState: %s (want one of %s)", s, strings.Join(scoreStates, ", "))
+		}
+		f.Score = s
+	}
+	if c.Query("isPlayoff") != "" {
+		isPlayoff := c.QueryBool("isPlayoff", false)
+		f.IsPlayoff = &isPlayoff
+	}
+
+	for _, r := range []struct {
+		param string
+		dest  **time.Time
+	}{
+		{"dateFrom", &f.DateFrom}, {"dateTo", &f.DateTo},
+	} {
+		if v := c.Query(r.param); v != "" {
+			d, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s: %s (want YYYY-MM-DD)", r.param, v)
+			}
+			*r.dest = &d
+		}
+	}
+	for _, r := range []struct {
+		param string
+		dest  **float64
+	}{
+		{"minX", &f.MinX}, {"maxX", &f.MaxX},
+		{"minY", &f.MinY}, {"maxY", &f.MaxY},
+	} {
+		if v := c.Query(r.param); v != "" {
+			x, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s: %s", r.param, v)
+			}
+			*r.dest = &x
+		}
+	}
+	for _, r := range []struct {
+		param string
+		dest  **int
+	}{
+		{"minDistance", &f.MinDistance}, {"maxDistance", &f.MaxDistance},
+		{"minMargin", &f.MinMargin}, {"maxMargin", &f.MaxMargin},
+	} {
+		if v := c.Query(r.param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s: %s", r.param, v)
+			}
+			*r.dest = &n
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"github.com/nprasad2077/NBA_Go/services"
+)
+
+const maxSimilarResults = 100
+
+// SimilarPlayersResponse is a player-season and its nearest neighbours.
+type SimilarPlayersResponse struct {
+	PlayerID   string                   `json:"playerId"`
+	PlayerName string                   `json:"playerName"`
+	Season     int                      `json:"season"`
+	Age        int                      `json:"age"`
+	Weights    map[string]float64       `json:"weights"`
+	Target     map[string]float64       `json:"target"` // the season's feature values
+	Pool       int                      `json:"pool"`   // qualified seasons compared against
+	Similar    []services.SimilarSeason `json:"similar"`
+}
+
+// GetSimilarPlayers godoc
+// //@Security ApiKeyAuth
+// @Summary Find similar player-seasons
+// @Description The k player-seasons across history nearest to one of the player's seasons, by usage, TS%, AST%, TRB%, BLK%, STL%, 3PAr, FTr and BPM.
+// @Description Each feature is standardized over the qualified seasons (500 minutes, prorated) and the distance is the weighted Euclidean distance,
+// @Description returned with each feature's share of it. The player's own seasons are left out, and so are seasons before a weighted feature was tracked
+// @Description (NBA: BLK%, STL% and BPM from 1974, USG% from 1978, 3PAr from 1980); weight those features 0 to search earlier seasons.
+// @Description NBA.com rows have no BLK%, STL%, 3PAr, FTr or BPM and are left out likewise.
+// @Tags Players
+// @Produce  json
+// @Param id path string true "Player ID, BR or NBA.com (e.g. jokicni01)"
+// @Param season query int false "Season to match (default the player's latest)"
+// @Param k query int false "Number of results (max 100)" default(10)
+// @Param weights query string false "Feature weights, e.g. usagePercent:2,box:0.5; unlisted features weigh 1, 0 drops one"
+// @Param ageWindow query int false "Only seasons within this many years of the player's age that season"
+// @Param isPlayoff query bool false "Playoffs instead of regular season"
+// @Param league query string false "League" Enums(NBA, WNBA, ABA) default(NBA)
+// @Success 200 {object} SimilarPlayersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/players/{id}/similar [get]
+func GetSimilarPlayers(advanced repository.AdvancedStatsRepository, ids repository.PlayerIDResolver) fiber.Handler {
+	scales := services.NewSimilarityScaleCache()
+	return func(c *fiber.Ctx) error {
+		league, err := leagueParam(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		k := c.QueryInt("k", 10)
+		if k < 1 || k > maxSimilarResults {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("k must be between 1 and %d", maxSimilarResults)})
+		}
+		weights, err := services.ParseSimilarityWeights(c.Query("weights"))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		ageWindow := c.QueryInt("ageWindow", -1)
+		if c.Query("ageWindow") != "" && ageWindow < 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "ageWindow must be a non-negative integer"})
+		}
+		isPlayoff := c.QueryBool("isPlayoff", false)
+		playerIDs, err := ids.ResolvePlayerIDs(c.Params("id"))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// One row per season: a traded player's aggregate row.
+		own, _, err := advanced.List(repository.StatsQuery{
+			Filter: repository.StatsFilter{
+				League: league, Season: c.QueryInt("season", 0), PlayerIDs: playerIDs,
+				IsPlayoff: &isPlayoff, TeamMode: repository.TeamModeCombined,
+			},
+			Sort:      repository.Sort{Column: "season"},
+			Limit:     1,
+			SkipCount: true,
+			Columns:   similarColumns,
+		})
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if len(own) == 0 {
+			return c.Status(404).JSON(fiber.Map{"error": "no advanced stats for that player and season"})
+		}
+		target := own[0]
+		if missing := services.UntrackedSimilarityFeatures(league, &target, weights); len(missing) > 0 {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf(
+				"%s not tracked for the %d season; weight them 0 to compare it", strings.Join(missing, ", "), target.Season)})
+		}
+
+		// Seasons before a weighted feature was tracked, and NBA.com rows,
+		// store 0 for it, so they are left out of both the population and
+		// the pool.
+		firstSeason := services.SimilarityFirstSeason(league, weights)
+		lowest := services.LowestPopulationQualification(league, isPlayoff)
+		load := func(extra ...repository.Condition) ([]models.PlayerAdvancedStat, error) {
+			filter := repository.StatsFilter{
+				League: league, IsPlayoff: &isPlayoff, TeamMode: repository.TeamModeCombined,
+				Conditions: append([]repository.Condition{
+					{Column: "minutes_played", Op: repository.OpGte, Values: []interface{}{lowest.MinMinutes}},
+					{Column: "season", Op: repository.OpGte, Values: []interface{}{firstSeason}},
+				}, extra...),
+			}
+			rows, _, err := advanced.List(repository.StatsQuery{
+				Filter:    filter,
+				Sort:      repository.Sort{Column: "season", Ascending: true},
+				SkipCount: true,
+				Columns:   similarColumns,
+			})
+			return slices.DeleteFunc(rows, func(s models.PlayerAdvancedStat) bool {
+				q := services.PopulationQualification(league, s.Season, isPlayoff)
+				return !q.Meets(s.Games, float64(s.MinutesPlayed), 0) ||
+					len(services.UntrackedSimilarityFeatures(league, &s, weights)) > 0
+			}), err
+		}
+
+		// Features are standardized over every qualified season, cached per
+		// league, season type and weighted features; the pool is what the
+		// target is compared to.
+		var population []models.PlayerAdvancedStat
+		scale, err := scales.Get(league, isPlayoff, weights, func() (services.SimilarityScale, error) {
+			population, err = load()
+			return services.NewSimilarityScale(population), err
+		})
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		pool := population
+		if pool == nil || ageWindow >= 0 {
+			var extra []repository.Condition
+			if ageWindow >= 0 {
+				extra = append(extra, repository.Condition{
+					Column: "age", Op: repository.OpBetween, Values: []interface{}{target.Age - ageWindow, target.Age + ageWindow},
+				})
+			}
+			if pool, err = load(extra...); err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+		pool = slices.DeleteFunc(pool, func(s models.PlayerAdvancedStat) bool {
+			return slices.Contains(playerIDs, s.PlayerID)
+		})
+
+		resp := SimilarPlayersResponse{
+			PlayerID: c.Params("id"), PlayerName: target.PlayerName, Season: target.Season, Age: target.Age,
+			Weights: weights, Target: map[string]float64{}, Pool: len(pool),
+			Similar: services.FindSimilar(&target, pool, scale, weights, k),
+		}
+		if resp.Similar == nil {
+			resp.Similar = []services.SimilarSeason{}
+		}
+		projected, err := repository.Project([]models.PlayerAdvancedStat{target}, similarFields)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		for name, v := range projected[0] {
+			resp.Target[name] = services.NumberValue(v)
+		}
+		return c.JSON(resp)
+	}
+}
+
+// similarFields are the similarity features as advanced fields;
+// similarColumns adds what a result row and its qualification need.
+var similarFields, similarColumns = func() ([]repository.Field, []string) {
+	var fields []repository.Field
+	columns := []string{"player_id", "player_name", "season", "age", "team", "games", "minutes_played"}
+	for _, name := range services.SimilarityFeatures() {
+		f, ok := advancedFields.Lookup(name)
+		if !ok {
+			panic("similarity feature " + name + " is not an advanced field")
+		}
+		fields = append(fields, f)
+		columns = append(columns, f.Column)
+	}
+	return fields, columns
+}()
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// seasonKeyColumns are read whenever related rows are attached, since
+// they join the two datasets.
+var seasonKeyColumns = []string{"league", "player_id", "season", "team", "is_playoff"}
+
+// sparseParams are a list request's fields= and include= options.
+type sparseParams struct {
+	fields  []repository.Field // nil means every field
+	include []string
+}
+
+// parseSparse reads fields= (checked against fields) and include=
+// (checked against includes).
+func parseSparse(c *fiber.Ctx, fields repository.Fields, includes ...string) (sparseParams, error) {
+	var p sparseParams
+	var err error
+	if p.fields, err = fields.Select(c.Query("fields")); err != nil {
+		return p, err
+	}
+	if inc := c.Query("include"); inc != "" {
+		for _, name := range strings.Split(inc, ",") {
+			if !slices.Contains(includes, name) {
+				return p, fmt.Errorf("unknown include %q (want one of %s)", name, strings.Join(includes, ", "))
+			}
+			p.include = append(p.include, name)
+		}
+	}
+	return p, nil
+}
+
+// active reports whether the response needs reshaping at all.
+func (p sparseParams) active() bool {
+	return p.fields != nil || len(p.include) > 0
+}
+
+// includes reports whether name was requested in include=.
+func (p sparseParams) includes(name string) bool {
+	return slices.Contains(p.include, name)
+}
+
+// columns is the SQL projection for the request; nil reads every column.
+func (p sparseParams) columns() []string {
+	if p.fields == nil {
+		return nil
+	}
+	cols := make([]string, 0, len(p.fields)+len(seasonKeyColumns))
+	for _, f := range p.fields {
+		cols = append(cols, f.Column)
+	}
+	if len(p.include) > 0 {
+		for _, col := range seasonKeyColumns {
+			if !slices.Contains(cols, col) {
+				cols = append(cols, col)
+			}
+		}
+	}
+	return cols
+}
+
+// project shapes rows to the requested fields; all is the full field set.
+func project[T any](rows []T, p sparseParams, all repository.Fields) ([]map[string]interface{}, error) {
+	fields := p.fields
+	if fields == nil {
+		fields = all.All()
+	}
+	return repository.Project(rows, fields)
+}
+
+// seasonKeysOf collects the SeasonKey of each row.
+func seasonKeysOf[T any](rows []T, key func(*T) repository.SeasonKey) []repository.SeasonKey {
+	keys := make([]repository.SeasonKey, len(rows))
+	for i := range rows {
+		keys[i] = key(&rows[i])
+	}
+	return keys
+}
@@ -15,70 +15,185 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/playeradvancedstats": {
+        "/api/compare": {
             "get": {
-                "description": "Returns filtered and paginated player advanced stats",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Two to ten players' seasons side by side, aligned by calendar season, age or years in the league,\nwith each player's career totals and peak season per stat. Peaks of rate stats only count seasons that qualify for the leaderboards;\nturnoverPercent peaks at its lowest season and turnovers and personalFouls have no peak. Experience counts regular seasons, also for playoff rows.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PlayerStats"
+                    "Players"
                 ],
-                "summary": "Get player advanced stats",
+                "summary": "Compare players",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Season (e.g., 2025)",
-                        "name": "season",
+                        "type": "string",
+                        "description": "Comma-separated player IDs, BR or NBA.com (e.g. curryst01,duranke01)",
+                        "name": "players",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated totals or advanced stats (default games,points,totalRb,assists,fieldPercent,threePercent,ftPercent,per,tsPercent,winShares)",
+                        "name": "stats",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "season",
+                            "age",
+                            "experience"
+                        ],
                         "type": "string",
-                        "description": "Team abbreviation (e.g., MIL)",
-                        "name": "team",
+                        "default": "season",
+                        "description": "Row alignment",
+                        "name": "align",
                         "in": "query"
                     },
                     {
+                        "type": "boolean",
+                        "description": "Playoffs instead of regular season",
+                        "name": "isPlayoff",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
                         "type": "string",
-                        "description": "Player ID (e.g., greenaj01)",
-                        "name": "playerId",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CompareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/leaders/{stat}": {
+            "get": {
+                "description": "Ranks players on any numeric totals or advanced stat, applying Basketball-Reference-style qualification (games, minutes or makes) prorated for shortened seasons. Tied players share a rank.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaders"
+                ],
+                "summary": "Stat leaderboard",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "description": "Stat JSON name (e.g. points, threePercent, per)",
+                        "name": "stat",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "totals",
+                            "advanced"
+                        ],
+                        "type": "string",
+                        "description": "Where to look the stat up when both have it",
+                        "name": "dataset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Rank career totals instead of seasons (totals stats only)",
+                        "name": "career",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Page size",
-                        "name": "pageSize",
+                        "description": "Season; omit to rank every season",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Playoffs instead of regular season",
+                        "name": "isPlayoff",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
                         "type": "string",
-                        "default": "winShares",
-                        "description": "Field to sort by",
-                        "name": "sortBy",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "default": false,
-                        "description": "Sort ascending",
+                        "description": "Rank the lowest values first",
                         "name": "ascending",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Whether playoffs?",
-                        "name": "isPlayoff",
+                        "type": "integer",
+                        "default": 25,
+                        "description": "Number of places (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Override the games floor",
+                        "name": "minGames",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Override the minutes floor",
+                        "name": "minMinutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Override the makes floor of a shooting percentage",
+                        "name": "minMade",
                         "in": "query"
                     }
                 ],
@@ -86,7 +201,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.AdvancedStatsResponse"
+                            "$ref": "#/definitions/controllers.LeadersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -101,9 +225,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/playershotchart": {
+        "/api/playeradvancedstats": {
             "get": {
-                "description": "Returns shot-chart points, optionally filtered by playerId and/or season",
+                "description": "Returns filtered and paginated player advanced stats",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,20 +235,131 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PlayerShotChart"
+                    "PlayerStats"
                 ],
-                "summary": "Get shot-chart data",
+                "summary": "Get player advanced stats",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season (e.g., 2025)",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Team abbreviation (e.g., MIL)",
+                        "name": "team",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Player ID (e.g., hardeja01)",
+                        "description": "Player ID, BR or NBA.com (e.g., greenaj01)",
                         "name": "playerId",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Season (e.g., 2023)",
-                        "name": "season",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset pagination: nextCursor from the previous page; must be used with the same sortBy and ascending",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Keyset pagination: rows per page (max 1000); switches from page/pageSize to cursor mode",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Count the total matches (skip it for faster pages)",
+                        "name": "includeTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "winShares",
+                        "description": "Field to sort by",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Sort ascending",
+                        "name": "ascending",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether playoffs?",
+                        "name": "isPlayoff",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "combined",
+                            "split",
+                            "both"
+                        ],
+                        "type": "string",
+                        "default": "combined",
+                        "description": "Traded players: combined, split or both",
+                        "name": "teamMode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return (e.g. playerName,team,per,vorp)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "totals"
+                        ],
+                        "type": "string",
+                        "description": "Attach related rows: totals (the matching season totals row)",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression: field:op:value clauses, comma-separated; ops eq, ne, gt, gte, lt, lte, between (two values), in (|-separated). E.g. per:gte:20,games:gte:50,age:lt:25",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Attach each row's within-season percentiles (overall and by position)",
+                        "name": "withPercentiles",
                         "in": "query"
                     }
                 ],
@@ -132,9 +367,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.PlayerShotChart"
+                            "$ref": "#/definitions/controllers.AdvancedStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -150,36 +391,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/playertotals": {
+        "/api/players/career": {
             "get": {
-                "description": "Filter and paginate player totals",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Sort and paginate career totals",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PlayerTotals"
+                    "Players"
                 ],
-                "summary": "Get player total stats",
+                "summary": "Career leaderboard",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Season (e.g. 2000)",
-                        "name": "season",
+                        "type": "string",
+                        "default": "points",
+                        "description": "Field to sort by (e.g. points, assists, threePercent)",
+                        "name": "sortBy",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Team abbreviation (e.g. LAL)",
-                        "name": "team",
+                        "type": "boolean",
+                        "description": "Sort ascending (default false)",
+                        "name": "ascending",
                         "in": "query"
                     },
                     {
+                        "type": "boolean",
+                        "description": "Playoff careers instead of regular season",
+                        "name": "isPlayoff",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only careers with at least this many games",
+                        "name": "minGames",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
                         "type": "string",
-                        "description": "Player ID (e.g. greenac01)",
-                        "name": "playerId",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
                         "in": "query"
                     },
                     {
@@ -198,20 +455,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Field to sort by (e.g. points, assists)",
-                        "name": "sortBy",
+                        "description": "Keyset pagination: nextCursor from the previous page; must be used with the same sortBy and ascending",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Sort ascending (default false)",
-                        "name": "ascending",
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Keyset pagination: rows per page (max 1000); switches from page/pageSize to cursor mode",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "boolean",
-                        "description": "Whether the stats are for playoffs",
-                        "name": "isPlayoff",
+                        "default": true,
+                        "description": "Count the total matches (skip it for faster pages)",
+                        "name": "includeTotal",
                         "in": "query"
                     }
                 ],
@@ -223,6 +482,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -234,87 +502,1515 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.AdvancedStatsResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/api/players/search": {
+            "get": {
+                "description": "Accent- and case-insensitive name search with prefix and typo tolerance, ranked by relevance and career minutes. The returned playerId works with every other endpoint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Search players by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name or part of a name (e.g. jokic, lebron, doncic)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum results (at most 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/players/{id}/career": {
+            "get": {
+                "description": "Regular-season and playoff career totals, with traded seasons counted once and percentages recomputed from the summed totals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get a player's career totals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Player ID, BR or NBA.com (e.g. jamesle01)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlayerCareerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/players/{id}/profile": {
+            "get": {
+                "description": "Season-by-season totals and advanced stats merged per (season, team, season type),\ntraded seasons' aggregate rows included, plus a per-season shot summary by distance band.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get a player's profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Player ID, BR or NBA.com (e.g. jamesle01)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First season to include (e.g. 2015)",
+                        "name": "fromSeason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last season to include (e.g. 2024)",
+                        "name": "toSeason",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlayerProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/players/{id}/similar": {
+            "get": {
+                "description": "The k player-seasons across history nearest to one of the player's seasons, by usage, TS%, AST%, TRB%, BLK%, STL%, 3PAr, FTr and BPM.\nEach feature is standardized over the qualified seasons (500 minutes, prorated) and the distance is the weighted Euclidean distance,\nreturned with each feature's share of it. The player's own seasons are left out, and so are seasons before a weighted feature was tracked\n(NBA: BLK%, STL% and BPM from 1974, USG% from 1978, 3PAr from 1980); weight those features 0 to search earlier seasons.\nNBA.com rows have no BLK%, STL%, 3PAr, FTr or BPM and are left out likewise.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Find similar player-seasons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Player ID, BR or NBA.com (e.g. jokicni01)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season to match (default the player's latest)",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of results (max 100)",
+                        "name": "k",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feature weights, e.g. usagePercent:2,box:0.5; unlisted features weigh 1, 0 drops one",
+                        "name": "weights",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only seasons within this many years of the player's age that season",
+                        "name": "ageWindow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Playoffs instead of regular season",
+                        "name": "isPlayoff",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SimilarPlayersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playershotchart": {
+            "get": {
+                "description": "Returns shot-chart points matching the filters as {data, pagination}, paged\nlike the other list endpoints (pageSize 20 by default, at most 1000).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PlayerShotChart"
+                ],
+                "summary": "Get shot-chart data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Player ID, BR or NBA.com (e.g., hardeja01 or 201935)",
+                        "name": "playerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season (e.g., 2023)",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Playoff (true) or regular-season (false) shots only",
+                        "name": "isPlayoff",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First game date, YYYY-MM-DD",
+                        "name": "dateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last game date, YYYY-MM-DD",
+                        "name": "dateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated quarters 1-4 and/or OT (every overtime), e.g. 4,OT",
+                        "name": "quarter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "made",
+                            "missed"
+                        ],
+                        "type": "string",
+                        "description": "made or missed",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "2",
+                            "3"
+                        ],
+                        "type": "string",
+                        "description": "2 or 3 pointers",
+                        "name": "shotType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Min shot distance in feet",
+                        "name": "minDistance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max shot distance in feet",
+                        "name": "maxDistance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shooter's team (e.g. GSW)",
+                        "name": "team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opponent (e.g. LAL)",
+                        "name": "opponent",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "leading",
+                            "trailing",
+                            "tied"
+                        ],
+                        "type": "string",
+                        "description": "Shooting team's score state (BR shots only; NBA.com shots have no running score)",
+                        "name": "scoreState",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Min score margin, team minus opponent (negative = trailing; BR shots only)",
+                        "name": "minMargin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max score margin, team minus opponent (BR shots only)",
+                        "name": "maxMargin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated zones (restricted_area, paint_non_ra, mid_range, left_corner_3, right_corner_3, above_break_3, backcourt)",
+                        "name": "zone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Min feet right of the basket (negative = left)",
+                        "name": "minX",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Max feet right of the basket",
+                        "name": "maxX",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Min feet out from the basket",
+                        "name": "minY",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Max feet out from the basket",
+                        "name": "maxY",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League (shot charts are NBA-only)",
+                        "name": "league",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset pagination: nextCursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Keyset pagination: shots per page (max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Count the total matches",
+                        "name": "includeTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ShotChartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playershotchart/aggregate": {
+            "get": {
+                "description": "Groups the matching shots into hexagons or squares over the chart's Top/Left\npixels (10 px to the foot), or into distance bands, and returns attempts,\nmakes, FG% and points per shot per bin. Takes every /api/playershotchart\nfilter; one of playerId, team or season is required. withLeague=true adds\nthe league FG% in each bin, over the same filters minus playerId and team.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PlayerShotChart"
+                ],
+                "summary": "Binned shot-chart aggregates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Player ID, BR or NBA.com",
+                        "name": "playerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shooter's team (e.g. GSW)",
+                        "name": "team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Season (e.g., 2024)",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hex",
+                            "grid",
+                            "distance"
+                        ],
+                        "type": "string",
+                        "default": "hex",
+                        "description": "Bin kind",
+                        "name": "bin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Hex radius or grid side in pixels, or band width in whole feet (defaults 15, 20, 3)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Drop bins with fewer attempts",
+                        "name": "minAttempts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the league-average FG% per bin",
+                        "name": "withLeague",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ShotAggregateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/playertotals": {
+            "get": {
+                "description": "Filter and paginate player totals",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PlayerTotals"
+                ],
+                "summary": "Get player total stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season (e.g. 2000)",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Team abbreviation (e.g. LAL)",
+                        "name": "team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Player ID, BR or NBA.com (e.g. greenac01)",
+                        "name": "playerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset pagination: nextCursor from the previous page; must be used with the same sortBy and ascending",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Keyset pagination: rows per page (max 1000); switches from page/pageSize to cursor mode",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Count the total matches (skip it for faster pages)",
+                        "name": "includeTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to sort by (e.g. points, assists)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort ascending (default false)",
+                        "name": "ascending",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the stats are for playoffs",
+                        "name": "isPlayoff",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "combined",
+                            "split",
+                            "both"
+                        ],
+                        "type": "string",
+                        "default": "combined",
+                        "description": "Traded players: combined (aggregate row only), split (per-team rows only) or both",
+                        "name": "teamMode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "NBA",
+                            "WNBA",
+                            "ABA"
+                        ],
+                        "type": "string",
+                        "default": "NBA",
+                        "description": "League",
+                        "name": "league",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return (e.g. playerName,team,points)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "advanced"
+                        ],
+                        "type": "string",
+                        "description": "Attach related rows: advanced (the matching advanced stats row)",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression: field:op:value clauses, comma-separated; ops eq, ne, gt, gte, lt, lte, between (two values), in (|-separated). E.g. points:gte:1500,season:between:2015:2024,team:in:LAL|BOS",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Attach each row's within-season percentiles (overall and by position) of the stored totals",
+                        "name": "withPercentiles",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Totals",
+                            "PerGame",
+                            "Per36",
+                            "Per48"
+                        ],
+                        "type": "string",
+                        "default": "Totals",
+                        "description": "Counting stats as season totals, per game, per 36 or per 48 minutes; sortBy ranks the derived values",
+                        "name": "perMode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.AdvancedStatsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlayerAdvancedStat"
+                    }
+                },
+                "pagination": {
+                    "type": "object",
+                    "properties": {
+                        "limit": {
+                            "description": "cursor mode only",
+                            "type": "integer"
+                        },
+                        "nextCursor": {
+                            "description": "cursor mode only; null on the last page",
+                            "type": "string"
+                        },
+                        "page": {
+                            "type": "integer"
+                        },
+                        "pageSize": {
+                            "type": "integer"
+                        },
+                        "pages": {
+                            "type": "integer"
+                        },
+                        "total": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "controllers.ComparePeak": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "season": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.ComparePlayer": {
+            "type": "object",
+            "properties": {
+                "career": {
+                    "$ref": "#/definitions/models.PlayerCareerStat"
+                },
+                "peaks": {
+                    "description": "Peaks maps each stat to its best qualifying season, the lowest for\nturnoverPercent; nil when no season qualifies and always nil for\nturnovers and personalFouls.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/controllers.ComparePeak"
+                    }
+                },
+                "playerId": {
+                    "type": "string"
+                },
+                "playerName": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CompareResponse": {
+            "type": "object",
+            "properties": {
+                "align": {
+                    "type": "string"
+                },
+                "players": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ComparePlayer"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.CompareRow"
+                    }
+                },
+                "stats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controllers.CompareRow": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "integer"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.CompareSeason"
+                    }
+                }
+            }
+        },
+        "controllers.CompareSeason": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "season": {
+                    "type": "integer"
+                },
+                "stats": {
+                    "description": "Stats holds the requested stats; advanced stats are missing when the\nseason has no advanced row.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "team": {
+                    "type": "string"
+                },
+                "year": {
+                    "description": "1 for the player's first regular season",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.LeadersResponse": {
+            "type": "object",
+            "properties": {
+                "dataset": {
+                    "type": "string"
+                },
+                "isPlayoff": {
+                    "type": "boolean"
+                },
+                "leaders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.Leader"
+                    }
+                },
+                "league": {
+                    "type": "string"
+                },
+                "qualification": {
+                    "description": "Qualification is the floor for the requested season, or for a full\nschedule when ranking every season; each season is prorated by its\nlength unless overridden.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/services.Qualification"
+                        }
+                    ]
+                },
+                "season": {
+                    "description": "0 ranks every season",
+                    "type": "integer"
+                },
+                "stat": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.PlayerCareerResponse": {
+            "type": "object",
+            "properties": {
+                "playerId": {
+                    "type": "string"
+                },
+                "playoffs": {
+                    "$ref": "#/definitions/models.PlayerCareerStat"
+                },
+                "regularSeason": {
+                    "$ref": "#/definitions/models.PlayerCareerStat"
+                }
+            }
+        },
+        "controllers.PlayerProfileResponse": {
+            "type": "object",
+            "properties": {
+                "playerId": {
+                    "type": "string"
+                },
+                "playerName": {
+                    "type": "string"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ProfileSeason"
+                    }
+                },
+                "shots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ShotSeasonSummary"
+                    }
+                }
+            }
+        },
+        "controllers.ProfileSeason": {
+            "type": "object",
+            "properties": {
+                "advanced": {
+                    "$ref": "#/definitions/models.PlayerAdvancedStat"
+                },
+                "isAggregate": {
+                    "type": "boolean"
+                },
+                "isPlayoff": {
+                    "type": "boolean"
+                },
+                "season": {
+                    "type": "integer"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.PlayerTotalStat"
+                }
+            }
+        },
+        "controllers.ShotAggregateResponse": {
+            "type": "object",
+            "properties": {
+                "bin": {
+                    "type": "string"
+                },
+                "bins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ShotBin"
+                    }
+                },
+                "size": {
+                    "type": "number"
+                },
+                "totals": {
+                    "$ref": "#/definitions/services.ShotBin"
+                }
+            }
+        },
+        "controllers.ShotChartResponse": {
+            "type": "object",
+            "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.PlayerAdvancedStat"
+                        "$ref": "#/definitions/models.PlayerShotChart"
+                    }
+                },
+                "pagination": {
+                    "type": "object",
+                    "properties": {
+                        "limit": {
+                            "description": "cursor mode only",
+                            "type": "integer"
+                        },
+                        "nextCursor": {
+                            "description": "cursor mode only; null on the last page",
+                            "type": "string"
+                        },
+                        "page": {
+                            "type": "integer"
+                        },
+                        "pageSize": {
+                            "type": "integer"
+                        },
+                        "pages": {
+                            "type": "integer"
+                        },
+                        "total": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        },
+        "controllers.SimilarPlayersResponse": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "playerId": {
+                    "type": "string"
+                },
+                "playerName": {
+                    "type": "string"
+                },
+                "pool": {
+                    "description": "qualified seasons compared against",
+                    "type": "integer"
+                },
+                "season": {
+                    "type": "integer"
+                },
+                "similar": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SimilarSeason"
+                    }
+                },
+                "target": {
+                    "description": "the season's feature values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "weights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
                     }
+                }
+            }
+        },
+        "models.PlayerAdvancedStat": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "assistPercent": {
+                    "type": "number"
+                },
+                "blockPercent": {
+                    "type": "number"
+                },
+                "box": {
+                    "type": "number"
+                },
+                "defensiveBox": {
+                    "type": "number"
+                },
+                "defensiveRBPercent": {
+                    "type": "number"
+                },
+                "defensiveWS": {
+                    "type": "number"
+                },
+                "ftr": {
+                    "type": "number"
+                },
+                "games": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isAggregate": {
+                    "description": "multi-team season total (TOT/2TM/3TM)",
+                    "type": "boolean"
+                },
+                "isPlayoff": {
+                    "type": "boolean"
+                },
+                "league": {
+                    "type": "string"
+                },
+                "minutesPlayed": {
+                    "type": "integer"
+                },
+                "offensiveBox": {
+                    "type": "number"
+                },
+                "offensiveRBPercent": {
+                    "type": "number"
+                },
+                "offensiveWS": {
+                    "type": "number"
+                },
+                "per": {
+                    "type": "number"
+                },
+                "playerId": {
+                    "type": "string"
+                },
+                "playerName": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "season": {
+                    "type": "integer"
+                },
+                "stealPercent": {
+                    "type": "number"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "threePAR": {
+                    "type": "number"
+                },
+                "totalRBPercent": {
+                    "type": "number"
+                },
+                "tsPercent": {
+                    "type": "number"
+                },
+                "turnoverPercent": {
+                    "type": "number"
+                },
+                "usagePercent": {
+                    "type": "number"
+                },
+                "vorp": {
+                    "type": "number"
+                },
+                "winShares": {
+                    "type": "number"
+                },
+                "winSharesPer": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PlayerCareerStat": {
+            "type": "object",
+            "properties": {
+                "assists": {
+                    "type": "integer"
+                },
+                "blocks": {
+                    "type": "integer"
+                },
+                "defensiveRb": {
+                    "type": "integer"
+                },
+                "effectFgPercent": {
+                    "type": "number"
+                },
+                "fieldAttempts": {
+                    "type": "integer"
+                },
+                "fieldGoals": {
+                    "type": "integer"
+                },
+                "fieldPercent": {
+                    "type": "number"
+                },
+                "firstSeason": {
+                    "type": "integer"
+                },
+                "ft": {
+                    "type": "integer"
+                },
+                "ftAttempts": {
+                    "type": "integer"
+                },
+                "ftPercent": {
+                    "type": "number"
+                },
+                "games": {
+                    "type": "integer"
+                },
+                "gamesStarted": {
+                    "type": "integer"
+                },
+                "isPlayoff": {
+                    "type": "boolean"
+                },
+                "lastSeason": {
+                    "type": "integer"
+                },
+                "league": {
+                    "type": "string"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "offensiveRb": {
+                    "type": "integer"
+                },
+                "personalFouls": {
+                    "type": "integer"
+                },
+                "playerId": {
+                    "type": "string"
+                },
+                "playerName": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "seasons": {
+                    "type": "integer"
+                },
+                "steals": {
+                    "type": "integer"
+                },
+                "teams": {
+                    "description": "comma-separated, in the order first played for",
+                    "type": "string"
+                },
+                "threeAttempts": {
+                    "type": "integer"
+                },
+                "threeFg": {
+                    "type": "integer"
+                },
+                "threePercent": {
+                    "type": "number"
+                },
+                "totalRb": {
+                    "type": "integer"
+                },
+                "turnovers": {
+                    "type": "integer"
+                },
+                "twoAttempts": {
+                    "type": "integer"
+                },
+                "twoFg": {
+                    "type": "integer"
+                },
+                "twoPercent": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlayerShotChart": {
+            "type": "object",
+            "properties": {
+                "courtX": {
+                    "description": "──────────  court position in feet relative to the basket  ──────────",
+                    "type": "number"
+                },
+                "courtY": {
+                    "description": "out from the basket toward half court",
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
                 },
-                "pagination": {
-                    "type": "object",
-                    "properties": {
-                        "page": {
-                            "type": "integer"
-                        },
-                        "pageSize": {
-                            "type": "integer"
-                        },
-                        "pages": {
-                            "type": "integer"
-                        },
-                        "total": {
-                            "type": "integer"
-                        }
-                    }
+                "distanceFt": {
+                    "type": "integer"
+                },
+                "elapsedSeconds": {
+                    "description": "since tip-off",
+                    "type": "integer"
+                },
+                "gameDate": {
+                    "description": "──────────  typed game context, derived from Date/Quarter/TimeRemaining  ──────────",
+                    "type": "string"
+                },
+                "gameId": {
+                    "description": "NBA.com game ID, when sourced from there",
+                    "type": "string"
+                },
+                "hasScore": {
+                    "description": "false when the source has no running score (NBA.com); the scores are then 0",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "Auto‑increment primary key — works in SQLite and any other DB.",
+                    "type": "integer"
+                },
+                "isHome": {
+                    "description": "nil on rows scraped before it was recorded",
+                    "type": "boolean"
+                },
+                "isOvertime": {
+                    "type": "boolean"
+                },
+                "isPlayoff": {
+                    "type": "boolean"
+                },
+                "lead": {
+                    "type": "boolean"
+                },
+                "league": {
+                    "description": "──────────  \"identity\" columns (the dedup key)  ──────────",
+                    "type": "string"
+                },
+                "left": {
+                    "type": "integer"
+                },
+                "opponent": {
+                    "type": "string"
+                },
+                "opponentTeamScore": {
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "1–4; 5 = 1st OT, 6 = 2nd OT, …",
+                    "type": "integer"
+                },
+                "playerId": {
+                    "type": "string"
+                },
+                "playerName": {
+                    "description": "──────────  the rest of the payload  ──────────",
+                    "type": "string"
+                },
+                "qtr": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "boolean"
+                },
+                "season": {
+                    "type": "integer"
+                },
+                "secondsRemaining": {
+                    "description": "left in the period",
+                    "type": "integer"
+                },
+                "shotAngle": {
+                    "description": "degrees off centre; negative = left",
+                    "type": "number"
+                },
+                "shotType": {
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "teamScore": {
+                    "type": "integer"
+                },
+                "timeRemaining": {
+                    "type": "string"
+                },
+                "top": {
+                    "type": "integer"
+                },
+                "zone": {
+                    "description": "restricted_area, paint_non_ra, mid_range, …",
+                    "type": "string"
                 }
             }
         },
-        "models.PlayerAdvancedStat": {
+        "models.PlayerTotalStat": {
             "type": "object",
             "properties": {
                 "age": {
                     "type": "integer"
                 },
-                "assistPercent": {
-                    "type": "number"
+                "assists": {
+                    "type": "integer"
                 },
-                "blockPercent": {
-                    "type": "number"
+                "blocks": {
+                    "type": "integer"
                 },
-                "box": {
-                    "type": "number"
+                "defensiveRb": {
+                    "type": "integer"
                 },
-                "defensiveBox": {
+                "effectFgPercent": {
                     "type": "number"
                 },
-                "defensiveRBPercent": {
-                    "type": "number"
+                "fieldAttempts": {
+                    "type": "integer"
                 },
-                "defensiveWS": {
+                "fieldGoals": {
+                    "type": "integer"
+                },
+                "fieldPercent": {
                     "type": "number"
                 },
-                "ftr": {
+                "ft": {
+                    "type": "integer"
+                },
+                "ftAttempts": {
+                    "type": "integer"
+                },
+                "ftPercent": {
                     "type": "number"
                 },
                 "games": {
                     "type": "integer"
                 },
+                "gamesStarted": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "isPlayoff": {
+                "isAggregate": {
+                    "description": "multi-team season total (TOT/2TM/3TM)",
                     "type": "boolean"
                 },
-                "minutesPlayed": {
-                    "type": "integer"
+                "isPlayoff": {
+                    "type": "boolean"
                 },
-                "offensiveBox": {
-                    "type": "number"
+                "league": {
+                    "type": "string"
                 },
-                "offensiveRBPercent": {
+                "minutesPg": {
                     "type": "number"
                 },
-                "offensiveWS": {
-                    "type": "number"
+                "offensiveRb": {
+                    "type": "integer"
                 },
-                "per": {
-                    "type": "number"
+                "personalFouls": {
+                    "type": "integer"
                 },
                 "playerId": {
                     "type": "string"
@@ -322,100 +2018,197 @@ const docTemplate = `{
                 "playerName": {
                     "type": "string"
                 },
+                "points": {
+                    "type": "integer"
+                },
                 "position": {
                     "type": "string"
                 },
                 "season": {
                     "type": "integer"
                 },
-                "stealPercent": {
-                    "type": "number"
+                "steals": {
+                    "type": "integer"
                 },
                 "team": {
                     "type": "string"
                 },
-                "threePAR": {
-                    "type": "number"
+                "threeAttempts": {
+                    "type": "integer"
                 },
-                "totalRBPercent": {
-                    "type": "number"
+                "threeFg": {
+                    "type": "integer"
                 },
-                "tsPercent": {
+                "threePercent": {
                     "type": "number"
                 },
-                "turnoverPercent": {
-                    "type": "number"
+                "totalRb": {
+                    "type": "integer"
                 },
-                "usagePercent": {
-                    "type": "number"
+                "turnovers": {
+                    "type": "integer"
                 },
-                "vorp": {
+                "twoAttempts": {
+                    "type": "integer"
+                },
+                "twoFg": {
+                    "type": "integer"
+                },
+                "twoPercent": {
+                    "type": "number"
+                }
+            }
+        },
+        "services.FeatureContribution": {
+            "type": "object",
+            "properties": {
+                "difference": {
+                    "description": "Value minus the target's value",
                     "type": "number"
                 },
-                "winShares": {
+                "share": {
+                    "description": "Share is the fraction of the squared distance this feature adds.",
                     "type": "number"
                 },
-                "winSharesPer": {
+                "value": {
                     "type": "number"
                 }
             }
         },
-        "models.PlayerShotChart": {
+        "services.Leader": {
             "type": "object",
             "properties": {
-                "date": {
+                "games": {
+                    "type": "integer"
+                },
+                "playerId": {
                     "type": "string"
                 },
-                "distanceFt": {
+                "playerName": {
+                    "type": "string"
+                },
+                "rank": {
                     "type": "integer"
                 },
-                "id": {
-                    "description": "Auto‑increment primary key — works in SQLite and any other DB.",
+                "season": {
+                    "description": "not set on career boards",
                     "type": "integer"
                 },
-                "lead": {
+                "team": {
+                    "type": "string"
+                },
+                "tied": {
                     "type": "boolean"
                 },
-                "left": {
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "services.Qualification": {
+            "type": "object",
+            "properties": {
+                "madeField": {
+                    "description": "MadeField is the JSON field MinMade applies to, e.g. \"fieldGoals\".",
+                    "type": "string"
+                },
+                "minGames": {
                     "type": "integer"
                 },
-                "opponent": {
-                    "type": "string"
+                "minMade": {
+                    "type": "integer"
                 },
-                "opponentTeamScore": {
+                "minMinutes": {
+                    "type": "number"
+                }
+            }
+        },
+        "services.ShotBin": {
+            "type": "object",
+            "properties": {
+                "attempts": {
                     "type": "integer"
                 },
-                "playerId": {
-                    "description": "──────────  \"identity\" columns (the dedup key)  ──────────",
-                    "type": "string"
+                "fgPercent": {
+                    "type": "number"
                 },
-                "playerName": {
-                    "description": "──────────  the rest of the payload  ──────────",
+                "key": {
                     "type": "string"
                 },
-                "qtr": {
-                    "type": "string"
+                "leagueFgPercent": {
+                    "description": "LeagueFGPercent is the league's FG% in the same bin, when asked for.",
+                    "type": "number"
                 },
-                "result": {
+                "left": {
+                    "type": "number"
+                },
+                "makes": {
+                    "type": "integer"
+                },
+                "maxDistanceFt": {
+                    "type": "integer"
+                },
+                "minDistanceFt": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "pointsPerShot": {
+                    "type": "number"
+                },
+                "top": {
+                    "type": "number"
+                }
+            }
+        },
+        "services.ShotSeasonSummary": {
+            "type": "object",
+            "properties": {
+                "bands": {
+                    "description": "one per ShotBands entry, empty ones included",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ShotBin"
+                    }
+                },
+                "isPlayoff": {
                     "type": "boolean"
                 },
                 "season": {
                     "type": "integer"
                 },
-                "shotType": {
+                "totals": {
+                    "$ref": "#/definitions/services.ShotBin"
+                }
+            }
+        },
+        "services.SimilarSeason": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "contributions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/services.FeatureContribution"
+                    }
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "playerId": {
                     "type": "string"
                 },
-                "team": {
+                "playerName": {
                     "type": "string"
                 },
-                "teamScore": {
+                "season": {
                     "type": "integer"
                 },
-                "timeRemaining": {
+                "team": {
                     "type": "string"
-                },
-                "top": {
-                    "type": "integer"
                 }
             }
         }
@@ -2,17 +2,82 @@ package main
 
 import (
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"github.com/nprasad2077/NBA_Go/models"
 	"github.com/nprasad2077/NBA_Go/services"
 	"github.com/nprasad2077/NBA_Go/utils"
 )
 
-// importPlayerAdvanced fetches and stores advanced stats for seasons 2017–2025
-func importPlayerAdvanced(db *gorm.DB) {
-	for season := 1991; season <= 2002; season++ {
-		if err := services.FetchAndStorePlayerAdvancedScrapedStats(db, season, false); err != nil {
+// importOptions are the flags shared by the import subcommands.
+type importOptions struct {
+	season   int
+	force    bool
+	provider services.StatsProvider
+	league   string
+}
+
+// parseImportArgs reads "<season>", "--force", "--provider=br|nba" and
+// "--league=NBA|WNBA|ABA" in any order.
+func parseImportArgs(args []string) importOptions {
+	opts := importOptions{}
+	providerName, leagueName := "", ""
+	for _, arg := range args {
+		switch {
+		case arg == "--force":
+			opts.force = true
+		case strings.HasPrefix(arg, "--provider="):
+			providerName = strings.TrimPrefix(arg, "--provider=")
+		case strings.HasPrefix(arg, "--league="):
+			leagueName = strings.TrimPrefix(arg, "--league=")
+		default:
+			season, err := strconv.Atoi(arg)
+			if err != nil {
+				log.Fatalf("invalid argument %q", arg)
+			}
+			opts.season = season
+		}
+	}
+
+	p, err := services.ProviderByName(providerName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.provider = p
+	if opts.league, err = services.ParseLeague(leagueName); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Using data provider %q for the %s", p.Name(), opts.league)
+	return opts
+}
+
+// defaultImportSeasons are the seasons import-data fetches per league when
+// no season is given: the ABA's whole history, the WNBA's since 1997.
+var defaultImportSeasons = map[string][2]int{
+	models.LeagueNBA:  {1991, 2002},
+	models.LeagueABA:  {1968, 1976},
+	models.LeagueWNBA: {1997, 2025},
+}
+
+// importSeasons is the first and last season to import: the given season,
+// or the league's default range.
+func importSeasons(opts importOptions) (first, last int) {
+	if opts.season != 0 {
+		return opts.season, opts.season
+	}
+	r := defaultImportSeasons[opts.league]
+	return r[0], r[1]
+}
+
+// importPlayerAdvanced fetches and stores regular-season advanced stats
+// for importSeasons
+func importPlayerAdvanced(db *gorm.DB, opts importOptions) {
+	first, last := importSeasons(opts)
+	for season := first; season <= last; season++ {
+		if err := services.ImportPlayerAdvanced(db, opts.provider, opts.league, season, false); err != nil {
 			log.Printf("advanced import failed for %d: %v", season, err)
 		}
 		log.Printf("Advanced import for season: %d", season)
@@ -21,10 +86,12 @@ func importPlayerAdvanced(db *gorm.DB) {
 	}
 }
 
-// importPlayerAdvancedPlayoffs fetches and stores advanced stats for playoffs seasons 2023–2025
-func importPlayerAdvancedPlayoffs(db *gorm.DB) {
-	for season := 1991; season <= 2002; season++ {
-		if err := services.FetchAndStorePlayerAdvancedScrapedStats(db, season, true); err != nil {
+// importPlayerAdvancedPlayoffs fetches and stores playoff advanced stats
+// for importSeasons
+func importPlayerAdvancedPlayoffs(db *gorm.DB, opts importOptions) {
+	first, last := importSeasons(opts)
+	for season := first; season <= last; season++ {
+		if err := services.ImportPlayerAdvanced(db, opts.provider, opts.league, season, true); err != nil {
 			log.Printf("advanced import failed for %d: %v", season, err)
 		}
 		log.Printf("Advanced Playoffs import for season: %d", season)
@@ -34,9 +101,11 @@ func importPlayerAdvancedPlayoffs(db *gorm.DB) {
 }
 
 // importPlayerTotalsScrape fetches & stores scraped regular-season total stats
-func importPlayerTotalsScrape(db *gorm.DB) {
-    for season := 1991; season <= 2002; season++ {
-        if err := services.FetchAndStorePlayerTotalScrapedStats(db, season, false); err != nil {
+// for importSeasons
+func importPlayerTotalsScrape(db *gorm.DB, opts importOptions) {
+    first, last := importSeasons(opts)
+    for season := first; season <= last; season++ {
+        if err := services.ImportPlayerTotals(db, opts.provider, opts.league, season, false); err != nil {
             log.Printf("scraped totals import failed for %d: %v", season, err)
         }
 		log.Printf("Player Totals import for season: %d", season)
@@ -46,9 +115,11 @@ func importPlayerTotalsScrape(db *gorm.DB) {
 }
 
 // importPlayerPlayoffsScrape fetches & stores scraped playoff total stats
-func importPlayerTotalsPlayoffsScrape(db *gorm.DB) {
-    for season := 1991; season <= 2002; season++ {
-        if err := services.FetchAndStorePlayerTotalScrapedStats(db, season, true); err != nil {
+// for importSeasons
+func importPlayerTotalsPlayoffsScrape(db *gorm.DB, opts importOptions) {
+    first, last := importSeasons(opts)
+    for season := first; season <= last; season++ {
+        if err := services.ImportPlayerTotals(db, opts.provider, opts.league, season, true); err != nil {
             log.Printf("scraped playoffs import failed for %d: %v", season, err)
         }
 		log.Printf("Player Playoffs Totals import for season: %d", season)
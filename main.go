@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -34,34 +35,86 @@ import (
 
 	"github.com/nprasad2077/NBA_Go/config"
 	"github.com/nprasad2077/NBA_Go/controllers"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"github.com/nprasad2077/NBA_Go/routes"
+	"github.com/nprasad2077/NBA_Go/services"
 	"github.com/nprasad2077/NBA_Go/utils/middleware"
 	_ "github.com/nprasad2077/NBA_Go/docs"
 )
 
 func main() {
-	// ——— One-off import-data mode ———
+	// ——— One-off import-data mode: import-data [<season>] [--provider=br|nba] [--league=NBA|WNBA|ABA] ———
 	if len(os.Args) > 1 && os.Args[1] == "import-data" {
+		opts := parseImportArgs(os.Args[2:])
+
 		// Run all migrations + import steps exactly once
 		db := config.InitDB(true)
 
-		importPlayerAdvanced(db)
+		importPlayerAdvanced(db, opts)
 		log.Println("🎉 Player Advanced Import completed successfully")
 
-		importPlayerAdvancedPlayoffs(db)
+		importPlayerAdvancedPlayoffs(db, opts)
 		log.Println("🎉 Player Advanced Playoffs Import completed successfully")
 
-		importPlayerTotalsScrape(db)
+		importPlayerTotalsScrape(db, opts)
 		log.Println("🎉 Player Totals (scraped) Import completed successfully")
 
-		importPlayerTotalsPlayoffsScrape(db)
+		importPlayerTotalsPlayoffsScrape(db, opts)
 		log.Println("🎉 Player Playoffs (scraped) Import completed successfully")
 
 		log.Println("🏀 ALL Imports completed successfully ✅ 🙌")
 		return
 	}
 
-	// ——— Normal API startup: skip AutoMigrate ———
+	// ——— Season-wide shot chart import: import-shotcharts <season> [--force] [--provider=br|nba] ———
+	if len(os.Args) > 1 && os.Args[1] == "import-shotcharts" {
+		const usage = "usage: import-shotcharts <season> [--force] [--provider=br|nba]"
+		for _, arg := range os.Args[2:] {
+			if strings.HasPrefix(arg, "--league=") {
+				log.Fatalf("shot charts are NBA-only; --league is not supported\n%s", usage)
+			}
+		}
+		opts := parseImportArgs(os.Args[2:])
+		if opts.season == 0 {
+			log.Fatal(usage)
+		}
+
+		db := config.InitDB(true)
+		if _, err := services.ImportSeasonShotCharts(db, opts.provider, opts.season, opts.force); err != nil {
+			log.Fatalf("shot chart import for %d: %v", opts.season, err)
+		}
+		log.Printf("🎉 Shot chart import for %d completed", opts.season)
+		return
+	}
+
+	// ——— Player ID mapping: map-player-ids [mappings.csv] ———
+	if len(os.Args) > 1 && os.Args[1] == "map-player-ids" {
+		db := config.InitDB(true)
+		if len(os.Args) > 2 {
+			f, err := os.Open(os.Args[2])
+			if err != nil {
+				log.Fatalf("open %s: %v", os.Args[2], err)
+			}
+			n, err := services.ImportPlayerIDMappingsCSV(db, f)
+			f.Close()
+			if err != nil {
+				log.Fatalf("import %s: %v", os.Args[2], err)
+			}
+			log.Printf("🔗 Imported %d player ID mappings from %s", n, os.Args[2])
+		}
+		if _, err := services.InferPlayerIDMappings(db); err != nil {
+			log.Fatalf("infer player ID mappings: %v", err)
+		}
+		return
+	}
+
+	// ——— Schema migrations: migrate up | down [n] | status ———
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(config.InitDB(false), os.Args[2:])
+		return
+	}
+
+	// ——— Normal API startup: no migrations, but check the schema version ———
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -86,18 +139,25 @@ func main() {
 
 	// DB connection (no migrations on API startup)
 	db := config.InitDB(false)
+	checkSchemaVersion(db)
+	repos := repository.NewGorm(db)
 
 	/* ---------- PUBLIC ROUTES (no API key) ---------- */
 	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 	app.Get("/swagger/*", fiberswagger.WrapHandler)
-	controllers.RegisterKeyAdminRoutes(app, db)
+	controllers.RegisterKeyAdminRoutes(app, repos.APIKeys)
+	controllers.RegisterDataQualityRoutes(app, db)
+	controllers.RegisterPlayerIDMappingRoutes(app, db)
 
 	/* ---------- PROTECTED ROUTES ---------- */
 	// Remove Comment to re-enable api key middleware.
-	// app.Use(middleware.APIKeyAuth(db))
-	routes.RegisterPlayerAdvancedRoutes(app, db)
-	routes.RegisterPlayerTotalRoutes(app, db)
-	routes.RegisterPlayerShotChartRoutes(app, db)
+	// app.Use(middleware.APIKeyAuth(repos.APIKeys))
+	routes.RegisterPlayerAdvancedRoutes(app, db, repos)
+	routes.RegisterPlayerTotalRoutes(app, db, repos)
+	routes.RegisterPlayerShotChartRoutes(app, db, repos)
+	routes.RegisterPlayerRoutes(app, repos)
+	routes.RegisterLeaderRoutes(app, repos)
+	routes.RegisterCompareRoutes(app, repos)
 
 	/* ---------- START & SHUTDOWN ---------- */
 	go func() {
@@ -2,17 +2,27 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"github.com/nprasad2077/NBA_Go/config"
+	"github.com/nprasad2077/NBA_Go/controllers"
 	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"github.com/nprasad2077/NBA_Go/routes"
+	"github.com/nprasad2077/NBA_Go/services"
+	"github.com/nprasad2077/NBA_Go/utils/middleware"
 	"github.com/nprasad2077/NBA_Go/utils/security"
 )
 
@@ -34,11 +44,51 @@ func setupTestApp() (*fiber.App, string) {
 	db.Create(&models.APIKey{Hash: security.HashKey(rawKey)})
 
 	// register only the routes we need
-	routes.RegisterPlayerAdvancedRoutes(app, db)
+	routes.RegisterPlayerAdvancedRoutes(app, db, repository.NewGorm(db))
 
 	return app, rawKey
 }
 
+// getJSON GETs route and, on a 200, decodes the body into a T.
+func getJSON[T any](t *testing.T, app *fiber.App, route string) (int, T) {
+	t.Helper()
+	var body T
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, route, nil), -1)
+	if !assert.NoError(t, err, route) {
+		return 0, body
+	}
+	if resp.StatusCode == 200 {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body), route)
+	}
+	return resp.StatusCode, body
+}
+
+// getRows GETs a list route and returns its data rows.
+func getRows(t *testing.T, app *fiber.App, route string) (int, []map[string]interface{}) {
+	t.Helper()
+	code, body := getJSON[struct {
+		Data []map[string]interface{} `json:"data"`
+	}](t, app, route)
+	return code, body.Data
+}
+
+// nbaTotal is an NBA regular-season totals row with the shooting line the
+// leader and compare tests rank on.
+func nbaTotal(id uint, player, team string, season, age, games, fg int, pct float64, points int) models.PlayerTotalStat {
+	return models.PlayerTotalStat{
+		ID: id, League: models.LeagueNBA, PlayerID: player, PlayerName: player + " name", Team: team,
+		Season: season, Age: age, Games: games, FieldGoals: fg, FieldPercent: pct, Points: points,
+	}
+}
+
+// nbaAdvanced is an NBA regular-season advanced row for a player's minutes.
+func nbaAdvanced(id uint, player, team string, season, age, games, minutes int) models.PlayerAdvancedStat {
+	return models.PlayerAdvancedStat{
+		ID: id, League: models.LeagueNBA, PlayerID: player, PlayerName: player + " name", Team: team,
+		Season: season, Age: age, Games: games, MinutesPlayed: minutes,
+	}
+}
+
 // -----------------------------------------------------------------------------
 // actual test
 // -----------------------------------------------------------------------------
@@ -77,3 +127,992 @@ func TestGetPlayerAdvancedStats(t *testing.T) {
 		assert.Contains(t, string(body), tc.wantSubstring, tc.name)
 	}
 }
+
+// -----------------------------------------------------------------------------
+// traded players: TOT / per-team rows
+// -----------------------------------------------------------------------------
+func TestPlayerTotalsTeamMode(t *testing.T) {
+	app := fiber.New()
+	db, err := gorm.Open(sqlite.Open("file:teammode?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	_ = db.AutoMigrate(&models.PlayerTotalStat{})
+	db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "hardeja01", Team: "TOT", Season: 2021, Points: 1800, IsAggregate: true},
+		{PlayerID: "hardeja01", Team: "HOU", Season: 2021, Points: 200},
+		{PlayerID: "hardeja01", Team: "BRK", Season: 2021, Points: 1600},
+		{PlayerID: "curryst01", Team: "GSW", Season: 2021, Points: 2000},
+		{League: "WNBA", PlayerID: "wilsoa01w", Team: "LVA", Season: 2021, Points: 500},
+	})
+	routes.RegisterPlayerTotalRoutes(app, db, repository.NewGorm(db))
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantRows int
+	}{
+		{"default is combined", "", 200, 2},
+		{"combined", "teamMode=combined", 200, 2},
+		{"split", "teamMode=split", 200, 3},
+		{"both", "teamMode=both", 200, 4},
+		{"team filter drops aggregates", "teamMode=both&team=TOT", 200, 0},
+		{"team filter keeps team rows", "team=BRK", 200, 1},
+		{"invalid mode", "teamMode=bogus", 400, 0},
+		{"other league", "league=wnba", 200, 1},
+		{"invalid league", "league=XBA", 400, 0},
+	}
+
+	for _, tc := range tests {
+		req, _ := http.NewRequest(http.MethodGet, "/api/playertotals/?season=2021&"+tc.query, nil)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.wantCode, resp.StatusCode, tc.name)
+		if tc.wantCode != 200 {
+			continue
+		}
+
+		var body struct {
+			Data []models.PlayerTotalStat `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body), tc.name)
+		assert.Len(t, body.Data, tc.wantRows, tc.name)
+	}
+}
+
+func TestPlayerEndpointsAcceptEitherIDStyle(t *testing.T) {
+	app := fiber.New()
+	db, err := gorm.Open(sqlite.Open("file:playerids?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	_ = db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerIDMapping{})
+	db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "hardeja01", Team: "HOU", Season: 2019, Points: 2818},
+		{PlayerID: "201935", Team: "LAC", Season: 2024, Points: 1341},
+		{PlayerID: "curryst01", Team: "GSW", Season: 2024, Points: 1956},
+	})
+	db.Create(&models.PlayerIDMapping{
+		BRID: "hardeja01", NBAID: "201935", Source: models.MappingSourceCSV,
+		Confidence: 1, Status: models.MappingConfirmed,
+	})
+	routes.RegisterPlayerTotalRoutes(app, db, repository.NewGorm(db))
+
+	for _, id := range []string{"hardeja01", "201935"} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/playertotals/?playerId="+id, nil)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err, id)
+		assert.Equal(t, 200, resp.StatusCode, id)
+
+		var body struct {
+			Data []models.PlayerTotalStat `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body), id)
+		assert.Len(t, body.Data, 2, id)
+	}
+}
+
+func TestPlayerCareerEndpoints(t *testing.T) {
+	app := fiber.New()
+	db, err := gorm.Open(sqlite.Open("file:career?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	_ = db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerCareerStat{}, &models.PlayerIDMapping{})
+	db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "hardeja01", PlayerName: "James Harden", Team: "TOT", Season: 2021, Games: 44, Points: 1090, IsAggregate: true},
+		{PlayerID: "hardeja01", Team: "HOU", Season: 2021, Games: 8, Points: 198},
+		{PlayerID: "hardeja01", Team: "BRK", Season: 2021, Games: 36, Points: 892},
+		{PlayerID: "hardeja01", Team: "BRK", Season: 2021, Games: 9, Points: 184, IsPlayoff: true},
+		{PlayerID: "curryst01", Team: "GSW", Season: 2021, Games: 63, Points: 2015},
+	})
+	db.Create(&models.PlayerIDMapping{
+		BRID: "hardeja01", NBAID: "201935", Source: models.MappingSourceCSV,
+		Confidence: 1, Status: models.MappingConfirmed,
+	})
+	assert.NoError(t, services.RebuildCareerStats(db, models.LeagueNBA))
+	routes.RegisterPlayerRoutes(app, repository.NewGorm(db))
+
+	// Either ID style finds the BR-keyed career.
+	for _, id := range []string{"hardeja01", "201935"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/players/"+id+"/career", nil), -1)
+		assert.NoError(t, err, id)
+		assert.Equal(t, 200, resp.StatusCode, id)
+
+		var body struct {
+			PlayerID      string                   `json:"playerId"`
+			RegularSeason *models.PlayerCareerStat `json:"regularSeason"`
+			Playoffs      *models.PlayerCareerStat `json:"playoffs"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body), id)
+		assert.Equal(t, "hardeja01", body.PlayerID, id)
+		if assert.NotNil(t, body.RegularSeason, id) {
+			assert.Equal(t, 1090, body.RegularSeason.Points, id)
+			assert.Equal(t, "HOU,BRK", body.RegularSeason.Teams, id)
+		}
+		if assert.NotNil(t, body.Playoffs, id) {
+			assert.Equal(t, 184, body.Playoffs.Points, id)
+		}
+	}
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/api/players/nobody01/career", nil), -1)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	// Search returns IDs the career endpoint accepts.
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/api/players/search?q=hardn", nil), -1)
+	assert.Equal(t, 200, resp.StatusCode)
+	var search struct {
+		Data []services.PlayerSearchResult `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&search))
+	if assert.Len(t, search.Data, 1) {
+		assert.Equal(t, "hardeja01", search.Data[0].PlayerID)
+	}
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/api/players/search", nil), -1)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	tests := []struct {
+		query     string
+		wantCode  int
+		wantFirst string
+	}{
+		{"", 200, "curryst01"},
+		{"ascending=true", 200, "hardeja01"},
+		{"sortBy=games&minGames=50", 200, "curryst01"},
+		{"sortBy=bogus", 400, ""},
+		{"limit=1", 200, "curryst01"},
+		{"pageSize=0", 400, ""},
+		{"page=0", 400, ""},
+	}
+	for _, tc := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/players/career?"+tc.query, nil), -1)
+		assert.NoError(t, err, tc.query)
+		assert.Equal(t, tc.wantCode, resp.StatusCode, tc.query)
+		if tc.wantCode != 200 {
+			continue
+		}
+		var body struct {
+			Data []models.PlayerCareerStat `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body), tc.query)
+		if assert.NotEmpty(t, body.Data, tc.query) {
+			assert.Equal(t, tc.wantFirst, body.Data[0].PlayerID, tc.query)
+		}
+	}
+
+	// Keyset pages walk the leaderboard without repeats.
+	type page struct {
+		Data       []models.PlayerCareerStat `json:"data"`
+		Pagination map[string]interface{}    `json:"pagination"`
+	}
+	_, first := getJSON[page](t, app, "/api/players/career?limit=1")
+	next, _ := first.Pagination["nextCursor"].(string)
+	if assert.NotEmpty(t, next) {
+		code, second := getJSON[page](t, app, "/api/players/career?limit=1&cursor="+next)
+		assert.Equal(t, 200, code)
+		if assert.Len(t, second.Data, 1) {
+			assert.Equal(t, "hardeja01", second.Data[0].PlayerID)
+		}
+		assert.Nil(t, second.Pagination["nextCursor"])
+	}
+}
+
+func TestSparseFieldsAndInclude(t *testing.T) {
+	app := fiber.New()
+	db, err := gorm.Open(sqlite.Open("file:sparse?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	_ = db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerAdvancedStat{}, &models.PlayerIDMapping{})
+	db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "curryst01", PlayerName: "Stephen Curry", Team: "GSW", Season: 2024, Points: 1956},
+		{PlayerID: "curryst01", PlayerName: "Stephen Curry", Team: "GSW", Season: 2024, Points: 300, IsPlayoff: true},
+	})
+	db.Create(&[]models.PlayerAdvancedStat{
+		{PlayerID: "curryst01", PlayerName: "Stephen Curry", Team: "GSW", Season: 2024, PER: 21.3, VORP: 3.4},
+		{PlayerID: "jokicni01", PlayerName: "Nikola Jokic", Team: "DEN", Season: 2024, PER: 31.0, VORP: 10.6},
+	})
+	repos := repository.NewGorm(db)
+	routes.RegisterPlayerAdvancedRoutes(app, db, repos)
+	routes.RegisterPlayerTotalRoutes(app, db, repos)
+
+	code, rows := getRows(t, app, "/api/playeradvancedstats/?season=2024&sortBy=per&fields=playerName,team,per,vorp")
+	assert.Equal(t, 200, code)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, map[string]interface{}{"playerName": "Nikola Jokic", "team": "DEN", "per": 31.0, "vorp": 10.6}, rows[0])
+	}
+
+	code, rows = getRows(t, app, "/api/playeradvancedstats/?season=2024&sortBy=per&ascending=true&fields=playerName,per&include=totals")
+	assert.Equal(t, 200, code)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "Stephen Curry", rows[0]["playerName"])
+		// Joined on player, season, team and playoff flag: the regular-season row.
+		if totals, ok := rows[0]["totals"].(map[string]interface{}); assert.True(t, ok) {
+			assert.Equal(t, 1956.0, totals["points"])
+		}
+		assert.Nil(t, rows[1]["totals"])
+		assert.NotContains(t, rows[0], "vorp")
+	}
+
+	code, rows = getRows(t, app, "/api/playertotals/?season=2024&isPlayoff=true&include=advanced")
+	assert.Equal(t, 200, code)
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, 300.0, rows[0]["points"])
+		assert.Nil(t, rows[0]["advanced"])
+	}
+
+	for _, bad := range []string{
+		"/api/playeradvancedstats/?fields=playerName,bogus",
+		"/api/playeradvancedstats/?include=shots",
+		"/api/playertotals/?include=totals",
+	} {
+		code, _ := getRows(t, app, bad)
+		assert.Equal(t, 400, code, bad)
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	app := fiber.New()
+	db, err := gorm.Open(sqlite.Open("file:cursor?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	_ = db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerAdvancedStat{}, &models.PlayerShotChart{}, &models.PlayerIDMapping{})
+	// Ties on points, so only the ID tie-breaker keeps pages apart.
+	for i, pts := range []int{1500, 2000, 1500, 1000, 1500} {
+		db.Create(&models.PlayerTotalStat{PlayerID: fmt.Sprintf("player%02d", i), Team: "GSW", Season: 2024, Points: pts})
+	}
+	for i := 0; i < 3; i++ {
+		db.Create(&models.PlayerShotChart{PlayerID: "curryst01", Season: 2024, Quarter: fmt.Sprint(i + 1), Period: i + 1})
+	}
+	repos := repository.NewGorm(db)
+	routes.RegisterPlayerTotalRoutes(app, db, repos)
+	routes.RegisterPlayerShotChartRoutes(app, db, repos)
+
+	type page struct {
+		Data []struct {
+			PlayerID string `json:"playerId"`
+			Points   int    `json:"points"`
+		} `json:"data"`
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+
+	var seen []string
+	route := "/api/playertotals/?sortBy=points&limit=2"
+	for pages := 0; ; pages++ {
+		code, body := getJSON[page](t, app, route)
+		if !assert.Equal(t, 200, code, route) || pages > 3 {
+			break
+		}
+		if pages == 0 {
+			assert.EqualValues(t, 5, body.Pagination["total"])
+		} else {
+			assert.NotContains(t, body.Pagination, "total")
+		}
+		for _, r := range body.Data {
+			seen = append(seen, fmt.Sprintf("%s:%d", r.PlayerID, r.Points))
+		}
+		next, _ := body.Pagination["nextCursor"].(string)
+		if next == "" {
+			break
+		}
+		// A row inserted mid-walk ahead of the cursor does not shift later pages.
+		db.Create(&models.PlayerTotalStat{PlayerID: fmt.Sprintf("late%d", pages), Team: "GSW", Season: 2024, Points: 3000})
+		route = "/api/playertotals/?sortBy=points&limit=2&includeTotal=false&cursor=" + next
+	}
+	assert.Equal(t, []string{"player01:2000", "player04:1500", "player02:1500", "player00:1500", "player03:1000"}, seen)
+
+	// A cursor is bound to the sort it was issued for.
+	_, body := getJSON[page](t, app, "/api/playertotals/?sortBy=points&limit=1")
+	next := body.Pagination["nextCursor"].(string)
+	code, _ := getJSON[page](t, app, "/api/playertotals/?sortBy=assists&limit=1&cursor="+next)
+	assert.Equal(t, 400, code)
+	code, _ = getJSON[page](t, app, "/api/playertotals/?cursor=garbage")
+	assert.Equal(t, 400, code)
+	for _, bad := range []string{"pageSize=0", "pageSize=1001", "page=0", "page=-1"} {
+		code, _ = getJSON[page](t, app, "/api/playertotals/?"+bad)
+		assert.Equal(t, 400, code, bad)
+	}
+	code, _ = getJSON[page](t, app, "/api/playershotchart/?playerId=curryst01&pageSize=0")
+	assert.Equal(t, 400, code)
+
+	// page/pageSize still works and can skip the count.
+	_, body = getJSON[page](t, app, "/api/playertotals/?page=2&pageSize=2&includeTotal=false")
+	assert.Len(t, body.Data, 2)
+	assert.NotContains(t, body.Pagination, "total")
+
+	// Shots page like the other lists.
+	_, body = getJSON[page](t, app, "/api/playershotchart/?playerId=curryst01&limit=2")
+	assert.Len(t, body.Data, 2)
+	next = body.Pagination["nextCursor"].(string)
+	_, body = getJSON[page](t, app, "/api/playershotchart/?playerId=curryst01&limit=2&cursor="+next)
+	assert.Len(t, body.Data, 1)
+	assert.Nil(t, body.Pagination["nextCursor"])
+	_, body = getJSON[page](t, app, "/api/playershotchart/?playerId=curryst01&quarter=2,3&page=2&pageSize=1")
+	assert.Len(t, body.Data, 1)
+	assert.EqualValues(t, 2, body.Pagination["total"])
+	assert.EqualValues(t, 2, body.Pagination["pages"])
+	// Without paging parameters, the first page of the default size.
+	code, body = getJSON[page](t, app, "/api/playershotchart/?playerId=curryst01")
+	assert.Equal(t, 200, code)
+	assert.Len(t, body.Data, 3)
+	assert.EqualValues(t, 20, body.Pagination["pageSize"])
+	assert.EqualValues(t, 3, body.Pagination["total"])
+}
+
+func TestShotChartAggregate(t *testing.T) {
+	app := fiber.New()
+	db, err := gorm.Open(sqlite.Open("file:aggregate?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+	_ = db.AutoMigrate(&models.PlayerShotChart{}, &models.PlayerIDMapping{})
+	for i, s := range []struct {
+		player string
+		dist   int
+		made   bool
+	}{
+		{"curryst01", 25, true}, {"curryst01", 26, false}, {"curryst01", 2, true},
+		{"jamesle01", 24, false}, {"jamesle01", 1, true}, {"jamesle01", 1, false},
+	} {
+		shotType := "2-pointer"
+		if s.dist >= 22 {
+			shotType = "3-pointer"
+		}
+		db.Create(&models.PlayerShotChart{
+			PlayerID: s.player, Season: 2024, Quarter: fmt.Sprint(i), Team: "GSW",
+			DistanceFt: s.dist, Result: s.made, ShotType: shotType,
+		})
+	}
+	routes.RegisterPlayerShotChartRoutes(app, db, repository.NewGorm(db))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/playershotchart/aggregate?playerId=curryst01&bin=distance&size=3&withLeague=true", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	var body controllers.ShotAggregateResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 3, body.Totals.Attempts)
+	assert.Equal(t, 1.667, body.Totals.PointsPerShot)
+	if assert.Len(t, body.Bins, 2) {
+		assert.Equal(t, "0-2", body.Bins[0].Key)
+		assert.Equal(t, 1.0, body.Bins[0].FGPercent)
+		if assert.NotNil(t, body.Bins[0].LeagueFGPercent) {
+			assert.Equal(t, 0.667, *body.Bins[0].LeagueFGPercent)
+		}
+		assert.Equal(t, "24-26", body.Bins[1].Key)
+		assert.Equal(t, 0.333, *body.Bins[1].LeagueFGPercent)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/playershotchart/aggregate?season=2024&bin=grid&minAttempts=2", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	for _, bad := range []string{
+		"/api/playershotchart/aggregate?bin=distance",
+		"/api/playershotchart/aggregate?season=2024&bin=triangle",
+		"/api/playershotchart/aggregate?season=2024&size=-1",
+		"/api/playershotchart/aggregate?season=2024&bin=distance&size=2.5",
+		"/api/playershotchart/aggregate?season=2024&result=maybe",
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, bad, nil), -1)
+		assert.NoError(t, err, bad)
+		assert.Equal(t, 400, resp.StatusCode, bad)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// handlers over the in-memory repositories: no database involved
+// -----------------------------------------------------------------------------
+func TestHandlersWithMemoryRepositories(t *testing.T) {
+	mem := repository.NewMemory()
+	mem.Totals = []models.PlayerTotalStat{
+		{League: models.LeagueNBA, PlayerID: "hardeja01", Team: "HOU", Season: 2019, Points: 2818},
+		{League: models.LeagueNBA, PlayerID: "201935", Team: "LAC", Season: 2024, Points: 1341},
+		{League: models.LeagueNBA, PlayerID: "curryst01", Team: "GSW", Season: 2024, Points: 1956},
+	}
+	mem.Advanced = []models.PlayerAdvancedStat{
+		{League: models.LeagueNBA, PlayerID: "curryst01", Team: "GSW", Season: 2024, WinShares: 7.2},
+	}
+	mem.Shots = []models.PlayerShotChart{
+		{League: models.LeagueNBA, PlayerID: "curryst01", Season: 2024, Zone: services.ZoneAboveBreak3},
+		{League: models.LeagueNBA, PlayerID: "curryst01", Season: 2024, Zone: services.ZoneRestrictedArea},
+	}
+	mem.Careers = []models.PlayerCareerStat{
+		{ID: 1, League: models.LeagueNBA, PlayerID: "curryst01", PlayerName: "Stephen Curry", SearchName: "stephen curry", Games: 956, Points: 23668},
+		{ID: 2, League: models.LeagueNBA, PlayerID: "curryst01", PlayerName: "Stephen Curry", SearchName: "stephen curry", Games: 150, Points: 4000, IsPlayoff: true},
+		{ID: 3, League: models.LeagueNBA, PlayerID: "hardeja01", PlayerName: "James Harden", SearchName: "james harden", Games: 1138, Points: 27000},
+	}
+	mem.IDMappings["hardeja01"] = []string{"201935"}
+	mem.Keys = []models.APIKey{{ID: 1, Hash: security.HashKey("live")}}
+	repos := mem.Repositories()
+
+	app := fiber.New()
+	app.Use(middleware.APIKeyAuth(repos.APIKeys))
+	app.Get("/totals", controllers.GetPlayerTotalStats(repos.Totals, repos.Advanced, repos.Percentiles, repos.PlayerIDs))
+	app.Get("/advanced", controllers.GetAllAdvancedPlayerStats(repos.Advanced, repos.Totals, repos.Percentiles, repos.PlayerIDs))
+	app.Get("/shots", controllers.GetPlayerShotChart(repos.Shots, repos.PlayerIDs))
+	app.Get("/search", controllers.SearchPlayers(repos.Careers))
+	app.Get("/career", controllers.GetCareerLeaders(repos.Careers))
+	app.Get("/career/:id", controllers.GetPlayerCareer(repos.Careers, repos.PlayerIDs))
+
+	tests := []struct {
+		route    string
+		key      string
+		wantCode int
+		wantRows int
+	}{
+		{"/totals?playerId=201935", "live", 200, 2},
+		{"/totals?sortBy=points&pageSize=1", "live", 200, 1},
+		{"/totals?teamMode=bogus", "live", 400, 0},
+		{"/totals?filter=points:gte:1500,season:between:2020:2024", "live", 200, 1},
+		{"/totals?filter=team:in:HOU|LAC", "live", 200, 2},
+		{"/totals?filter=bogus:gte:1", "live", 400, 0},
+		{"/totals?filter=points:like:1", "live", 400, 0},
+		{"/advanced?filter=winShares:gt:5", "live", 200, 1},
+		{"/totals", "nope", 401, 0},
+		{"/advanced?season=2024", "live", 200, 1},
+		{"/shots?playerId=curryst01&zone=" + services.ZoneAboveBreak3, "live", 200, 1},
+		{"/shots?zone=Nowhere", "live", 400, 0},
+		{"/shots?quarter=5", "live", 400, 0},
+		{"/shots?result=maybe", "live", 400, 0},
+		{"/shots?shotType=4", "live", 400, 0},
+		{"/shots?dateFrom=2024-13-01", "live", 400, 0},
+		{"/shots?scoreState=winning", "live", 400, 0},
+		{"/search?q=curry", "live", 200, 1},
+		{"/search?q=.", "live", 400, 0},
+		{"/career?minGames=1000", "live", 200, 1},
+		{"/career?sortBy=bogus", "live", 400, 0},
+		{"/career/nobody01", "live", 404, 0},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodGet, tc.route, nil)
+		req.Header.Set("X-API-Key", tc.key)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err, tc.route)
+		assert.Equal(t, tc.wantCode, resp.StatusCode, tc.route)
+		if tc.wantCode != 200 {
+			continue
+		}
+
+		var body struct {
+			Data []json.RawMessage `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body), tc.route)
+		assert.Len(t, body.Data, tc.wantRows, tc.route)
+	}
+
+	// A career resolves across ID styles.
+	req := httptest.NewRequest(http.MethodGet, "/career/201935", nil)
+	req.Header.Set("X-API-Key", "live")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	var career controllers.PlayerCareerResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&career))
+	assert.Equal(t, "hardeja01", career.PlayerID)
+	assert.NotNil(t, career.RegularSeason)
+	assert.Nil(t, career.Playoffs)
+}
+
+func TestPlayerProfile(t *testing.T) {
+	mem := repository.NewMemory()
+	mem.Totals = []models.PlayerTotalStat{
+		{League: models.LeagueNBA, PlayerID: "hardeja01", PlayerName: "James Harden", Team: "HOU", Season: 2019, Points: 2818},
+		{League: models.LeagueNBA, PlayerID: "hardeja01", PlayerName: "James Harden", Team: "TOT", Season: 2021, Points: 1800, IsAggregate: true},
+		{League: models.LeagueNBA, PlayerID: "hardeja01", PlayerName: "James Harden", Team: "BRK", Season: 2021, Points: 1600},
+		{League: models.LeagueNBA, PlayerID: "hardeja01", PlayerName: "James Harden", Team: "HOU", Season: 2021, Points: 200},
+		{League: models.LeagueNBA, PlayerID: "201935", PlayerName: "James Harden", Team: "LAC", Season: 2024, Points: 1341},
+		{League: models.LeagueNBA, PlayerID: "201935", PlayerName: "James Harden", Team: "LAC", Season: 2024, Points: 150, IsPlayoff: true},
+	}
+	mem.Advanced = []models.PlayerAdvancedStat{
+		{League: models.LeagueNBA, PlayerID: "hardeja01", Team: "HOU", Season: 2019, PER: 30.6},
+		{League: models.LeagueNBA, PlayerID: "hardeja01", Team: "TOT", Season: 2021, PER: 23.2, IsAggregate: true},
+	}
+	mem.Shots = []models.PlayerShotChart{
+		{League: models.LeagueNBA, PlayerID: "201935", Season: 2024, DistanceFt: 26, ShotType: "3-pointer", Result: true},
+		{League: models.LeagueNBA, PlayerID: "201935", Season: 2024, DistanceFt: 1, ShotType: "2-pointer"},
+		{League: models.LeagueNBA, PlayerID: "hardeja01", Season: 2019, DistanceFt: 1, ShotType: "2-pointer"},
+	}
+	mem.IDMappings["hardeja01"] = []string{"201935"}
+	repos := mem.Repositories()
+
+	app := fiber.New()
+	app.Get("/players/:id/profile", controllers.GetPlayerProfile(repos.Totals, repos.Advanced, repos.Shots, repos.PlayerIDs))
+
+	code, body := getJSON[controllers.PlayerProfileResponse](t, app, "/players/201935/profile")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "James Harden", body.PlayerName)
+	if assert.Len(t, body.Seasons, 6) {
+		assert.Equal(t, 30.6, body.Seasons[0].Advanced.PER)
+		assert.Equal(t, 2818, body.Seasons[0].Totals.Points)
+		// The traded season: aggregate first, with both datasets merged.
+		assert.Equal(t, "TOT", body.Seasons[1].Team)
+		assert.Equal(t, 23.2, body.Seasons[1].Advanced.PER)
+		assert.Equal(t, "BRK", body.Seasons[2].Team)
+		assert.Nil(t, body.Seasons[2].Advanced)
+		assert.True(t, body.Seasons[5].IsPlayoff)
+	}
+	if assert.Len(t, body.Shots, 2) {
+		assert.Equal(t, 2, body.Shots[1].Totals.Attempts)
+		assert.Equal(t, 1.5, body.Shots[1].Totals.PointsPerShot)
+	}
+
+	code, body = getJSON[controllers.PlayerProfileResponse](t, app, "/players/hardeja01/profile?fromSeason=2020&toSeason=2021")
+	assert.Equal(t, 200, code)
+	assert.Len(t, body.Seasons, 3)
+	assert.Empty(t, body.Shots)
+
+	code, _ = getJSON[controllers.PlayerProfileResponse](t, app, "/players/nobody01/profile")
+	assert.Equal(t, 404, code)
+	code, _ = getJSON[controllers.PlayerProfileResponse](t, app, "/players/hardeja01/profile?fromSeason=2024&toSeason=2020")
+	assert.Equal(t, 400, code)
+}
+
+func TestTotalsPerMode(t *testing.T) {
+	mem := repository.NewMemory()
+	mem.Totals = []models.PlayerTotalStat{
+		{ID: 1, League: models.LeagueNBA, PlayerID: "a", Season: 2024, Games: 80, MinutesPG: 2400, Points: 2000, FieldPercent: 0.5},
+		{ID: 2, League: models.LeagueNBA, PlayerID: "b", Season: 2024, Games: 50, MinutesPG: 1000, Points: 1500, FieldPercent: 0.4},
+		{ID: 3, League: models.LeagueNBA, PlayerID: "c", Season: 2024, Games: 0, MinutesPG: 0, Points: 0},
+		{ID: 4, League: models.LeagueNBA, PlayerID: "d", Season: 2024, Games: 20, MinutesPG: 700, Points: 100},
+	}
+	repos := mem.Repositories()
+	app := fiber.New()
+	app.Get("/playertotals", controllers.GetPlayerTotalStats(repos.Totals, repos.Advanced, repos.Percentiles, repos.PlayerIDs))
+
+	code, rows := getRows(t, app, "/playertotals?sortBy=points")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "a", rows[0]["playerId"])
+	assert.EqualValues(t, 2000, rows[0]["points"])
+
+	// b scores more per game and per minute, so it sorts first.
+	code, rows = getRows(t, app, "/playertotals?sortBy=points&perMode=PerGame")
+	assert.Equal(t, 200, code)
+	if assert.Len(t, rows, 4) {
+		assert.Equal(t, "b", rows[0]["playerId"])
+		assert.Equal(t, 30.0, rows[0]["points"])
+		assert.Equal(t, 20.0, rows[0]["minutesPg"]) // minutes per game
+		assert.Equal(t, 0.4, rows[0]["fieldPercent"])
+		assert.EqualValues(t, 50, rows[0]["games"])
+		assert.Equal(t, 0.0, rows[3]["points"]) // no games
+	}
+
+	// Per game, minutes rank by minutes per game too: d plays the fewest
+	// minutes but the most per game.
+	_, rows = getRows(t, app, "/playertotals?sortBy=minutesPg&perMode=PerGame&fields=playerId,minutesPg")
+	if assert.Len(t, rows, 4) {
+		assert.Equal(t, "d", rows[0]["playerId"])
+		assert.Equal(t, 35.0, rows[0]["minutesPg"])
+		assert.Equal(t, "a", rows[1]["playerId"])
+	}
+	_, rows = getRows(t, app, "/playertotals?sortBy=minutesPg&perMode=Per36&fields=playerId,minutesPg")
+	if assert.Len(t, rows, 4) {
+		assert.Equal(t, "a", rows[0]["playerId"]) // season minutes
+	}
+
+	_, rows = getRows(t, app, "/playertotals?sortBy=points&perMode=per36&fields=playerId,points,minutesPg")
+	if assert.Len(t, rows, 4) {
+		assert.Equal(t, 54.0, rows[0]["points"])
+		assert.Equal(t, 1000.0, rows[0]["minutesPg"]) // still the season's minutes
+		assert.NotContains(t, rows[0], "games")
+	}
+
+	_, rows = getRows(t, app, "/playertotals?sortBy=points&perMode=Per48&ascending=true&limit=1")
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, "c", rows[0]["playerId"])
+	}
+
+	code, _ = getRows(t, app, "/playertotals?perMode=Per100")
+	assert.Equal(t, 400, code)
+}
+
+func TestComparePlayers(t *testing.T) {
+	mem := repository.NewMemory()
+	mem.Totals = []models.PlayerTotalStat{
+		nbaTotal(1, "curryst01", "GSW", 2010, 21, 80, 500, 0.46, 1400),
+		nbaTotal(2, "curryst01", "GSW", 2016, 27, 79, 800, 0.50, 2375),
+		nbaTotal(3, "curryst01", "GSW", 2012, 23, 26, 100, 0.60, 400), // too few makes for a FG% peak
+		nbaTotal(4, "duranke01", "GSW", 2008, 19, 80, 600, 0.43, 1624),
+		nbaTotal(5, "duranke01", "GSW", 2010, 21, 82, 800, 0.48, 2472),
+	}
+	playoffs := []models.PlayerTotalStat{nbaTotal(6, "curryst01", "GSW", 2016, 27, 18, 150, 0.44, 460), nbaTotal(7, "duranke01", "GSW", 2010, 21, 6, 50, 0.35, 154)}
+	for i := range playoffs {
+		playoffs[i].IsPlayoff = true
+	}
+	mem.Totals = append(mem.Totals, playoffs...)
+	mem.Advanced = []models.PlayerAdvancedStat{
+		{ID: 1, League: models.LeagueNBA, PlayerID: "curryst01", Season: 2016, Age: 27, Team: "GSW", MinutesPlayed: 2700, PER: 31.5, TurnoverPercent: 12},
+		{ID: 2, League: models.LeagueNBA, PlayerID: "curryst01", Season: 2010, Age: 21, Team: "GSW", MinutesPlayed: 2500, PER: 16, TurnoverPercent: 15},
+	}
+	mem.Careers = []models.PlayerCareerStat{{ID: 1, League: models.LeagueNBA, PlayerID: "curryst01", Points: 4175}}
+	repos := mem.Repositories()
+	app := fiber.New()
+	app.Get("/compare", controllers.ComparePlayers(repos.Totals, repos.Advanced, repos.Careers, repos.PlayerIDs))
+
+	code, body := getJSON[controllers.CompareResponse](t, app, "/compare?players=curryst01,duranke01&stats=points,fieldPercent,per")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, []string{"points", "fieldPercent", "per"}, body.Stats)
+	keys := func(body controllers.CompareResponse) []int {
+		var out []int
+		for _, r := range body.Rows {
+			out = append(out, r.Key)
+		}
+		return out
+	}
+	assert.Equal(t, []int{2008, 2010, 2012, 2016}, keys(body))
+	if assert.Len(t, body.Rows, 4) {
+		both := body.Rows[1].Seasons
+		assert.EqualValues(t, 1400, both[0].Stats["points"])
+		assert.EqualValues(t, 2472, both[1].Stats["points"])
+		assert.Nil(t, body.Rows[0].Seasons[0])
+		assert.Equal(t, 31.5, body.Rows[3].Seasons[0].Stats["per"])
+	}
+
+	if assert.Len(t, body.Players, 2) {
+		curry := body.Players[0]
+		assert.Equal(t, "curryst01 name", curry.PlayerName)
+		assert.Equal(t, 4175, curry.Career.Points)
+		assert.Equal(t, 2016, curry.Peaks["points"].Season)
+		assert.Equal(t, 2016, curry.Peaks["fieldPercent"].Season)
+		assert.Equal(t, 2016, curry.Peaks["per"].Season)
+		assert.Nil(t, body.Players[1].Career)
+		assert.Nil(t, body.Players[1].Peaks["per"])
+	}
+
+	_, body = getJSON[controllers.CompareResponse](t, app, "/compare?players=curryst01,duranke01&align=age&stats=points")
+	assert.Equal(t, []int{19, 21, 23, 27}, keys(body))
+	_, body = getJSON[controllers.CompareResponse](t, app, "/compare?players=curryst01,duranke01&align=experience&stats=points")
+	assert.Equal(t, []int{1, 2, 3}, keys(body))
+	assert.EqualValues(t, 2472, body.Rows[1].Seasons[1].Stats["points"])
+
+	// Playoff experience counts regular seasons, not playoff runs.
+	_, body = getJSON[controllers.CompareResponse](t, app, "/compare?players=curryst01,duranke01&align=experience&stats=points&isPlayoff=true")
+	assert.Equal(t, []int{2, 3}, keys(body))
+	if assert.Len(t, body.Rows, 2) {
+		assert.EqualValues(t, 154, body.Rows[0].Seasons[1].Stats["points"])
+		assert.EqualValues(t, 460, body.Rows[1].Seasons[0].Stats["points"])
+	}
+
+	// A lower turnover rate is better; turnover counts have no peak.
+	_, body = getJSON[controllers.CompareResponse](t, app, "/compare?players=curryst01,duranke01&stats=turnovers,turnoverPercent")
+	if assert.Len(t, body.Players, 2) {
+		assert.Nil(t, body.Players[0].Peaks["turnovers"])
+		if peak := body.Players[0].Peaks["turnoverPercent"]; assert.NotNil(t, peak) {
+			assert.Equal(t, 2016, peak.Season)
+			assert.Equal(t, 12.0, peak.Value)
+		}
+	}
+
+	for route, want := range map[string]int{
+		"/compare?players=curryst01":                      400,
+		"/compare?players=curryst01,duranke01&align=bad":  400,
+		"/compare?players=curryst01,duranke01&stats=nope": 400,
+		"/compare?players=curryst01,nobody01":             404,
+	} {
+		code, _ := getJSON[controllers.CompareResponse](t, app, route)
+		assert.Equal(t, want, code, route)
+	}
+}
+
+func TestWithPercentiles(t *testing.T) {
+	mem := repository.NewMemory()
+	mem.Totals = []models.PlayerTotalStat{
+		{ID: 1, League: models.LeagueNBA, PlayerID: "a", Season: 2024, Points: 2000, Assists: 500},
+		{ID: 2, League: models.LeagueNBA, PlayerID: "b", Season: 2024, Points: 1000},
+	}
+	mem.Advanced = []models.PlayerAdvancedStat{{ID: 1, League: models.LeagueNBA, PlayerID: "a", Season: 2024, PER: 20}}
+	mem.Percentiles = []models.PlayerStatPercentile{
+		{Dataset: services.DatasetTotals, RecordID: 1, Percentiles: map[string]float64{"points": 95, "assists": 80}, PositionPercentiles: map[string]float64{"points": 90}},
+		{Dataset: services.DatasetAdvanced, RecordID: 1, Percentiles: map[string]float64{"per": 70}},
+	}
+	repos := mem.Repositories()
+	app := fiber.New()
+	app.Get("/totals", controllers.GetPlayerTotalStats(repos.Totals, repos.Advanced, repos.Percentiles, repos.PlayerIDs))
+	app.Get("/advanced", controllers.GetAllAdvancedPlayerStats(repos.Advanced, repos.Totals, repos.Percentiles, repos.PlayerIDs))
+
+	code, rows := getRows(t, app, "/totals?withPercentiles=true")
+	assert.Equal(t, 200, code)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, map[string]interface{}{"points": 95.0, "assists": 80.0}, rows[0]["percentiles"])
+		assert.Equal(t, map[string]interface{}{"points": 90.0}, rows[0]["positionPercentiles"])
+		assert.Nil(t, rows[1]["percentiles"]) // not computed yet
+	}
+	// fields= narrows the percentiles too.
+	code, rows = getRows(t, app, "/totals?withPercentiles=true&fields=playerId,points")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, map[string]interface{}{"points": 95.0}, rows[0]["percentiles"])
+
+	code, rows = getRows(t, app, "/advanced?withPercentiles=true")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, map[string]interface{}{"per": 70.0}, rows[0]["percentiles"])
+
+	code, rows = getRows(t, app, "/totals")
+	assert.Equal(t, 200, code)
+	assert.NotContains(t, rows[0], "percentiles")
+}
+
+func TestStatLeaders(t *testing.T) {
+	mem := repository.NewMemory()
+	mem.Totals = []models.PlayerTotalStat{
+		nbaTotal(1, "a", "MIA", 2012, 0, 60, 250, 0.60, 1500), // 66-game season: 242 makes qualify
+		nbaTotal(2, "b", "OKC", 2012, 0, 60, 200, 0.70, 1800),
+		nbaTotal(3, "c", "DEN", 2024, 0, 60, 250, 0.65, 1800), // 300 needed
+		nbaTotal(4, "d", "BOS", 2024, 0, 60, 400, 0.55, 1800),
+		nbaTotal(5, "e", "TOT", 2024, 0, 60, 500, 0.50, 1700),
+		nbaTotal(6, "e", "NYK", 2024, 0, 60, 300, 0.50, 1000),
+	}
+	mem.Totals[4].IsAggregate = true
+	mem.Advanced = []models.PlayerAdvancedStat{
+		{ID: 1, League: models.LeagueNBA, PlayerID: "a", Season: 2024, Team: "MIA", MinutesPlayed: 2000, PER: 25},
+		{ID: 2, League: models.LeagueNBA, PlayerID: "b", Season: 2024, Team: "OKC", MinutesPlayed: 400, PER: 35},
+	}
+	mem.Careers = []models.PlayerCareerStat{
+		{ID: 1, League: models.LeagueNBA, PlayerID: "a", ThreeFG: 300, ThreePercent: 0.40},
+		{ID: 2, League: models.LeagueNBA, PlayerID: "b", ThreeFG: 100, ThreePercent: 0.50},
+	}
+	repos := mem.Repositories()
+
+	app := fiber.New()
+	app.Get("/leaders/:stat", controllers.GetStatLeaders(repos.Totals, repos.Advanced, repos.Careers))
+
+	players := func(body controllers.LeadersResponse) []string {
+		var out []string
+		for _, l := range body.Leaders {
+			out = append(out, l.PlayerID)
+		}
+		return out
+	}
+
+	// Each season is held to its own prorated floor.
+	code, body := getJSON[controllers.LeadersResponse](t, app, "/leaders/fieldPercent")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "totals", body.Dataset)
+	assert.Equal(t, 300, body.Qualification.MinMade)
+	assert.Equal(t, []string{"a", "d", "e"}, players(body))
+
+	code, body = getJSON[controllers.LeadersResponse](t, app, "/leaders/fieldPercent?season=2012")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 242, body.Qualification.MinMade)
+	assert.Equal(t, []string{"a"}, players(body))
+
+	// Overrides replace the floor.
+	_, body = getJSON[controllers.LeadersResponse](t, app, "/leaders/fieldPercent?minMade=0&limit=2")
+	assert.Equal(t, []string{"b", "c"}, players(body))
+
+	// Ties share a rank, and a tie at the cutoff is kept.
+	_, body = getJSON[controllers.LeadersResponse](t, app, "/leaders/points?limit=2")
+	if assert.Len(t, body.Leaders, 3) {
+		for _, l := range body.Leaders {
+			assert.Equal(t, 1, l.Rank)
+			assert.True(t, l.Tied)
+		}
+	}
+	_, body = getJSON[controllers.LeadersResponse](t, app, "/leaders/points?season=2024")
+	assert.ElementsMatch(t, []string{"c", "d", "e"}, players(body)) // the traded player once
+	assert.Equal(t, 3, body.Leaders[2].Rank)
+
+	_, body = getJSON[controllers.LeadersResponse](t, app, "/leaders/per")
+	assert.Equal(t, "advanced", body.Dataset)
+	assert.Equal(t, []string{"a"}, players(body))
+
+	_, body = getJSON[controllers.LeadersResponse](t, app, "/leaders/threePercent?career=true")
+	assert.Equal(t, "career", body.Dataset)
+	assert.Equal(t, []string{"a"}, players(body))
+
+	for _, route := range []string{
+		"/leaders/bogus",
+		"/leaders/playerName",
+		"/leaders/per?career=true",
+		"/leaders/points?dataset=advanced",
+		"/leaders/points?minMade=5",
+		"/leaders/points?limit=0",
+	} {
+		code, _ := getJSON[controllers.LeadersResponse](t, app, route)
+		assert.Equal(t, 400, code, route)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// DB_DRIVER=sqlite: migrations, upserts and API key auth on a file database
+// -----------------------------------------------------------------------------
+type fakeTotalsProvider struct{ services.BRProvider }
+
+func (fakeTotalsProvider) FetchPlayerTotals(league string, season int, isPlayoff bool) ([]models.PlayerTotalStat, error) {
+	return []models.PlayerTotalStat{
+		{League: league, PlayerID: "curryst01", Team: "GSW", Season: season, IsPlayoff: isPlayoff, Games: 74, Points: 1956},
+	}, nil
+}
+
+func TestSQLiteDriver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", "")
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "data", "nba_go.db"))
+	db := config.InitDB(true)
+
+	// The upsert runs twice against the same unique key.
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, services.ImportPlayerTotals(db, fakeTotalsProvider{}, models.LeagueNBA, 2024, false))
+	}
+	var count int64
+	db.Model(&models.PlayerTotalStat{}).Count(&count)
+	assert.EqualValues(t, 1, count)
+
+	app := fiber.New()
+	app.Use(middleware.APIKeyAuth(repository.NewGorm(db).APIKeys))
+	routes.RegisterPlayerTotalRoutes(app, db, repository.NewGorm(db))
+	db.Create(&models.APIKey{Hash: security.HashKey("live")})
+	db.Create(&models.APIKey{Hash: security.HashKey("dead"), Revoked: true})
+
+	for key, want := range map[string]int{"live": 200, "dead": 401, "nope": 401} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/playertotals/", nil)
+		req.Header.Set("X-API-Key", key)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err, key)
+		assert.Equal(t, want, resp.StatusCode, key)
+	}
+}
+
+func TestSimilarPlayers(t *testing.T) {
+	mem := repository.NewMemory()
+	season := func(id uint, player string, season, age, minutes int, usage, box float64) models.PlayerAdvancedStat {
+		s := nbaAdvanced(id, player, "DEN", season, age, 70, minutes)
+		s.UsagePercent, s.Box, s.TSPercent = usage, box, 0.6
+		return s
+	}
+	mem.Advanced = []models.PlayerAdvancedStat{
+		season(1, "jokicni01", 2023, 27, 2300, 27, 13),
+		season(2, "jokicni01", 2024, 28, 2700, 30, 13),
+		season(3, "sabondo01", 2024, 27, 2800, 20, 6),
+		season(4, "embiijo01", 2023, 28, 2300, 37, 6),
+		season(5, "olajuha01", 1994, 31, 3200, 30, 8),
+		season(6, "benchwa01", 2024, 24, 300, 30, 13), // too few minutes to compare
+		season(7, "gervige01", 1976, 23, 2600, 0, 0),  // before USG%, 3PAr and BPM were tracked
+		season(8, "1628983", 2024, 25, 2600, 30, 0),   // NBA.com: no BPM, BLK%, STL%, 3PAr or FTr
+	}
+	repos := mem.Repositories()
+	app := fiber.New()
+	app.Get("/players/:id/similar", controllers.GetSimilarPlayers(repos.Advanced, repos.PlayerIDs))
+
+	players := func(body controllers.SimilarPlayersResponse) []string {
+		var out []string
+		for _, s := range body.Similar {
+			out = append(out, s.PlayerID)
+		}
+		return out
+	}
+
+	code, body := getJSON[controllers.SimilarPlayersResponse](t, app, "/players/jokicni01/similar")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 2024, body.Season) // the latest season by default
+	assert.Equal(t, 30.0, body.Target["usagePercent"])
+	assert.Equal(t, 3, body.Pool)
+	assert.Equal(t, []string{"olajuha01", "embiijo01", "sabondo01"}, players(body))
+	if assert.NotEmpty(t, body.Similar) {
+		top := body.Similar[0]
+		assert.Equal(t, 1994, top.Season)
+		assert.Equal(t, 1.0, top.Contributions["box"].Share)
+		assert.Equal(t, -5.0, top.Contributions["box"].Difference)
+	}
+
+	_, body = getJSON[controllers.SimilarPlayersResponse](t, app, "/players/jokicni01/similar?k=1&weights=box:0")
+	assert.Equal(t, []string{"olajuha01"}, players(body))
+	assert.Zero(t, body.Similar[0].Distance)
+
+	// Dropping the untracked features opens up earlier seasons.
+	code, body = getJSON[controllers.SimilarPlayersResponse](t, app, "/players/gervige01/similar?weights=usagePercent:0,threePAR:0,box:0,blockPercent:0,stealPercent:0")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 5, body.Pool)
+	_, body = getJSON[controllers.SimilarPlayersResponse](t, app, "/players/jokicni01/similar?k=10&weights=usagePercent:0,threePAR:0,box:0,blockPercent:0,stealPercent:0")
+	assert.Contains(t, players(body), "gervige01")
+	assert.NotContains(t, players(body), "1628983") // FTr still weighs in
+
+	// Weighting only what NBA.com reports brings its rows in.
+	nbaStatsOnly := "?weights=blockPercent:0,stealPercent:0,threePAR:0,ftr:0,box:0"
+	code, body = getJSON[controllers.SimilarPlayersResponse](t, app, "/players/1628983/similar"+nbaStatsOnly)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 5, body.Pool)
+	_, body = getJSON[controllers.SimilarPlayersResponse](t, app, "/players/jokicni01/similar"+nbaStatsOnly)
+	assert.Contains(t, players(body), "1628983")
+
+	_, body = getJSON[controllers.SimilarPlayersResponse](t, app, "/players/jokicni01/similar?season=2023&ageWindow=0")
+	assert.Equal(t, 27, body.Age)
+	assert.Equal(t, []string{"sabondo01"}, players(body))
+
+	for route, want := range map[string]int{
+		"/players/jokicni01/similar?k=0":            400,
+		"/players/jokicni01/similar?k=101":          400,
+		"/players/jokicni01/similar?weights=per:2":  400,
+		"/players/jokicni01/similar?weights=box:-1": 400,
+		"/players/jokicni01/similar?ageWindow=-2":   400,
+		"/players/jokicni01/similar?season=1990":    404,
+		"/players/gervige01/similar":                400,
+		"/players/1628983/similar":                  400,
+		"/players/nobody01/similar":                 404,
+	} {
+		code, _ := getJSON[controllers.SimilarPlayersResponse](t, app, route)
+		assert.Equal(t, want, code, route)
+	}
+}
+
+func TestImportSeasons(t *testing.T) {
+	for _, tc := range []struct {
+		opts        importOptions
+		first, last int
+	}{
+		{importOptions{league: models.LeagueNBA}, 1991, 2002},
+		{importOptions{league: models.LeagueABA}, 1968, 1976},
+		{importOptions{league: models.LeagueWNBA}, 1997, 2025},
+		{importOptions{league: models.LeagueWNBA, season: 2024}, 2024, 2024},
+	} {
+		first, last := importSeasons(tc.opts)
+		assert.Equal(t, tc.first, first, tc.opts)
+		assert.Equal(t, tc.last, last, tc.opts)
+	}
+}
+
+func TestAdminGuard(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.DataQualityViolation{}))
+	violations := make([]models.DataQualityViolation, 3)
+	for i := range violations {
+		violations[i] = models.DataQualityViolation{League: models.LeagueNBA, Season: 2024, Dataset: "totals", Rule: "fg_sum", PlayerID: fmt.Sprintf("p%d", i)}
+	}
+	require.NoError(t, db.Create(&violations).Error)
+
+	request := func(app *fiber.App, route, secret string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, route, nil)
+		if secret != "" {
+			req.Header.Set("X-Admin-Secret", secret)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// No secret configured: the admin routes are closed.
+	t.Setenv("ADMIN_SECRET", "")
+	closed := fiber.New()
+	controllers.RegisterDataQualityRoutes(closed, db)
+	assert.Equal(t, 401, request(closed, "/admin/dataquality?season=2024", "").StatusCode)
+
+	t.Setenv("ADMIN_SECRET", "s3cret")
+	app := fiber.New()
+	controllers.RegisterDataQualityRoutes(app, db)
+	assert.Equal(t, 401, request(app, "/admin/dataquality?season=2024", "").StatusCode)
+	assert.Equal(t, 401, request(app, "/admin/dataquality?season=2024", "wrong").StatusCode)
+
+	// limit is clamped to 1..1000.
+	for route, want := range map[string]int{
+		"/admin/dataquality?season=2024":          3,
+		"/admin/dataquality?season=2024&limit=-1": 1,
+		"/admin/dataquality?season=2024&limit=2":  2,
+	} {
+		resp := request(app, route, "s3cret")
+		assert.Equal(t, 200, resp.StatusCode, route)
+		var report services.DataQualityReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.Len(t, report.Violations, want, route)
+	}
+}
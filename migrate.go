@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/nprasad2077/NBA_Go/migrations"
+	"gorm.io/gorm"
+)
+
+// runMigrate handles "migrate up", "migrate down [n]" (default 1) and
+// "migrate status".
+func runMigrate(db *gorm.DB, args []string) {
+	if len(args) == 0 {
+		log.Fatalf("usage: migrate up | down [n] | status")
+	}
+	switch args[0] {
+	case "up":
+		n, err := migrations.Up(db)
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		log.Printf("✅ Applied %d migrations; schema at version %d", n, migrations.Latest())
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatalf("invalid step count %q", args[1])
+			}
+		}
+		n, err := migrations.Down(db, steps)
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		log.Printf("✅ Rolled back %d migrations", n)
+	case "status":
+		status, err := migrations.Status(db)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range status {
+			applied := "pending"
+			if s.Applied {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			log.Printf("%04d %-32s %s", s.Version, s.Name, applied)
+		}
+	default:
+		log.Fatalf("unknown migrate command %q (want up, down or status)", args[0])
+	}
+}
+
+// checkSchemaVersion warns when the database is behind this binary, or
+// refuses to start when DB_REQUIRE_MIGRATED=true.
+func checkSchemaVersion(db *gorm.DB) {
+	pending, err := migrations.Pending(db)
+	if err != nil {
+		log.Fatalf("read schema version: %v", err)
+	}
+	if pending == 0 {
+		return
+	}
+	if os.Getenv("DB_REQUIRE_MIGRATED") == "true" {
+		log.Fatalf("database is %d migrations behind; run `migrate up` first", pending)
+	}
+	log.Printf("⚠️  database is %d migrations behind; run `migrate up`", pending)
+}
@@ -0,0 +1,214 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// baseline is the schema as it stood when AutoMigrate was retired. It uses
+// frozen copies of the models so later model edits cannot change it. On a
+// database created by the old AutoMigrate path it only adds what is missing.
+var baseline = Migration{
+	Version: 1,
+	Name:    "baseline",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(baselineTables...)
+	},
+	Down: func(tx *gorm.DB) error {
+		for i := len(baselineTables) - 1; i >= 0; i-- {
+			if err := tx.Migrator().DropTable(baselineTables[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var baselineTables = []interface{}{
+	&v1PlayerAdvancedStat{},
+	&v1PlayerTotalStat{},
+	&v1PlayerShotChart{},
+	&v1APIKey{},
+	&v1ShotChartImportProgress{},
+	&v1DataQualityViolation{},
+	&v1PlayerIDMapping{},
+}
+
+type v1PlayerAdvancedStat struct {
+	ID                 uint `gorm:"primaryKey"`
+	ExternalID         int
+	League             string `gorm:"not null;default:NBA;index:idx_advanced_league_player_season_team,unique"`
+	PlayerID           string `gorm:"not null;index:idx_advanced_league_player_season_team,unique"`
+	PlayerName         string
+	Position           string
+	Age                int
+	Games              int
+	MinutesPlayed      int
+	PER                float64
+	TSPercent          float64
+	ThreePAR           float64
+	FTR                float64
+	OffensiveRBPercent float64
+	DefensiveRBPercent float64
+	TotalRBPercent     float64
+	AssistPercent      float64
+	StealPercent       float64
+	BlockPercent       float64
+	TurnoverPercent    float64
+	UsagePercent       float64
+	OffensiveWS        float64
+	DefensiveWS        float64
+	WinShares          float64
+	WinSharesPer       float64
+	OffensiveBox       float64
+	DefensiveBox       float64
+	Box                float64
+	VORP               float64
+	Team               string `gorm:"not null;index:idx_advanced_league_player_season_team,unique"`
+	Season             int    `gorm:"not null;index:idx_advanced_league_player_season_team,unique"`
+	IsPlayoff          bool   `gorm:"not null;default:false;index:idx_advanced_league_player_season_team,unique"`
+	IsAggregate        bool   `gorm:"not null;default:false;index"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          gorm.DeletedAt `gorm:"index"`
+}
+
+func (v1PlayerAdvancedStat) TableName() string { return "player_advanced_stats" }
+
+type v1PlayerTotalStat struct {
+	ID              uint `gorm:"primaryKey"`
+	ExternalID      int
+	League          string `gorm:"not null;default:NBA;uniqueIndex:idx_total_league_player_season_team"`
+	PlayerID        string `gorm:"not null;uniqueIndex:idx_total_league_player_season_team"`
+	PlayerName      string
+	Position        string
+	Age             int
+	Games           int
+	GamesStarted    int
+	MinutesPG       float64
+	FieldGoals      int
+	FieldAttempts   int
+	FieldPercent    float64
+	ThreeFG         int
+	ThreeAttempts   int
+	ThreePercent    float64
+	TwoFG           int
+	TwoAttempts     int
+	TwoPercent      float64
+	EffectFGPercent float64
+	FT              int
+	FTAttempts      int
+	FTPercent       float64
+	OffensiveRB     int
+	DefensiveRB     int
+	TotalRB         int
+	Assists         int
+	Steals          int
+	Blocks          int
+	Turnovers       int
+	PersonalFouls   int
+	Points          int
+	Team            string `gorm:"not null;uniqueIndex:idx_total_league_player_season_team"`
+	Season          int    `gorm:"not null;uniqueIndex:idx_total_league_player_season_team"`
+	IsPlayoff       bool   `gorm:"not null;default:false;uniqueIndex:idx_total_league_player_season_team"`
+	IsAggregate     bool   `gorm:"not null;default:false;index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+}
+
+func (v1PlayerTotalStat) TableName() string { return "player_total_stats" }
+
+type v1PlayerShotChart struct {
+	ID                uint   `gorm:"primaryKey"`
+	League            string `gorm:"not null;default:NBA;uniqueIndex:idx_shot_league_identity,priority:0"`
+	PlayerID          string `gorm:"not null;uniqueIndex:idx_shot_league_identity,priority:1"`
+	Season            int    `gorm:"not null;uniqueIndex:idx_shot_league_identity,priority:2"`
+	Date              string `gorm:"not null;uniqueIndex:idx_shot_league_identity,priority:3"`
+	Quarter           string `gorm:"column:qtr;uniqueIndex:idx_shot_league_identity,priority:4"`
+	TimeRemaining     string `gorm:"column:time_remaining;uniqueIndex:idx_shot_league_identity,priority:5"`
+	Top               int    `gorm:"uniqueIndex:idx_shot_league_identity,priority:6"`
+	Left              int    `gorm:"uniqueIndex:idx_shot_league_identity,priority:7"`
+	PlayerName        string
+	Result            bool
+	ShotType          string `gorm:"column:shot_type"`
+	DistanceFt        int    `gorm:"column:distance_ft"`
+	Lead              bool
+	TeamScore         int `gorm:"column:team_score"`
+	OpponentTeamScore int `gorm:"column:opponent_team_score"`
+	Opponent          string
+	Team              string     `gorm:"not null"`
+	GameID            string     `gorm:"column:game_id;index"`
+	GameDate          *time.Time `gorm:"type:date;index"`
+	Period            int        `gorm:"index"`
+	IsOvertime        bool       `gorm:"not null;default:false"`
+	SecondsRemaining  int
+	ElapsedSeconds    int `gorm:"index"`
+	IsHome            *bool
+	CourtX            float64 `gorm:"column:court_x;index"`
+	CourtY            float64 `gorm:"column:court_y;index"`
+	ShotAngle         float64 `gorm:"column:shot_angle"`
+	Zone              string  `gorm:"index"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+}
+
+func (v1PlayerShotChart) TableName() string { return "player_shot_charts" }
+
+type v1APIKey struct {
+	ID        uint   `gorm:"primaryKey"`
+	Hash      []byte `gorm:"uniqueIndex"`
+	Label     string
+	Revoked   bool
+	CreatedAt time.Time
+	RevokedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (v1APIKey) TableName() string { return "api_keys" }
+
+type v1ShotChartImportProgress struct {
+	ID        uint   `gorm:"primaryKey"`
+	Season    int    `gorm:"not null;uniqueIndex:idx_shot_import_season_player"`
+	PlayerID  string `gorm:"not null;uniqueIndex:idx_shot_import_season_player"`
+	Status    string `gorm:"not null;index"`
+	Error     string
+	Attempts  int `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (v1ShotChartImportProgress) TableName() string { return "shot_chart_import_progresses" }
+
+type v1DataQualityViolation struct {
+	ID        uint   `gorm:"primaryKey"`
+	Dataset   string `gorm:"not null;index:idx_dq_league_scope"`
+	League    string `gorm:"not null;default:NBA;index:idx_dq_league_scope"`
+	Season    int    `gorm:"not null;index:idx_dq_league_scope"`
+	IsPlayoff bool   `gorm:"not null;default:false;index:idx_dq_league_scope"`
+	Rule      string `gorm:"not null;index"`
+	RecordID  uint
+	PlayerID  string `gorm:"index"`
+	Team      string
+	Message   string
+	CreatedAt time.Time
+}
+
+func (v1DataQualityViolation) TableName() string { return "data_quality_violations" }
+
+type v1PlayerIDMapping struct {
+	ID         uint   `gorm:"primaryKey"`
+	BRID       string `gorm:"column:br_id;not null;uniqueIndex:idx_player_id_mapping"`
+	NBAID      string `gorm:"column:nba_id;not null;uniqueIndex:idx_player_id_mapping;index"`
+	PlayerName string
+	BirthDate  *time.Time `gorm:"type:date"`
+	Source     string     `gorm:"not null"`
+	Confidence float64    `gorm:"not null;default:0"`
+	Status     string     `gorm:"not null;index"`
+	Reason     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (v1PlayerIDMapping) TableName() string { return "player_id_mappings" }
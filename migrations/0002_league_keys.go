@@ -0,0 +1,44 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// legacyIndexes are the pre-league unique keys. AutoMigrate created the
+// league-aware replacements under new names but never dropped these, so
+// a WNBA row could still collide with an NBA one.
+var legacyIndexes = []struct {
+	table, name, create string
+}{
+	{"player_total_stats", "idx_total_player_season_team",
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_total_player_season_team ON player_total_stats (player_id, team, season, is_playoff)`},
+	{"player_advanced_stats", "idx_player_season_team",
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_player_season_team ON player_advanced_stats (player_id, team, season, is_playoff)`},
+	{"player_shot_charts", "idx_shot_identity",
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_shot_identity ON player_shot_charts (player_id, season, date, qtr, time_remaining, top, "left")`},
+	{"data_quality_violations", "idx_dq_scope",
+		`CREATE INDEX IF NOT EXISTS idx_dq_scope ON data_quality_violations (dataset, season, is_playoff)`},
+}
+
+// leagueKeys drops the NBA-only unique keys. Rolling it back fails if
+// another league's rows now share a key with NBA rows.
+var leagueKeys = Migration{
+	Version: 2,
+	Name:    "drop_pre_league_unique_keys",
+	Up: func(tx *gorm.DB) error {
+		for _, idx := range legacyIndexes {
+			if tx.Migrator().HasIndex(idx.table, idx.name) {
+				if err := tx.Migrator().DropIndex(idx.table, idx.name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, idx := range legacyIndexes {
+			if err := tx.Exec(idx.create).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,210 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// backfillDerivedColumns fills the columns earlier releases added to rows
+// that were already stored: aggregate-row flags, typed shot context and
+// court zones. import-data used to redo this on every run.
+var backfillDerivedColumns = Migration{
+	Version: 3,
+	Name:    "backfill_derived_columns",
+	Up: func(tx *gorm.DB) error {
+		if err := v3NormalizeAggregateRows(tx); err != nil {
+			return err
+		}
+		if err := v3BackfillShotContext(tx); err != nil {
+			return err
+		}
+		return v3BackfillShotZones(tx)
+	},
+	// Derived values only; the backfilled columns stay valid when rolled back.
+	Down: func(tx *gorm.DB) error { return nil },
+}
+
+// The rest of this file freezes the services code as it stood at version 3,
+// so later changes to parsing or zone rules cannot change what this
+// migration writes.
+
+var v3LegacyAggregateTeams = []string{"TOT", "2TM", "3TM", "4TM", "5TM"}
+
+func v3NormalizeAggregateRows(tx *gorm.DB) error {
+	for _, model := range []interface{}{&v1PlayerTotalStat{}, &v1PlayerAdvancedStat{}} {
+		res := tx.Model(model).
+			Where("team IN ?", v3LegacyAggregateTeams).
+			Updates(map[string]interface{}{"team": "TOT", "is_aggregate": true})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			log.Printf("Normalized %d aggregate rows in %T", res.RowsAffected, model)
+		}
+	}
+	return nil
+}
+
+type v3ShotContext struct {
+	GameDate         *time.Time
+	Period           int
+	IsOvertime       bool
+	SecondsRemaining int
+	ElapsedSeconds   int
+}
+
+func v3ParseShotContext(date, quarter, timeRemaining string) (v3ShotContext, error) {
+	var ctx v3ShotContext
+	var firstErr error
+	for _, layout := range []string{"Jan 2,2006", "Jan 2, 2006"} {
+		if d, err := time.Parse(layout, strings.TrimSpace(date)); err == nil {
+			ctx.GameDate = &d
+			break
+		}
+	}
+	if ctx.GameDate == nil {
+		firstErr = fmt.Errorf("unrecognized shot date %q", date)
+	}
+
+	badPeriod := fmt.Errorf("unrecognized period %q", quarter)
+	f := strings.Fields(quarter)
+	if len(f) != 2 {
+		return ctx, firstError(firstErr, badPeriod)
+	}
+	n, err := strconv.Atoi(strings.TrimRight(f[0], "stndrh"))
+	if err != nil || n < 1 {
+		return ctx, firstError(firstErr, badPeriod)
+	}
+	switch strings.ToUpper(f[1]) {
+	case "QTR":
+		if n > 4 {
+			return ctx, firstError(firstErr, badPeriod)
+		}
+		ctx.Period = n
+	case "OT":
+		ctx.Period, ctx.IsOvertime = 4+n, true
+	default:
+		return ctx, firstError(firstErr, badPeriod)
+	}
+
+	badClock := fmt.Errorf("unrecognized game clock %q", timeRemaining)
+	m, s, ok := strings.Cut(strings.TrimSpace(timeRemaining), ":")
+	mins, err := strconv.Atoi(m)
+	if !ok || err != nil || mins < 0 {
+		return ctx, firstError(firstErr, badClock)
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil || secs < 0 || secs >= 60 {
+		return ctx, firstError(firstErr, badClock)
+	}
+	ctx.SecondsRemaining = mins*60 + int(secs)
+
+	before, length := (ctx.Period-1)*720, 720
+	if ctx.IsOvertime {
+		before, length = 4*720+(ctx.Period-5)*300, 300
+	}
+	ctx.ElapsedSeconds = before + length - ctx.SecondsRemaining
+	return ctx, firstErr
+}
+
+// firstError keeps the first error seen.
+func firstError(first, next error) error {
+	if first != nil {
+		return first
+	}
+	return next
+}
+
+func v3BackfillShotContext(tx *gorm.DB) error {
+	var updated, failed int
+	var rows []v1PlayerShotChart
+	res := tx.Where("period = 0 OR period IS NULL").
+		FindInBatches(&rows, 1000, func(_ *gorm.DB, _ int) error {
+			for _, r := range rows {
+				ctx, err := v3ParseShotContext(r.Date, r.Quarter, r.TimeRemaining)
+				if err != nil {
+					failed++
+				}
+				if ctx.Period == 0 && ctx.GameDate == nil {
+					continue
+				}
+				if err := tx.Model(&v1PlayerShotChart{}).Where("id = ?", r.ID).
+					Updates(map[string]interface{}{
+						"game_date":         ctx.GameDate,
+						"period":            ctx.Period,
+						"is_overtime":       ctx.IsOvertime,
+						"seconds_remaining": ctx.SecondsRemaining,
+						"elapsed_seconds":   ctx.ElapsedSeconds,
+					}).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	log.Printf("Backfilled shot context on %d shots (%d with unparseable fields)", updated, failed)
+	return nil
+}
+
+// v3CourtGeometry converts BR's 10 px per foot offsets into feet from the
+// basket and classifies the zone.
+func v3CourtGeometry(top, left int, shotType string) (x, y, angle float64, zone string) {
+	round1 := func(f float64) float64 { return math.Round(f*10) / 10 }
+	x = round1((float64(left) - 250.0) / 10)
+	y = round1((float64(top) - 52.5) / 10)
+	angle = round1(math.Atan2(x, y) * 180 / math.Pi)
+
+	dist := math.Hypot(x, y)
+	three := shotType == "3-pointer"
+	if shotType != "3-pointer" && shotType != "2-pointer" {
+		three = dist >= 23.75 || (math.Abs(x) >= 22.0 && y <= 14.0-5.25)
+	}
+	switch {
+	case y > 47.0-5.25:
+		zone = "backcourt"
+	case three && y <= 14.0-5.25 && x < 0:
+		zone = "left_corner_3"
+	case three && y <= 14.0-5.25:
+		zone = "right_corner_3"
+	case three:
+		zone = "above_break_3"
+	case dist <= 4.0:
+		zone = "restricted_area"
+	case math.Abs(x) <= 8.0 && y <= 19.0-5.25:
+		zone = "paint_non_ra"
+	default:
+		zone = "mid_range"
+	}
+	return x, y, angle, zone
+}
+
+func v3BackfillShotZones(tx *gorm.DB) error {
+	var updated int
+	var rows []v1PlayerShotChart
+	res := tx.Where("zone = '' OR zone IS NULL").
+		FindInBatches(&rows, 1000, func(_ *gorm.DB, _ int) error {
+			for _, r := range rows {
+				x, y, angle, zone := v3CourtGeometry(r.Top, r.Left, r.ShotType)
+				if err := tx.Model(&v1PlayerShotChart{}).Where("id = ?", r.ID).
+					Updates(map[string]interface{}{"court_x": x, "court_y": y, "shot_angle": angle, "zone": zone}).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	log.Printf("Backfilled court coordinates and zones on %d shots", updated)
+	return nil
+}
@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// playerCareerStats adds the derived career table and fills it from the
+// season totals already stored.
+var playerCareerStats = Migration{
+	Version: 4,
+	Name:    "player_career_stats",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AutoMigrate(&v4PlayerCareerStat{}); err != nil {
+			return err
+		}
+		return v4RebuildCareerStats(tx)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v4PlayerCareerStat{})
+	},
+}
+
+type v4PlayerCareerStat struct {
+	ID              uint   `gorm:"primaryKey"`
+	League          string `gorm:"not null;default:NBA;uniqueIndex:idx_career_league_player"`
+	PlayerID        string `gorm:"not null;uniqueIndex:idx_career_league_player"`
+	IsPlayoff       bool   `gorm:"not null;default:false;uniqueIndex:idx_career_league_player"`
+	PlayerName      string
+	Seasons         int
+	FirstSeason     int
+	LastSeason      int
+	Teams           string
+	Games           int
+	GamesStarted    int
+	Minutes         float64
+	FieldGoals      int
+	FieldAttempts   int
+	FieldPercent    float64
+	ThreeFG         int
+	ThreeAttempts   int
+	ThreePercent    float64
+	TwoFG           int
+	TwoAttempts     int
+	TwoPercent      float64
+	EffectFGPercent float64
+	FT              int
+	FTAttempts      int
+	FTPercent       float64
+	OffensiveRB     int
+	DefensiveRB     int
+	TotalRB         int
+	Assists         int
+	Steals          int
+	Blocks          int
+	Turnovers       int
+	PersonalFouls   int
+	Points          int
+	UpdatedAt       time.Time
+}
+
+func (v4PlayerCareerStat) TableName() string { return "player_career_stats" }
+
+// v4RebuildCareerStats freezes the career rebuild as it stood at version 4:
+// every league's season totals summed per player and season type, a traded
+// season counted once through its aggregate row.
+func v4RebuildCareerStats(tx *gorm.DB) error {
+	var rows []v1PlayerTotalStat
+	if err := tx.Order("league, season, id").Find(&rows).Error; err != nil {
+		return err
+	}
+	type careerKey struct {
+		league, playerID string
+		isPlayoff        bool
+	}
+	type seasonKey struct {
+		careerKey
+		season int
+	}
+	hasAggregate := map[seasonKey]bool{}
+	for _, r := range rows {
+		if r.IsAggregate {
+			hasAggregate[seasonKey{careerKey{r.League, r.PlayerID, r.IsPlayoff}, r.Season}] = true
+		}
+	}
+
+	var order []careerKey
+	careers := map[careerKey]*v4PlayerCareerStat{}
+	teams := map[careerKey][]string{}
+	for _, r := range rows {
+		key := careerKey{r.League, r.PlayerID, r.IsPlayoff}
+		c, ok := careers[key]
+		if !ok {
+			c = &v4PlayerCareerStat{League: r.League, PlayerID: r.PlayerID, IsPlayoff: r.IsPlayoff, FirstSeason: r.Season}
+			careers[key] = c
+			order = append(order, key)
+		}
+		if !r.IsAggregate && !slices.Contains(teams[key], r.Team) {
+			teams[key] = append(teams[key], r.Team)
+		}
+		if !r.IsAggregate && hasAggregate[seasonKey{key, r.Season}] {
+			continue
+		}
+		if r.Season != c.LastSeason {
+			c.Seasons++
+			c.LastSeason = r.Season
+		}
+		if r.PlayerName != "" {
+			c.PlayerName = r.PlayerName
+		}
+		c.Games += r.Games
+		c.GamesStarted += r.GamesStarted
+		c.Minutes += r.MinutesPG // season total minutes on the totals tables
+		c.FieldGoals += r.FieldGoals
+		c.FieldAttempts += r.FieldAttempts
+		c.ThreeFG += r.ThreeFG
+		c.ThreeAttempts += r.ThreeAttempts
+		c.TwoFG += r.TwoFG
+		c.TwoAttempts += r.TwoAttempts
+		c.FT += r.FT
+		c.FTAttempts += r.FTAttempts
+		c.OffensiveRB += r.OffensiveRB
+		c.DefensiveRB += r.DefensiveRB
+		c.TotalRB += r.TotalRB
+		c.Assists += r.Assists
+		c.Steals += r.Steals
+		c.Blocks += r.Blocks
+		c.Turnovers += r.Turnovers
+		c.PersonalFouls += r.PersonalFouls
+		c.Points += r.Points
+	}
+
+	ratio := func(made, att int) float64 {
+		if att == 0 {
+			return 0
+		}
+		return math.Round(float64(made)/float64(att)*1000) / 1000
+	}
+	out := make([]v4PlayerCareerStat, 0, len(order))
+	for _, key := range order {
+		c := careers[key]
+		c.Teams = strings.Join(teams[key], ",")
+		c.FieldPercent = ratio(c.FieldGoals, c.FieldAttempts)
+		c.ThreePercent = ratio(c.ThreeFG, c.ThreeAttempts)
+		c.TwoPercent = ratio(c.TwoFG, c.TwoAttempts)
+		c.FTPercent = ratio(c.FT, c.FTAttempts)
+		if c.FieldAttempts > 0 {
+			c.EffectFGPercent = math.Round((float64(c.FieldGoals)+0.5*float64(c.ThreeFG))/float64(c.FieldAttempts)*1000) / 1000
+		}
+		out = append(out, *c)
+	}
+
+	if err := tx.Where("1 = 1").Delete(&v4PlayerCareerStat{}).Error; err != nil {
+		return err
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	if err := tx.CreateInBatches(out, 500).Error; err != nil {
+		return err
+	}
+	log.Printf("Rebuilt %d career rows", len(out))
+	return nil
+}
@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+// careerSearchName adds the folded player name used by player search and
+// fills it on the career rows 0004 built.
+var careerSearchName = Migration{
+	Version: 5,
+	Name:    "career_search_name",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if !m.HasColumn(&v5PlayerCareerStat{}, "SearchName") {
+			if err := m.AddColumn(&v5PlayerCareerStat{}, "SearchName"); err != nil {
+				return err
+			}
+		}
+		if !m.HasIndex(&v5PlayerCareerStat{}, "SearchName") {
+			if err := m.CreateIndex(&v5PlayerCareerStat{}, "SearchName"); err != nil {
+				return err
+			}
+		}
+		var rows []v5PlayerCareerStat
+		if err := tx.Select("id", "player_name").Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if err := tx.Model(&v5PlayerCareerStat{}).Where("id = ?", r.ID).
+				Update("search_name", v5FoldName(r.PlayerName)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&v5PlayerCareerStat{}, "SearchName"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&v5PlayerCareerStat{}, "SearchName")
+	},
+}
+
+type v5PlayerCareerStat struct {
+	ID         uint `gorm:"primaryKey"`
+	PlayerName string
+	SearchName string `gorm:"index"`
+}
+
+func (v5PlayerCareerStat) TableName() string { return "player_career_stats" }
+
+var (
+	v5NameSuffixes = map[string]bool{"jr": true, "sr": true, "ii": true, "iii": true, "iv": true}
+	v5FoldExtra    = strings.NewReplacer("ø", "o", "đ", "d", "ł", "l", "ß", "ss", "æ", "ae", "ı", "i", ".", "", "'", "", "’", "")
+)
+
+// v5FoldName freezes the name folding search used at version 5: accents
+// stripped, lower-cased, punctuation and generational suffixes removed.
+func v5FoldName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(name))
+	if err != nil {
+		folded = strings.ToLower(name)
+	}
+	folded = v5FoldExtra.Replace(folded)
+
+	var words []string
+	for _, w := range strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !v5NameSuffixes[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
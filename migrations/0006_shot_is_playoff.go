@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// shotIsPlayoff records whether each shot was taken in the playoffs and
+// fills it on stored shots from their game ID or date.
+var shotIsPlayoff = Migration{
+	Version: 6,
+	Name:    "shot_is_playoff",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if !m.HasColumn(&v6PlayerShotChart{}, "IsPlayoff") {
+			if err := m.AddColumn(&v6PlayerShotChart{}, "IsPlayoff"); err != nil {
+				return err
+			}
+		}
+		if !m.HasIndex(&v6PlayerShotChart{}, "IsPlayoff") {
+			if err := m.CreateIndex(&v6PlayerShotChart{}, "IsPlayoff"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&v6PlayerShotChart{}, "IsPlayoff"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&v6PlayerShotChart{}, "IsPlayoff")
+	},
+	Backfill: v6BackfillShotPlayoffFlags,
+}
+
+type v6PlayerShotChart struct {
+	IsPlayoff bool `gorm:"not null;default:false;index"`
+}
+
+func (v6PlayerShotChart) TableName() string { return "player_shot_charts" }
+
+// v6PlayoffStartDates freezes the first day of each season's playoffs as
+// the version 6 backfill knew them.
+var v6PlayoffStartDates = map[int]string{
+	2001: "2001-04-21", 2002: "2002-04-20", 2003: "2003-04-19", 2004: "2004-04-17",
+	2005: "2005-04-23", 2006: "2006-04-22", 2007: "2007-04-21", 2008: "2008-04-19",
+	2009: "2009-04-18", 2010: "2010-04-17", 2011: "2011-04-16", 2012: "2012-04-28",
+	2013: "2013-04-20", 2014: "2014-04-19", 2015: "2015-04-18", 2016: "2016-04-16",
+	2017: "2017-04-15", 2018: "2018-04-14", 2019: "2019-04-13", 2020: "2020-08-17",
+	2021: "2021-05-22", 2022: "2022-04-16", 2023: "2023-04-15", 2024: "2024-04-20",
+	2025: "2025-04-19",
+}
+
+// v6BackfillShotPlayoffFlags flags stored playoff shots: NBA.com shots by
+// their "004" game ID prefix, BR shots by date.
+func v6BackfillShotPlayoffFlags(tx *gorm.DB) error {
+	var updated int
+	var rows []v1PlayerShotChart
+	res := tx.Select("id", "season", "game_date", "game_id").
+		Where("is_playoff = ?", false).
+		FindInBatches(&rows, 1000, func(_ *gorm.DB, _ int) error {
+			var ids []uint
+			for _, r := range rows {
+				playoff := strings.HasPrefix(r.GameID, "004")
+				if start, ok := v6PlayoffStartDates[r.Season]; r.GameID == "" && ok && r.GameDate != nil {
+					playoff = r.GameDate.Format(time.DateOnly) >= start
+				}
+				if playoff {
+					ids = append(ids, r.ID)
+				}
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			updated += len(ids)
+			return tx.Table("player_shot_charts").Where("id IN ?", ids).Update("is_playoff", true).Error
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	log.Printf("Backfilled the playoff flag on %d shots", updated)
+	return nil
+}
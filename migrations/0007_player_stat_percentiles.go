@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/nprasad2077/NBA_Go/services"
+	"gorm.io/gorm"
+)
+
+// playerStatPercentiles adds the derived percentile table and fills it for
+// every season already stored.
+var playerStatPercentiles = Migration{
+	Version: 7,
+	Name:    "player_stat_percentiles",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&v7PlayerStatPercentile{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v7PlayerStatPercentile{})
+	},
+	Backfill: services.RefreshAllPercentiles,
+}
+
+type v7PlayerStatPercentile struct {
+	ID                  uint   `gorm:"primaryKey"`
+	Dataset             string `gorm:"not null;uniqueIndex:idx_percentile_dataset_record"`
+	RecordID            uint   `gorm:"not null;uniqueIndex:idx_percentile_dataset_record"`
+	League              string `gorm:"not null;default:NBA;index:idx_percentile_scope"`
+	Season              int    `gorm:"not null;index:idx_percentile_scope"`
+	IsPlayoff           bool   `gorm:"not null;default:false;index:idx_percentile_scope"`
+	Position            string
+	Percentiles         string `gorm:"type:text"`
+	PositionPercentiles string `gorm:"type:text"`
+	UpdatedAt           time.Time
+}
+
+func (v7PlayerStatPercentile) TableName() string { return "player_stat_percentiles" }
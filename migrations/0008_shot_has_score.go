@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// shotHasScore records whether a shot's running score is known. BR
+// tooltips carry one; NBA.com shots, stored with a game ID, do not.
+var shotHasScore = Migration{
+	Version: 8,
+	Name:    "shot_has_score",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if !m.HasColumn(&v8PlayerShotChart{}, "HasScore") {
+			if err := m.AddColumn(&v8PlayerShotChart{}, "HasScore"); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&v8PlayerShotChart{}).
+			Where("game_id = '' OR game_id IS NULL").
+			Update("has_score", true).Error
+	},
+	// A plain DROP COLUMN: GORM's SQLite migrator rebuilds the table to drop
+	// one, which loses the indexes migration 6's Down expects to find.
+	Down: func(tx *gorm.DB) error {
+		return tx.Exec("ALTER TABLE player_shot_charts DROP COLUMN has_score").Error
+	},
+}
+
+type v8PlayerShotChart struct {
+	HasScore bool `gorm:"not null;default:false"`
+}
+
+func (v8PlayerShotChart) TableName() string { return "player_shot_charts" }
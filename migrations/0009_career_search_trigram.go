@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// careerSearchTrigram indexes search_name for the substring and trigram
+// LIKEs player search narrows candidates with. Only postgres has such an
+// index (pg_trgm); elsewhere the step records the version and does nothing.
+var careerSearchTrigram = Migration{
+	Version: 9,
+	Name:    "career_search_trigram",
+	Up: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		if err := tx.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+			return err
+		}
+		return tx.Exec("CREATE INDEX IF NOT EXISTS idx_player_career_stats_search_name_trgm " +
+			"ON player_career_stats USING gin (search_name gin_trgm_ops)").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if tx.Dialector.Name() != "postgres" {
+			return nil
+		}
+		return tx.Exec("DROP INDEX IF EXISTS idx_player_career_stats_search_name_trgm").Error
+	},
+}
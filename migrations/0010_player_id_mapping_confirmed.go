@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// playerIDMappingConfirmed allows at most one confirmed mapping per BR and
+// per NBA.com ID. Existing duplicates are sent back to review first: per
+// ID, a CSV or manual row beats an inferred one, then the more confident,
+// then the older row.
+var playerIDMappingConfirmed = Migration{
+	Version: 10,
+	Name:    "player_id_mapping_confirmed",
+	Up: func(tx *gorm.DB) error {
+		var confirmed []v1PlayerIDMapping
+		if err := tx.Where("status = ?", "confirmed").
+			Order("CASE WHEN source = 'inferred' THEN 1 ELSE 0 END").
+			Order("confidence DESC").Order("id").
+			Find(&confirmed).Error; err != nil {
+			return err
+		}
+		keptBR := make(map[string]bool)
+		keptNBA := make(map[string]bool)
+		var demote []uint
+		for _, m := range confirmed {
+			if keptBR[m.BRID] || keptNBA[m.NBAID] {
+				demote = append(demote, m.ID)
+				continue
+			}
+			keptBR[m.BRID], keptNBA[m.NBAID] = true, true
+		}
+		if len(demote) > 0 {
+			if err := tx.Model(&v1PlayerIDMapping{}).Where("id IN ?", demote).
+				Update("status", "pending").Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_player_id_mapping_confirmed_br " +
+			"ON player_id_mappings (br_id) WHERE status = 'confirmed'").Error; err != nil {
+			return err
+		}
+		return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_player_id_mapping_confirmed_nba " +
+			"ON player_id_mappings (nba_id) WHERE status = 'confirmed'").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Exec("DROP INDEX IF EXISTS idx_player_id_mapping_confirmed_nba").Error; err != nil {
+			return err
+		}
+		return tx.Exec("DROP INDEX IF EXISTS idx_player_id_mapping_confirmed_br").Error
+	},
+}
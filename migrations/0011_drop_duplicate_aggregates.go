@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dropDuplicateAggregates finishes the aggregate rename of migration 3,
+// which skipped soft-deleted rows. Those still hold their unique key, so a
+// player-season could keep a TOT row next to a 2TM/3TM one. Each
+// player-season keeps one aggregate row: a live row beats a soft-deleted
+// one, then the most recently updated wins, then the later ID. The rest
+// are hard-deleted and the survivors renamed to TOT.
+var dropDuplicateAggregates = Migration{
+	Version: 11,
+	Name:    "drop_duplicate_aggregates",
+	Up: func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&v1PlayerTotalStat{}, &v1PlayerAdvancedStat{}} {
+			if err := v11DropDuplicateAggregates(tx, model); err != nil {
+				return err
+			}
+			res := tx.Unscoped().Model(model).
+				Where("team IN ?", v3LegacyAggregateTeams).
+				Updates(map[string]interface{}{"team": "TOT", "is_aggregate": true})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected > 0 {
+				log.Printf("Normalized %d aggregate rows in %T", res.RowsAffected, model)
+			}
+		}
+		return nil
+	},
+	// Dropped duplicates stay dropped; the kept rows are valid either way.
+	Down: func(tx *gorm.DB) error { return nil },
+}
+
+func v11DropDuplicateAggregates(tx *gorm.DB, model interface{}) error {
+	type aggregateRow struct {
+		ID        uint
+		League    string
+		PlayerID  string
+		Season    int
+		IsPlayoff bool
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt
+	}
+	type aggregateKey struct {
+		league, playerID string
+		season           int
+		isPlayoff        bool
+	}
+	var rows []aggregateRow
+	if err := tx.Unscoped().Model(model).
+		Select("id, league, player_id, season, is_playoff, updated_at, deleted_at").
+		Where("team IN ?", v3LegacyAggregateTeams).
+		Order("id").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+
+	newer := func(a, b aggregateRow) bool {
+		if a.DeletedAt.Valid != b.DeletedAt.Valid {
+			return !a.DeletedAt.Valid
+		}
+		return !a.UpdatedAt.Before(b.UpdatedAt) // rows come in ID order
+	}
+	keep := make(map[aggregateKey]aggregateRow)
+	var drop []uint
+	for _, r := range rows {
+		k := aggregateKey{r.League, r.PlayerID, r.Season, r.IsPlayoff}
+		kept, ok := keep[k]
+		switch {
+		case !ok:
+			keep[k] = r
+		case newer(r, kept):
+			drop = append(drop, kept.ID)
+			keep[k] = r
+		default:
+			drop = append(drop, r.ID)
+		}
+	}
+	if len(drop) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Where("id IN ?", drop).Delete(model).Error; err != nil {
+		return err
+	}
+	log.Printf("Dropped %d duplicate aggregate rows in %T", len(drop), model)
+	return nil
+}
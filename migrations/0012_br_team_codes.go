@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"github.com/nprasad2077/NBA_Go/services"
+	"gorm.io/gorm"
+)
+
+// brTeamCodes respells the team codes of stored NBA.com rows the way
+// Basketball-Reference does, as the provider now does on import. BR never
+// stores BKN or PHX, nor CHA after the Bobcats, so only NBA.com rows
+// change; their numeric player IDs cannot collide with BR rows. Career
+// rows list the teams, so they are rebuilt.
+var brTeamCodes = Migration{
+	Version: 12,
+	Name:    "br_team_codes",
+	Up: func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&v1PlayerTotalStat{}, &v1PlayerAdvancedStat{}} {
+			if err := v12Respell(tx, model, "team"); err != nil {
+				return err
+			}
+		}
+		for _, col := range []string{"team", "opponent"} {
+			if err := v12Respell(tx, &v1PlayerShotChart{}, col); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	// BR's spelling is valid for every release; nothing to undo.
+	Down:     func(tx *gorm.DB) error { return nil },
+	Backfill: services.RebuildAllCareerStats,
+}
+
+func v12Respell(tx *gorm.DB, model interface{}, col string) error {
+	for nba, br := range map[string]string{"BKN": "BRK", "PHX": "PHO"} {
+		if err := tx.Unscoped().Model(model).Where(col+" = ?", nba).Update(col, br).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Model(model).Where(col+" = ? AND season >= ?", "CHA", 2015).Update(col, "CHO").Error
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/nprasad2077/NBA_Go/services"
+	"gorm.io/gorm"
+)
+
+// refreshNBAStatsPercentiles rebuilds the stored percentiles, which ranked
+// the zeros NBA.com rows store for the stats it does not report.
+var refreshNBAStatsPercentiles = Migration{
+	Version: 13,
+	Name:    "refresh_nba_stats_percentiles",
+	// The percentiles are derived; the rebuilt ones stay valid either way.
+	Down:     func(tx *gorm.DB) error { return nil },
+	Backfill: services.RefreshAllPercentiles,
+}
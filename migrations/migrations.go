@@ -0,0 +1,236 @@
+// Package migrations holds the ordered, versioned schema migrations. They
+// are compiled into the binary and run against Postgres and SQLite alike;
+// applied versions are recorded in schema_migrations.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrIrreversible is returned when rolling back a migration without a Down step.
+var ErrIrreversible = errors.New("migration is irreversible")
+
+// Migration is one schema change. Up and Down change the schema with
+// frozen structs or SQL; a nil Down makes the migration irreversible.
+// Backfill runs once every pending Up has brought the schema up to date.
+// Steps that rewrite stored rows are frozen in this package along with
+// the structs they use. Only a Backfill rebuilding a derived table the
+// importers also maintain may call the live services; what it writes then
+// depends on the code at migrate time.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       func(tx *gorm.DB) error
+	Down     func(tx *gorm.DB) error
+	Backfill func(tx *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// MigrationStatus is one row of `migrate status`.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// registry lists every migration in version order. Append only: never
+// edit or renumber a migration that has shipped.
+var registry = []Migration{
+	baseline,
+	leagueKeys,
+	backfillDerivedColumns,
+	playerCareerStats,
+	careerSearchName,
+	shotIsPlayoff,
+	playerStatPercentiles,
+	shotHasScore,
+	careerSearchTrigram,
+	playerIDMappingConfirmed,
+	dropDuplicateAggregates,
+	brTeamCodes,
+	refreshNBAStatsPercentiles,
+}
+
+func init() {
+	for i := 1; i < len(registry); i++ {
+		if registry[i].Version <= registry[i-1].Version {
+			panic(fmt.Sprintf("migrations: version %d listed after %d", registry[i].Version, registry[i-1].Version))
+		}
+	}
+}
+
+// Latest is the version the binary expects the database to be at.
+func Latest() int {
+	return registry[len(registry)-1].Version
+}
+
+// Up applies every pending migration in order and returns how many ran.
+// The whole batch runs in one transaction: the Up steps, then the Backfill
+// steps of the same migrations.
+func Up(db *gorm.DB) (int, error) {
+	if err := ensureTable(db); err != nil {
+		return 0, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending []Migration
+	for _, m := range registry {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, m := range pending {
+			log.Printf("⬆️  migrate up %04d %s", m.Version, m.Name)
+			if m.Up != nil {
+				if err := m.Up(tx); err != nil {
+					return fmt.Errorf("migration %04d %s: %w", m.Version, m.Name, err)
+				}
+			}
+			if err := tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error; err != nil {
+				return err
+			}
+		}
+		for _, m := range pending {
+			if m.Backfill == nil {
+				continue
+			}
+			if err := m.Backfill(tx); err != nil {
+				return fmt.Errorf("migration %04d %s backfill: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
+
+// Down rolls back the latest steps applied migrations, newest first, and
+// returns how many were rolled back.
+func Down(db *gorm.DB, steps int) (int, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+	versions := make([]int, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	n := 0
+	for _, v := range versions {
+		if n == steps {
+			break
+		}
+		m, ok := byVersion(v)
+		if !ok {
+			return n, fmt.Errorf("migration %04d is applied but unknown to this binary", v)
+		}
+		if m.Down == nil {
+			return n, fmt.Errorf("migration %04d %s: %w", m.Version, m.Name, ErrIrreversible)
+		}
+		log.Printf("⬇️  migrate down %04d %s", m.Version, m.Name)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return n, fmt.Errorf("migration %04d %s: %w", m.Version, m.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Status reports every known migration and whether it has been applied.
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(registry))
+	for _, m := range registry {
+		s := MigrationStatus{Version: m.Version, Name: m.Name}
+		if rec, ok := applied[m.Version]; ok {
+			s.Applied, s.AppliedAt = true, &rec.AppliedAt
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Pending returns the number of migrations not yet applied.
+func Pending(db *gorm.DB) (int, error) {
+	status, err := Status(db)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, s := range status {
+		if !s.Applied {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ensureTable creates schema_migrations on first use.
+func ensureTable(db *gorm.DB) error {
+	if err := db.Migrator().AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions loads schema_migrations; a missing table means nothing
+// has been applied yet.
+func appliedVersions(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[int]SchemaMigration{}, nil
+	}
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]SchemaMigration, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+func byVersion(v int) (Migration, bool) {
+	for _, m := range registry {
+		if m.Version == v {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
@@ -0,0 +1,182 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func newMigrationTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestUpDownRoundTrip(t *testing.T) {
+	db := newMigrationTestDB(t)
+
+	pending, err := Pending(db)
+	require.NoError(t, err)
+	assert.Equal(t, len(registry), pending)
+
+	n, err := Up(db)
+	require.NoError(t, err)
+	assert.Equal(t, len(registry), n)
+	assert.True(t, db.Migrator().HasTable(&models.PlayerTotalStat{}))
+	assert.True(t, db.Migrator().HasIndex(&models.PlayerTotalStat{}, "idx_total_league_player_season_team"))
+	assert.True(t, db.Migrator().HasIndex(&models.PlayerIDMapping{}, "idx_player_id_mapping_confirmed_br"))
+
+	// The current models work against the migrated schema.
+	require.NoError(t, db.Create(&models.PlayerTotalStat{PlayerID: "hardeja01", Team: "LAC", Season: 2024}).Error)
+	require.NoError(t, db.Create(&models.PlayerTotalStat{PlayerID: "hardeja01", Team: "LAC", Season: 2024, League: models.LeagueWNBA}).Error)
+
+	// Re-running is a no-op.
+	n, err = Up(db)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	status, err := Status(db)
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.True(t, s.Applied, "version %d", s.Version)
+		assert.NotNil(t, s.AppliedAt)
+	}
+
+	// Roll back to version 2.
+	n, err = Down(db, len(registry)-2)
+	require.NoError(t, err)
+	assert.Equal(t, len(registry)-2, n)
+	pending, err = Pending(db)
+	require.NoError(t, err)
+	assert.Equal(t, len(registry)-2, pending)
+
+	// Restoring the NBA-only key fails while the WNBA row shares it.
+	_, err = Down(db, 1)
+	require.Error(t, err)
+	pending, err = Pending(db)
+	require.NoError(t, err)
+	assert.Equal(t, len(registry)-2, pending)
+	require.NoError(t, db.Unscoped().Where("league = ?", models.LeagueWNBA).Delete(&models.PlayerTotalStat{}).Error)
+
+	n, err = Down(db, len(registry))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.False(t, db.Migrator().HasTable(&models.PlayerTotalStat{}))
+
+	n, err = Up(db)
+	require.NoError(t, err)
+	assert.Equal(t, len(registry), n)
+}
+
+func TestUpAdoptsAutoMigratedDatabase(t *testing.T) {
+	db := newMigrationTestDB(t)
+
+	// A database from the AutoMigrate era: current tables, no
+	// schema_migrations, and the NBA-only key still in place.
+	require.NoError(t, db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerShotChart{}))
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX idx_total_player_season_team ON player_total_stats (player_id, team, season, is_playoff)`).Error)
+	require.NoError(t, db.Create(&models.PlayerTotalStat{PlayerID: "hardeja01", PlayerName: "James Harden Jr.", Team: "2TM", Season: 2024}).Error)
+	// A soft-deleted 3TM row next to the live TOT one: the rename in
+	// migration 3 skips it, and migration 11 drops it for the live row.
+	require.NoError(t, db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "westbru01", PlayerName: "Russell Westbrook", Team: "TOT", Season: 2023, Points: 90,
+			UpdatedAt: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{PlayerID: "westbru01", PlayerName: "Russell Westbrook", Team: "3TM", Season: 2023, Points: 95,
+			UpdatedAt: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC),
+			DeletedAt: gorm.DeletedAt{Time: time.Date(2023, time.June, 2, 0, 0, 0, 0, time.UTC), Valid: true}},
+		{PlayerID: "paulch01", PlayerName: "Chris Paul", Team: "2TM", Season: 2021,
+			DeletedAt: gorm.DeletedAt{Time: time.Date(2021, time.June, 2, 0, 0, 0, 0, time.UTC), Valid: true}},
+	}).Error)
+	require.NoError(t, db.Create(&[]models.PlayerShotChart{
+		{PlayerID: "hardeja01", Season: 2024, Date: "Jan 3,2024", TeamScore: 10, OpponentTeamScore: 8},
+		{PlayerID: "201935", Season: 2024, Date: "2024-01-03", GameID: "0022300471", Team: "BKN", Opponent: "CHA"},
+	}).Error)
+	// NBA.com spellings from before the provider mapped them.
+	require.NoError(t, db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "1628369", PlayerName: "Jayson Tatum", Team: "PHX", Season: 2024},
+		{PlayerID: "2730", PlayerName: "Dwight Howard", Team: "CHA", Season: 2014},
+	}).Error)
+
+	_, err := Up(db)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasIndex(&models.PlayerTotalStat{}, "idx_total_player_season_team"))
+	assert.True(t, db.Migrator().HasTable(&models.PlayerIDMapping{}))
+
+	// The backfill migration normalized the existing aggregate row.
+	var row models.PlayerTotalStat
+	require.NoError(t, db.First(&row).Error)
+	assert.Equal(t, "TOT", row.Team)
+	assert.True(t, row.IsAggregate)
+	var westbrook []models.PlayerTotalStat
+	require.NoError(t, db.Unscoped().Where("player_id = ?", "westbru01").Find(&westbrook).Error)
+	if assert.Len(t, westbrook, 1) {
+		assert.Equal(t, "TOT", westbrook[0].Team)
+		assert.Equal(t, 90, westbrook[0].Points)
+		assert.False(t, westbrook[0].DeletedAt.Valid)
+	}
+	var paul models.PlayerTotalStat
+	require.NoError(t, db.Unscoped().Where("player_id = ?", "paulch01").First(&paul).Error)
+	assert.Equal(t, "TOT", paul.Team)
+	assert.True(t, paul.IsAggregate)
+
+	var career models.PlayerCareerStat
+	require.NoError(t, db.Where("player_id = ?", "hardeja01").First(&career).Error)
+	assert.Equal(t, "hardeja01", career.PlayerID)
+	assert.Equal(t, 1, career.Seasons)
+	assert.Equal(t, "james harden", career.SearchName)
+
+	// BR shots carry a score; NBA.com shots do not.
+	var scored []bool
+	require.NoError(t, db.Model(&models.PlayerShotChart{}).Order("id").Pluck("has_score", &scored).Error)
+	assert.Equal(t, []bool{true, false}, scored)
+
+	// Migration 12 respelled the NBA.com team codes.
+	var shot models.PlayerShotChart
+	require.NoError(t, db.Where("player_id = ?", "201935").First(&shot).Error)
+	assert.Equal(t, "BRK", shot.Team)
+	assert.Equal(t, "CHO", shot.Opponent)
+	var teams []string
+	require.NoError(t, db.Model(&models.PlayerTotalStat{}).Where("player_id IN ?", []string{"1628369", "2730"}).Order("season").Pluck("team", &teams).Error)
+	assert.Equal(t, []string{"CHA", "PHO"}, teams)
+	var tatum models.PlayerCareerStat
+	require.NoError(t, db.Where("player_id = ?", "1628369").First(&tatum).Error)
+	assert.Equal(t, "PHO", tatum.Teams)
+
+	var percentile models.PlayerStatPercentile
+	require.NoError(t, db.Where("season = ?", 2024).First(&percentile).Error)
+	assert.Equal(t, row.ID, percentile.RecordID)
+	assert.Equal(t, 2024, percentile.Season)
+}
+
+func TestDownIrreversible(t *testing.T) {
+	saved := registry
+	t.Cleanup(func() { registry = saved })
+	registry = append(append([]Migration{}, saved...), Migration{
+		Version: Latest() + 1,
+		Name:    "one_way",
+		Up:      func(tx *gorm.DB) error { return nil },
+	})
+
+	db := newMigrationTestDB(t)
+	_, err := Up(db)
+	require.NoError(t, err)
+
+	n, err := Down(db, 1)
+	assert.Zero(t, n)
+	assert.True(t, errors.Is(err, ErrIrreversible))
+
+	// Applied by a newer binary: the old registry cannot roll it back.
+	registry = saved
+	_, err = Down(db, 1)
+	assert.ErrorContains(t, err, "unknown to this binary")
+}
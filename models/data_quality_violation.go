@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DataQualityViolation is one row that failed an integrity rule during the
+// last validation run for its dataset, league and season.
+type DataQualityViolation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Dataset   string    `gorm:"not null;index:idx_dq_league_scope" json:"dataset"` // totals, advanced, shots
+	League    string    `gorm:"not null;default:NBA;index:idx_dq_league_scope" json:"league"`
+	Season    int       `gorm:"not null;index:idx_dq_league_scope" json:"season"`
+	IsPlayoff bool      `gorm:"not null;default:false;index:idx_dq_league_scope" json:"isPlayoff"`
+	Rule      string    `gorm:"not null;index" json:"rule"`
+	RecordID  uint      `json:"recordId"`
+	PlayerID  string    `gorm:"index" json:"playerId"`
+	Team      string    `json:"team"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// Leagues whose Basketball-Reference tables share the NBA layout.
+const (
+	LeagueNBA  = "NBA"
+	LeagueWNBA = "WNBA"
+	LeagueABA  = "ABA"
+)
+
+// Leagues lists every supported league; LeagueNBA is the default everywhere.
+var Leagues = []string{LeagueNBA, LeagueWNBA, LeagueABA}
@@ -9,7 +9,8 @@ import (
 type PlayerAdvancedStat struct {
 	ID					uint	`gorm:"primaryKey" swaggerignore:"true"`
 	ExternalID          int     `json:"id"`
-	PlayerID            string  `gorm:"not null;index:idx_player_season_team,unique" json:"playerId"`
+	League              string  `gorm:"not null;default:NBA;index:idx_advanced_league_player_season_team,unique" json:"league"`
+	PlayerID            string  `gorm:"not null;index:idx_advanced_league_player_season_team,unique" json:"playerId"`
 	PlayerName          string  `json:"playerName"`
 	Position            string  `json:"position"`
 	Age                 int     `json:"age"`
@@ -35,9 +36,10 @@ type PlayerAdvancedStat struct {
 	DefensiveBox        float64 `json:"defensiveBox"`
 	Box                 float64 `json:"box"`
 	VORP                float64 `json:"vorp"`
-	Team                string  `gorm:"not null;index:idx_player_season_team,unique" json:"team"`
-	Season              int     `gorm:"not null;index:idx_player_season_team,unique" json:"season"`
-	IsPlayoff			bool	`gorm:"not null;default:false;index:idx_player_season_team,unique" json:"isPlayoff"`
+	Team                string  `gorm:"not null;index:idx_advanced_league_player_season_team,unique" json:"team"`
+	Season              int     `gorm:"not null;index:idx_advanced_league_player_season_team,unique" json:"season"`
+	IsPlayoff			bool	`gorm:"not null;default:false;index:idx_advanced_league_player_season_team,unique" json:"isPlayoff"`
+	IsAggregate			bool	`gorm:"not null;default:false;index" json:"isAggregate"` // multi-team season total (TOT/2TM/3TM)
 	
 	CreatedAt 			time.Time	`swaggerignore:"true"`
 	UpdatedAt 			time.Time	`swaggerignore:"true"`
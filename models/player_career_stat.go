@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// PlayerCareerStat is one player's career totals in a league, regular season
+// or playoffs. It is derived from PlayerTotalStat and rebuilt after every
+// totals import: traded seasons count once (the aggregate row), and the
+// percentages are recomputed from the summed makes and attempts.
+type PlayerCareerStat struct {
+	ID              uint      `gorm:"primaryKey" json:"-"`
+	League          string    `gorm:"not null;default:NBA;uniqueIndex:idx_career_league_player" json:"league"`
+	PlayerID        string    `gorm:"not null;uniqueIndex:idx_career_league_player" json:"playerId"`
+	IsPlayoff       bool      `gorm:"not null;default:false;uniqueIndex:idx_career_league_player" json:"isPlayoff"`
+	PlayerName      string    `json:"playerName"`
+	SearchName      string    `gorm:"index" json:"-"` // services.FoldName(PlayerName)
+	Seasons         int       `json:"seasons"`
+	FirstSeason     int       `json:"firstSeason"`
+	LastSeason      int       `json:"lastSeason"`
+	Teams           string    `json:"teams"` // comma-separated, in the order first played for
+	Games           int       `json:"games"`
+	GamesStarted    int       `json:"gamesStarted"`
+	Minutes         float64   `json:"minutes"`
+	FieldGoals      int       `json:"fieldGoals"`
+	FieldAttempts   int       `json:"fieldAttempts"`
+	FieldPercent    float64   `json:"fieldPercent"`
+	ThreeFG         int       `json:"threeFg"`
+	ThreeAttempts   int       `json:"threeAttempts"`
+	ThreePercent    float64   `json:"threePercent"`
+	TwoFG           int       `json:"twoFg"`
+	TwoAttempts     int       `json:"twoAttempts"`
+	TwoPercent      float64   `json:"twoPercent"`
+	EffectFGPercent float64   `json:"effectFgPercent"`
+	FT              int       `json:"ft"`
+	FTAttempts      int       `json:"ftAttempts"`
+	FTPercent       float64   `json:"ftPercent"`
+	OffensiveRB     int       `json:"offensiveRb"`
+	DefensiveRB     int       `json:"defensiveRb"`
+	TotalRB         int       `json:"totalRb"`
+	Assists         int       `json:"assists"`
+	Steals          int       `json:"steals"`
+	Blocks          int       `json:"blocks"`
+	Turnovers       int       `json:"turnovers"`
+	PersonalFouls   int       `json:"personalFouls"`
+	Points          int       `json:"points"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
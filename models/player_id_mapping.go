@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Player ID mapping review states.
+const (
+	MappingConfirmed = "confirmed"
+	MappingPending   = "pending"
+	MappingRejected  = "rejected"
+)
+
+// Player ID mapping sources.
+const (
+	MappingSourceCSV      = "csv"
+	MappingSourceInferred = "inferred"
+	MappingSourceManual   = "manual"
+)
+
+// PlayerIDMapping links a Basketball-Reference player ID ("hardeja01") to
+// an NBA.com person ID ("201935"). Only confirmed rows are used to resolve
+// IDs; pending rows are the review queue for ambiguous inferred matches.
+// Each ID has at most one confirmed row.
+type PlayerIDMapping struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	BRID       string     `gorm:"column:br_id;not null;uniqueIndex:idx_player_id_mapping;uniqueIndex:idx_player_id_mapping_confirmed_br,where:status = 'confirmed'" json:"brId"`
+	NBAID      string     `gorm:"column:nba_id;not null;uniqueIndex:idx_player_id_mapping;index;uniqueIndex:idx_player_id_mapping_confirmed_nba,where:status = 'confirmed'" json:"nbaId"`
+	PlayerName string     `json:"playerName"`
+	BirthDate  *time.Time `gorm:"type:date" json:"birthDate,omitempty"`
+	Source     string     `gorm:"not null" json:"source"`               // csv, inferred, manual
+	Confidence float64    `gorm:"not null;default:0" json:"confidence"` // 0–1; 1 for csv/manual
+	Status     string     `gorm:"not null;index" json:"status"`         // confirmed, pending, rejected
+	Reason     string     `json:"reason,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
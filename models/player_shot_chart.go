@@ -1,20 +1,25 @@
 // models/player_shot_chart.go
 package models
 
-import "gorm.io/gorm"
+import (
+    "time"
+
+    "gorm.io/gorm"
+)
 
 type PlayerShotChart struct {
     // Auto‑increment primary key — works in SQLite and any other DB.
     ID uint `gorm:"primaryKey" json:"id"`
 
     // ──────────  "identity" columns (the dedup key)  ──────────
-    PlayerID       string `gorm:"not null;uniqueIndex:idx_shot_identity,priority:1" json:"playerId"`
-    Season         int    `gorm:"not null;uniqueIndex:idx_shot_identity,priority:2" json:"season"`
-    Date           string `gorm:"not null;uniqueIndex:idx_shot_identity,priority:3" json:"date"`
-    Quarter        string `gorm:"column:qtr;uniqueIndex:idx_shot_identity,priority:4" json:"qtr"`
-    TimeRemaining  string `gorm:"column:time_remaining;uniqueIndex:idx_shot_identity,priority:5" json:"timeRemaining"`
-    Top            int    `gorm:"uniqueIndex:idx_shot_identity,priority:6" json:"top"`
-    Left           int    `gorm:"uniqueIndex:idx_shot_identity,priority:7" json:"left"`
+    League         string `gorm:"not null;default:NBA;uniqueIndex:idx_shot_league_identity,priority:0" json:"league"`
+    PlayerID       string `gorm:"not null;uniqueIndex:idx_shot_league_identity,priority:1" json:"playerId"`
+    Season         int    `gorm:"not null;uniqueIndex:idx_shot_league_identity,priority:2" json:"season"`
+    Date           string `gorm:"not null;uniqueIndex:idx_shot_league_identity,priority:3" json:"date"`
+    Quarter        string `gorm:"column:qtr;uniqueIndex:idx_shot_league_identity,priority:4" json:"qtr"`
+    TimeRemaining  string `gorm:"column:time_remaining;uniqueIndex:idx_shot_league_identity,priority:5" json:"timeRemaining"`
+    Top            int    `gorm:"uniqueIndex:idx_shot_league_identity,priority:6" json:"top"`
+    Left           int    `gorm:"uniqueIndex:idx_shot_league_identity,priority:7" json:"left"`
 
     // ──────────  the rest of the payload  ──────────
     PlayerName        string `json:"playerName"`
@@ -24,8 +29,25 @@ type PlayerShotChart struct {
     Lead              bool   `json:"lead"`
     TeamScore         int    `gorm:"column:team_score" json:"teamScore"`
     OpponentTeamScore int    `gorm:"column:opponent_team_score" json:"opponentTeamScore"`
+    HasScore          bool   `gorm:"not null;default:false" json:"hasScore"` // false when the source has no running score (NBA.com); the scores are then 0
     Opponent          string `json:"opponent"`
     Team              string `gorm:"not null" json:"team"`
+    GameID            string `gorm:"column:game_id;index" json:"gameId,omitempty"` // NBA.com game ID, when sourced from there
+    IsPlayoff         bool   `gorm:"not null;default:false;index" json:"isPlayoff"`
+
+    // ──────────  typed game context, derived from Date/Quarter/TimeRemaining  ──────────
+    GameDate         *time.Time `gorm:"type:date;index" json:"gameDate"`
+    Period           int        `gorm:"index" json:"period"`                      // 1–4; 5 = 1st OT, 6 = 2nd OT, …
+    IsOvertime       bool       `gorm:"not null;default:false" json:"isOvertime"`
+    SecondsRemaining int        `json:"secondsRemaining"`                         // left in the period
+    ElapsedSeconds   int        `gorm:"index" json:"elapsedSeconds"`              // since tip-off
+    IsHome           *bool      `json:"isHome"`                                   // nil on rows scraped before it was recorded
+
+    // ──────────  court position in feet relative to the basket  ──────────
+    CourtX    float64 `gorm:"column:court_x;index" json:"courtX"`       // right of the basket, shooter's view
+    CourtY    float64 `gorm:"column:court_y;index" json:"courtY"`       // out from the basket toward half court
+    ShotAngle float64 `gorm:"column:shot_angle" json:"shotAngle"`       // degrees off centre; negative = left
+    Zone      string  `gorm:"index" json:"zone"`                        // restricted_area, paint_non_ra, mid_range, …
 
     gorm.Model        `swaggerignore:"true"` // keeps CreatedAt/UpdatedAt/DeletedAt
 }
\ No newline at end of file
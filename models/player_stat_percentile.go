@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PlayerStatPercentile holds one totals or advanced row's percentile (0–100)
+// for each stat among the qualified players of the same league season and
+// season type, overall and within the row's primary position. It is
+// derived and rebuilt for the season after every totals or advanced import.
+type PlayerStatPercentile struct {
+	ID        uint   `gorm:"primaryKey" json:"-"`
+	Dataset   string `gorm:"not null;uniqueIndex:idx_percentile_dataset_record" json:"dataset"` // totals, advanced
+	RecordID  uint   `gorm:"not null;uniqueIndex:idx_percentile_dataset_record" json:"recordId"`
+	League    string `gorm:"not null;default:NBA;index:idx_percentile_scope" json:"league"`
+	Season    int    `gorm:"not null;index:idx_percentile_scope" json:"season"`
+	IsPlayoff bool   `gorm:"not null;default:false;index:idx_percentile_scope" json:"isPlayoff"`
+	Position  string `json:"position"` // primary position, e.g. "PG" for "PG-SG"
+	// Percentiles and PositionPercentiles map stat JSON names to
+	// percentiles; a stat no qualified player has is left out.
+	Percentiles         map[string]float64 `gorm:"serializer:json;type:text" json:"percentiles"`
+	PositionPercentiles map[string]float64 `gorm:"serializer:json;type:text" json:"positionPercentiles"`
+	UpdatedAt           time.Time          `json:"updatedAt"`
+}
@@ -10,7 +10,8 @@ type PlayerTotalStat struct {
 	ID				uint	`gorm:"primaryKey" swaggerignore:"true"`
 
 	ExternalID      int     `json:"id"`
-	PlayerID        string  `gorm:"not null;uniqueIndex:idx_total_player_season_team" json:"playerId"`
+	League          string  `gorm:"not null;default:NBA;uniqueIndex:idx_total_league_player_season_team" json:"league"`
+	PlayerID        string  `gorm:"not null;uniqueIndex:idx_total_league_player_season_team" json:"playerId"`
 	PlayerName      string  `json:"playerName"`
 	Position        string  `json:"position"`
 	Age             int     `json:"age"`
@@ -39,9 +40,10 @@ type PlayerTotalStat struct {
 	Turnovers       int     `json:"turnovers"`
 	PersonalFouls   int     `json:"personalFouls"`
 	Points          int     `json:"points"`
-	Team            string  `gorm:"not null;uniqueIndex:idx_total_player_season_team" json:"team"`
-	Season          int     `gorm:"not null;uniqueIndex:idx_total_player_season_team" json:"season"`
-	IsPlayoff		bool	`gorm:"not null;default:false;uniqueIndex:idx_total_player_season_team" json:"isPlayoff"`
+	Team            string  `gorm:"not null;uniqueIndex:idx_total_league_player_season_team" json:"team"`
+	Season          int     `gorm:"not null;uniqueIndex:idx_total_league_player_season_team" json:"season"`
+	IsPlayoff		bool	`gorm:"not null;default:false;uniqueIndex:idx_total_league_player_season_team" json:"isPlayoff"`
+	IsAggregate		bool	`gorm:"not null;default:false;index" json:"isAggregate"` // multi-team season total (TOT/2TM/3TM)
 	
 	CreatedAt 		time.Time	`swaggerignore:"true"`
 	UpdatedAt 		time.Time	`swaggerignore:"true"`
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Shot chart batch import states. A resumed import skips done and failed
+// players and tries the retry ones again; --force redoes them all.
+const (
+	ShotChartImportDone   = "done"
+	ShotChartImportFailed = "failed" // no shot chart for the season
+	ShotChartImportRetry  = "retry"  // a transient error: network, throttling, database
+)
+
+// ShotChartImportProgress records one player's outcome in a season-wide
+// shot chart import so an interrupted run can resume where it stopped.
+type ShotChartImportProgress struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	Season    int       `gorm:"not null;uniqueIndex:idx_shot_import_season_player" json:"season"`
+	PlayerID  string    `gorm:"not null;uniqueIndex:idx_shot_import_season_player" json:"playerId"`
+	Status    string    `gorm:"not null;index" json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Attempts  int       `gorm:"not null;default:0" json:"attempts"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned for a cursor that is malformed or was
+// issued for a different sort.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset position: the sort value and primary key of the last
+// row already returned. Clients only ever see it encoded.
+type Cursor struct {
+	Column    string      `json:"c"`
+	Ascending bool        `json:"a,omitempty"`
+	Per       string      `json:"p,omitempty"`
+	Scale     float64     `json:"s,omitempty"`
+	Value     interface{} `json:"v"`
+	ID        uint        `json:"i"`
+}
+
+// Encode returns the opaque string handed to clients as nextCursor.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor and checks it was issued for sort.
+func DecodeCursor(s string, sort Sort) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.sort() != sort {
+		return nil, fmt.Errorf("%w: issued for a different sortBy, direction or perMode", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// sort is the order the cursor was issued for.
+func (c Cursor) sort() Sort {
+	return Sort{Column: c.Column, Ascending: c.Ascending, Per: c.Per, Scale: c.Scale}
+}
+
+// NextCursor is the cursor that continues after row under sort.
+func NextCursor[T any](row *T, sort Sort) (string, error) {
+	c := Cursor{Column: sort.Column, Ascending: sort.Ascending, Per: sort.Per, Scale: sort.Scale}
+	var err error
+	if sort.Column != "" {
+		if c.Value, err = sortValue(row, sort); err != nil {
+			return "", err
+		}
+	}
+	id, err := columnValue(row, "id")
+	if err != nil {
+		return "", err
+	}
+	c.ID = uint(toFloat(id))
+	return c.Encode(), nil
+}
+
+// whereAfter restricts query to rows after c in (column, id) order.
+func whereAfter(query *gorm.DB, table string, c *Cursor) *gorm.DB {
+	op := "<"
+	if c.Ascending {
+		op = ">"
+	}
+	id := table + ".id"
+	if c.Column == "" {
+		return query.Where(id+" "+op+" ?", c.ID)
+	}
+	col := sortExpr(table, c.sort())
+	return query.Where(
+		"("+col+" "+op+" ? OR ("+col+" = ? AND "+id+" "+op+" ?))",
+		c.Value, c.Value, c.ID,
+	)
+}
+
+// afterCursor reports whether a row with sort value v and primary key id
+// comes after c.
+func afterCursor(c *Cursor, v interface{}, id uint) bool {
+	cmp := 0
+	if c.Column != "" {
+		cmp = compareValues(v, c.Value)
+	}
+	if cmp == 0 {
+		switch {
+		case id == c.ID:
+			return false
+		case id > c.ID:
+			cmp = 1
+		default:
+			cmp = -1
+		}
+	}
+	if c.Ascending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
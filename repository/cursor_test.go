@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestTotalStatsCursor(t *testing.T) {
+	for name, repos := range implementations(t) {
+		for _, sort := range []Sort{
+			{Column: "points"}, {Column: "season", Ascending: true}, {Column: "team"},
+			{Column: "points", Per: "games", Scale: 1},
+			{Column: "points", Per: "minutes_pg", Scale: 36, Ascending: true},
+		} {
+			q := StatsQuery{Filter: StatsFilter{League: "NBA", TeamMode: TeamModeBoth}, Sort: sort}
+			all, _, err := repos.Totals.List(q)
+			require.NoError(t, err, name)
+
+			// Walking two rows at a time visits every row once, in order.
+			var walked []uint
+			q.Limit, q.SkipCount = 2, true
+			for {
+				rows, total, err := repos.Totals.List(q)
+				require.NoError(t, err, name)
+				assert.Zero(t, total, name)
+				for _, r := range rows {
+					walked = append(walked, r.ID)
+				}
+				if len(rows) < q.Limit {
+					break
+				}
+				next, err := NextCursor(&rows[len(rows)-1], sort)
+				require.NoError(t, err, name)
+				q.After, err = DecodeCursor(next, sort)
+				require.NoError(t, err, name)
+			}
+			want := make([]uint, len(all))
+			for i, r := range all {
+				want[i] = r.ID
+			}
+			assert.Equal(t, want, walked, "%s/%s", name, sort.Column)
+		}
+	}
+}
+
+func TestTotalStatsRateSort(t *testing.T) {
+	for name, repos := range implementations(t) {
+		rows, _, err := repos.Totals.List(StatsQuery{
+			Filter: StatsFilter{League: "NBA", TeamMode: TeamModeBoth},
+			Sort:   Sort{Column: "points", Per: "games", Scale: 1},
+		})
+		require.NoError(t, err, name)
+		var points []int
+		for _, r := range rows {
+			points = append(points, r.Points)
+		}
+		// 31.7, 30.8, 30 (tied, later ID first), 30, 25, and 0 for no games.
+		assert.Equal(t, []int{2000, 1600, 1500, 1800, 200, 300}, points, name)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	row := models.PlayerTotalStat{Points: 1800}
+	row.ID = 7
+	next, err := NextCursor(&row, Sort{Column: "points", Ascending: false})
+	require.NoError(t, err)
+
+	c, err := DecodeCursor(next, Sort{Column: "points", Ascending: false})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), c.ID)
+	assert.EqualValues(t, 1800, c.Value)
+
+	_, err = DecodeCursor(next, Sort{Column: "points", Ascending: true})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = DecodeCursor(next, Sort{Column: "assists", Ascending: false})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = DecodeCursor(next, Sort{Column: "points", Per: "games", Scale: 1})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = DecodeCursor("not a cursor", Sort{Column: "points", Ascending: false})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
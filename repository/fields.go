@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// FieldKind is how a field's values are parsed and compared.
+type FieldKind int
+
+const (
+	FieldNumber FieldKind = iota
+	FieldString
+	FieldBool
+)
+
+// Field is one API-visible model field and the column that stores it.
+type Field struct {
+	Name   string // JSON name, e.g. "per"
+	Column string // database column, e.g. "per"
+	Kind   FieldKind
+}
+
+// Fields indexes a model's API fields by lower-cased JSON name.
+type Fields map[string]Field
+
+// Lookup finds a field by JSON name, ignoring case.
+func (f Fields) Lookup(name string) (Field, bool) {
+	field, ok := f[strings.ToLower(name)]
+	return field, ok
+}
+
+// ModelFields lists the fields of a GORM model that carry a JSON name;
+// untagged bookkeeping fields (ID, timestamps) are left out.
+func ModelFields(model interface{}) Fields {
+	s, err := schema.Parse(model, &fieldsCache, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+	fields := Fields{}
+	for _, f := range s.Fields {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || f.DBName == "" {
+			continue
+		}
+		kind := FieldNumber
+		switch f.DataType {
+		case schema.String:
+			kind = FieldString
+		case schema.Bool:
+			kind = FieldBool
+		case schema.Int, schema.Uint, schema.Float:
+		default:
+			continue
+		}
+		fields[strings.ToLower(name)] = Field{Name: name, Column: f.DBName, Kind: kind}
+	}
+	return fields
+}
+
+var fieldsCache sync.Map
+
+// Select parses a comma-separated list of JSON field names; "" selects
+// nothing (meaning every field) and an unknown name is an error.
+func (f Fields) Select(list string) ([]Field, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	var out []Field
+	for _, name := range strings.Split(list, ",") {
+		field, ok := f.Lookup(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		out = append(out, field)
+	}
+	return out, nil
+}
+
+// All returns every field, sorted by JSON name.
+func (f Fields) All() []Field {
+	out := make([]Field, 0, len(f))
+	for _, field := range f {
+		out = append(out, field)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Project turns model rows into maps holding only the given fields, keyed
+// by JSON name.
+func Project[T any](rows []T, fields []Field) ([]map[string]interface{}, error) {
+	out := make([]map[string]interface{}, len(rows))
+	for i := range rows {
+		m := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			v, err := columnValue(&rows[i], field.Column)
+			if err != nil {
+				return nil, err
+			}
+			m[field.Name] = v
+		}
+		out[i] = m
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Filter operators accepted in filter expressions.
+const (
+	OpEq      = "eq"
+	OpNe      = "ne"
+	OpGt      = "gt"
+	OpGte     = "gte"
+	OpLt      = "lt"
+	OpLte     = "lte"
+	OpBetween = "between"
+	OpIn      = "in"
+)
+
+// opsByKind lists the operators each kind of field supports.
+var opsByKind = map[FieldKind][]string{
+	FieldNumber: {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpBetween, OpIn},
+	FieldString: {OpEq, OpNe, OpIn},
+	FieldBool:   {OpEq, OpNe},
+}
+
+// sqlOps are the single-value operators' SQL forms.
+var sqlOps = map[string]string{
+	OpEq: "=", OpNe: "<>", OpGt: ">", OpGte: ">=", OpLt: "<", OpLte: "<=",
+}
+
+// Condition is one parsed filter clause over a whitelisted column.
+type Condition struct {
+	Column string
+	Op     string
+	Values []interface{}
+}
+
+// ParseFilter parses a filter expression such as
+//
+//	per:gte:20,games:gte:50,season:between:2015:2024,team:in:LAL|BOS
+//
+// Clauses are comma-separated field:op:value triples; between takes two
+// values and in takes a |-separated list. Fields must be in fields.
+func ParseFilter(expr string, fields Fields) ([]Condition, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	var conds []Condition
+	for _, clause := range strings.Split(expr, ",") {
+		parts := strings.Split(strings.TrimSpace(clause), ":")
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("invalid filter %q (want field:op:value)", clause)
+		}
+		field, ok := fields.Lookup(parts[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown filter field %q", parts[0])
+		}
+		op := strings.ToLower(parts[1])
+		if !slices.Contains(opsByKind[field.Kind], op) {
+			return nil, fmt.Errorf("operator %q not supported on %s", parts[1], field.Name)
+		}
+
+		var raw []string
+		switch op {
+		case OpBetween:
+			if len(parts) != 4 {
+				return nil, fmt.Errorf("%s:between needs two values", field.Name)
+			}
+			raw = parts[2:]
+		case OpIn:
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("invalid filter %q", clause)
+			}
+			raw = strings.Split(parts[2], "|")
+		default:
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("invalid filter %q", clause)
+			}
+			raw = parts[2:]
+		}
+
+		values := make([]interface{}, len(raw))
+		for i, r := range raw {
+			v, err := parseFilterValue(field, r)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		conds = append(conds, Condition{Column: field.Column, Op: op, Values: values})
+	}
+	return conds, nil
+}
+
+func parseFilterValue(field Field, raw string) (interface{}, error) {
+	switch field.Kind {
+	case FieldNumber:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("invalid number %q for %s", raw, field.Name)
+		}
+		return f, nil
+	case FieldBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q for %s", raw, field.Name)
+		}
+		return b, nil
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("empty value for %s", field.Name)
+	}
+	return raw, nil
+}
+
+// whereConditions adds conds to query as parameterized clauses.
+func whereConditions(query *gorm.DB, conds []Condition) *gorm.DB {
+	for _, c := range conds {
+		switch c.Op {
+		case OpBetween:
+			query = query.Where(c.Column+" BETWEEN ? AND ?", c.Values[0], c.Values[1])
+		case OpIn:
+			query = query.Where(c.Column+" IN ?", c.Values)
+		default:
+			query = query.Where(c.Column+" "+sqlOps[c.Op]+" ?", c.Values[0])
+		}
+	}
+	return query
+}
+
+// matchConditions evaluates conds against a model row in memory.
+func matchConditions[T any](row *T, conds []Condition) (bool, error) {
+	for _, c := range conds {
+		v, err := columnValue(row, c.Column)
+		if err != nil {
+			return false, err
+		}
+		var ok bool
+		switch c.Op {
+		case OpEq:
+			ok = compareValues(v, c.Values[0]) == 0
+		case OpNe:
+			ok = compareValues(v, c.Values[0]) != 0
+		case OpGt:
+			ok = compareValues(v, c.Values[0]) > 0
+		case OpGte:
+			ok = compareValues(v, c.Values[0]) >= 0
+		case OpLt:
+			ok = compareValues(v, c.Values[0]) < 0
+		case OpLte:
+			ok = compareValues(v, c.Values[0]) <= 0
+		case OpBetween:
+			ok = compareValues(v, c.Values[0]) >= 0 && compareValues(v, c.Values[1]) <= 0
+		case OpIn:
+			ok = slices.ContainsFunc(c.Values, func(x interface{}) bool { return compareValues(v, x) == 0 })
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
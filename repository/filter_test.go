@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestParseFilter(t *testing.T) {
+	fields := ModelFields(&models.PlayerAdvancedStat{})
+
+	conds, err := ParseFilter("per:gte:20,Games:gte:50,season:between:2015:2024,team:in:LAL|BOS,isPlayoff:eq:false", fields)
+	require.NoError(t, err)
+	assert.Equal(t, []Condition{
+		{Column: "per", Op: OpGte, Values: []interface{}{20.0}},
+		{Column: "games", Op: OpGte, Values: []interface{}{50.0}},
+		{Column: "season", Op: OpBetween, Values: []interface{}{2015.0, 2024.0}},
+		{Column: "team", Op: OpIn, Values: []interface{}{"LAL", "BOS"}},
+		{Column: "is_playoff", Op: OpEq, Values: []interface{}{false}},
+	}, conds)
+
+	conds, err = ParseFilter("", fields)
+	require.NoError(t, err)
+	assert.Nil(t, conds)
+
+	for _, bad := range []string{
+		"bogus:gte:1",          // unknown field
+		"createdAt:gte:1",      // not an API field
+		"per:like:20",          // unknown operator
+		"team:gte:LAL",         // operator not valid for strings
+		"per:gte:abc",          // not a number
+		"per:gte:NaN",          // not a finite number
+		"per:lte:-Inf",         // not a finite number
+		"per:between:1",        // between needs two values
+		"per:gte:1:2",          // too many values
+		"per:gte",              // missing value
+		"team:eq:",             // empty string
+		"isPlayoff:eq:perhaps", // not a boolean
+	} {
+		_, err := ParseFilter(bad, fields)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestTotalStatsListConditions(t *testing.T) {
+	fields := ModelFields(&models.PlayerTotalStat{})
+	cases := []struct {
+		expr      string
+		wantTotal int64
+	}{
+		{"points:gte:1600", 2},
+		{"points:between:1500:1800,season:eq:2021", 1},
+		{"team:in:GSW|HOU", 4}, // a team condition implies split mode
+		{"team:ne:GSW", 2},
+		{"playerId:eq:curryst01,points:lt:1000", 1},
+		{"isPlayoff:eq:true", 1},
+	}
+	for name, repos := range implementations(t) {
+		for _, tc := range cases {
+			conds, err := ParseFilter(tc.expr, fields)
+			require.NoError(t, err, tc.expr)
+			mode, err := ParseTeamMode("", "", conds)
+			require.NoError(t, err, tc.expr)
+			_, total, err := repos.Totals.List(StatsQuery{
+				Filter: StatsFilter{League: models.LeagueNBA, TeamMode: mode, Conditions: conds},
+				Sort:   Sort{Column: "points"},
+			})
+			require.NoError(t, err, name+"/"+tc.expr)
+			assert.Equal(t, tc.wantTotal, total, name+"/"+tc.expr)
+		}
+	}
+}
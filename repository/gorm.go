@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"gorm.io/gorm"
+)
+
+// NewGorm returns the repositories backed by db.
+func NewGorm(db *gorm.DB) Repositories {
+	return Repositories{
+		Totals:      gormTotalStats{db},
+		Advanced:    gormAdvancedStats{db},
+		Careers:     gormCareerStats{db},
+		Percentiles: gormPercentiles{db},
+		Shots:       gormShotCharts{db},
+		APIKeys:     gormAPIKeys{db},
+		PlayerIDs:   gormPlayerIDs{db},
+	}
+}
+
+// whereStats applies a StatsFilter to a query over table.
+func whereStats(query *gorm.DB, table string, f StatsFilter) *gorm.DB {
+	query = query.Where("league = ?", f.League)
+	if f.Season != 0 {
+		query = query.Where("season = ?", f.Season)
+	}
+	if f.Team != "" {
+		query = query.Where("team = ?", f.Team)
+	}
+	if f.PlayerIDs != nil {
+		query = query.Where("player_id IN ?", f.PlayerIDs)
+	}
+	if f.IsPlayoff != nil {
+		query = query.Where("is_playoff = ?", *f.IsPlayoff)
+	}
+	query = whereConditions(query, f.Conditions)
+	return applyTeamMode(query, table, f.TeamMode)
+}
+
+// listStats counts and fetches one page of q into dest.
+func listStats(db *gorm.DB, model interface{}, table string, q StatsQuery, dest interface{}) (int64, error) {
+	query := whereStats(db.Model(model), table, q.Filter)
+
+	var total int64
+	if !q.SkipCount {
+		if err := query.Count(&total).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	if len(q.Columns) > 0 {
+		// The cursor of the last row needs its sort value and ID.
+		columns := slices.Clone(q.Columns)
+		for _, col := range []string{"id", q.Sort.Column, q.Sort.Per} {
+			if col != "" && !slices.Contains(columns, col) {
+				columns = append(columns, col)
+			}
+		}
+		query = query.Select(columns)
+	}
+	if q.After != nil {
+		query = whereAfter(query, table, q.After)
+	}
+	query = orderKeyset(query, table, q.Sort).Offset(q.Offset)
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+	return total, query.Find(dest).Error
+}
+
+// orderKeyset orders by the sort column (or rate) with the primary key as
+// tie-breaker.
+func orderKeyset(query *gorm.DB, table string, s Sort) *gorm.DB {
+	dir := " DESC"
+	if s.Ascending {
+		dir = " ASC"
+	}
+	if s.Column != "" {
+		query = query.Order(sortExpr(table, s) + dir)
+	}
+	return query.Order(table + ".id" + dir)
+}
+
+type gormTotalStats struct{ db *gorm.DB }
+
+func (r gormTotalStats) List(q StatsQuery) ([]models.PlayerTotalStat, int64, error) {
+	var stats []models.PlayerTotalStat
+	total, err := listStats(r.db, &models.PlayerTotalStat{}, "player_total_stats", q, &stats)
+	return stats, total, err
+}
+
+func (r gormTotalStats) ListByKeys(keys []SeasonKey) ([]models.PlayerTotalStat, error) {
+	return listByKeys(r.db, keys, TotalSeasonKey)
+}
+
+type gormAdvancedStats struct{ db *gorm.DB }
+
+func (r gormAdvancedStats) List(q StatsQuery) ([]models.PlayerAdvancedStat, int64, error) {
+	var stats []models.PlayerAdvancedStat
+	total, err := listStats(r.db, &models.PlayerAdvancedStat{}, "player_advanced_stats", q, &stats)
+	return stats, total, err
+}
+
+func (r gormAdvancedStats) ListByKeys(keys []SeasonKey) ([]models.PlayerAdvancedStat, error) {
+	return listByKeys(r.db, keys, AdvancedSeasonKey)
+}
+
+type gormCareerStats struct{ db *gorm.DB }
+
+// where applies q's filters to a career query.
+func (r gormCareerStats) where(q CareerQuery) *gorm.DB {
+	query := r.db.Model(&models.PlayerCareerStat{}).Where("league = ? AND is_playoff = ?", q.League, q.IsPlayoff)
+	if q.PlayerIDs != nil {
+		query = query.Where("player_id IN ?", q.PlayerIDs)
+	}
+	return whereConditions(query, q.Conditions)
+}
+
+func (r gormCareerStats) List(q CareerQuery) ([]models.PlayerCareerStat, error) {
+	query := r.where(q)
+	if q.After != nil {
+		query = whereAfter(query, "player_career_stats", q.After)
+	}
+	query = orderKeyset(query, "player_career_stats", q.Sort).Offset(q.Offset)
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+	var stats []models.PlayerCareerStat
+	err := query.Find(&stats).Error
+	return stats, err
+}
+
+func (r gormCareerStats) Count(q CareerQuery) (int64, error) {
+	var total int64
+	err := r.where(q).Count(&total).Error
+	return total, err
+}
+
+func (r gormCareerStats) SearchCandidates(league, query string) ([]models.PlayerCareerStat, error) {
+	var rows []models.PlayerCareerStat
+	err := r.db.Select("player_id, player_name, search_name, first_season, last_season, minutes").
+		Where("league = ?", league).
+		Where(searchCondition(r.db, query)).
+		Find(&rows).Error
+	return rows, err
+}
+
+// SearchMinFuzzyLen is the shortest query word the search ranking
+// fuzzy-matches; shorter words would match everything.
+const SearchMinFuzzyLen = 4
+
+// searchCondition is the condition a name needs for the search ranking to
+// have a chance: it holds the whole query, every query word starts one of
+// its words, or it shares a three-letter run with a query word long enough
+// to fuzzy-match. A typo that leaves no such run intact is not found. On
+// postgres the pg_trgm index from migration 9 serves these LIKEs.
+func searchCondition(db *gorm.DB, query string) *gorm.DB {
+	tx := db.Session(&gorm.Session{NewDB: true})
+	words := strings.Fields(query)
+	cond := tx.Where("search_name LIKE ?", "%"+query+"%")
+
+	prefixes := tx
+	for _, w := range words {
+		prefixes = prefixes.Where("(search_name LIKE ? OR search_name LIKE ?)", w+"%", "% "+w+"%")
+	}
+	cond = cond.Or(prefixes)
+
+	seen := map[string]bool{}
+	for _, w := range words {
+		r := []rune(w)
+		if len(r) < SearchMinFuzzyLen {
+			continue
+		}
+		for i := 0; i+3 <= len(r); i++ {
+			if g := string(r[i : i+3]); !seen[g] {
+				seen[g] = true
+				cond = cond.Or("search_name LIKE ?", "%"+g+"%")
+			}
+		}
+	}
+	return cond
+}
+
+type gormPercentiles struct{ db *gorm.DB }
+
+func (r gormPercentiles) ListByRecords(dataset string, ids []uint) ([]models.PlayerStatPercentile, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var rows []models.PlayerStatPercentile
+	err := r.db.Where("dataset = ? AND record_id IN ?", dataset, ids).Find(&rows).Error
+	return rows, err
+}
+
+// listByKeys narrows by player and season in SQL, then keeps exact key
+// matches; key(row) reports a row's SeasonKey.
+func listByKeys[T any](db *gorm.DB, keys []SeasonKey, key func(*T) SeasonKey) ([]T, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var players []string
+	var seasons []int
+	want := make(map[SeasonKey]bool, len(keys))
+	for _, k := range keys {
+		players = append(players, k.PlayerID)
+		seasons = append(seasons, k.Season)
+		want[k] = true
+	}
+
+	var rows []T
+	if err := db.Where("player_id IN ? AND season IN ?", players, seasons).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	matched := rows[:0]
+	for i := range rows {
+		if want[key(&rows[i])] {
+			matched = append(matched, rows[i])
+		}
+	}
+	return matched, nil
+}
+
+// TotalSeasonKey is a totals row's SeasonKey.
+func TotalSeasonKey(s *models.PlayerTotalStat) SeasonKey {
+	return SeasonKey{s.League, s.PlayerID, s.Season, s.Team, s.IsPlayoff}
+}
+
+// AdvancedSeasonKey is an advanced row's SeasonKey.
+func AdvancedSeasonKey(s *models.PlayerAdvancedStat) SeasonKey {
+	return SeasonKey{s.League, s.PlayerID, s.Season, s.Team, s.IsPlayoff}
+}
+
+type gormShotCharts struct{ db *gorm.DB }
+
+func (r gormShotCharts) List(q ShotQuery) ([]models.PlayerShotChart, int64, error) {
+	query := whereShots(r.db.Model(&models.PlayerShotChart{}), q.Filter)
+
+	var total int64
+	if !q.SkipCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	if len(q.Columns) > 0 {
+		query = query.Select(append([]string{"id"}, q.Columns...))
+	}
+	if q.After != nil {
+		query = whereAfter(query, "player_shot_charts", q.After)
+	}
+	query = orderKeyset(query, "player_shot_charts", Sort{Ascending: true}).Offset(q.Offset)
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+
+	var shots []models.PlayerShotChart
+	err := query.Find(&shots).Error
+	return shots, total, err
+}
+
+func (r gormShotCharts) Tally(f ShotFilter, groupBy []string) ([]ShotTally, error) {
+	if err := checkTallyColumns(groupBy); err != nil {
+		return nil, err
+	}
+	query := whereShots(r.db.Model(&models.PlayerShotChart{}), f)
+	cols := make([]string, len(groupBy))
+	for i, c := range groupBy {
+		cols[i] = query.Statement.Quote(c) // "top" and "left" are keywords
+	}
+	query = query.Select(strings.Join(append(slices.Clone(cols),
+		"COUNT(*) AS attempts",
+		"COALESCE(SUM(CASE WHEN result THEN 1 ELSE 0 END), 0) AS makes",
+		"COALESCE(SUM(CASE WHEN result THEN CASE WHEN shot_type = '3-pointer' THEN 3 ELSE 2 END ELSE 0 END), 0) AS points",
+	), ", "))
+	if len(cols) > 0 {
+		query = query.Group(strings.Join(cols, ", ")).Order(strings.Join(cols, ", "))
+	}
+	var tallies []ShotTally
+	err := query.Scan(&tallies).Error
+	return tallies, err
+}
+
+// checkTallyColumns rejects a column a shot tally cannot group on.
+func checkTallyColumns(groupBy []string) error {
+	for _, c := range groupBy {
+		if !slices.Contains(ShotTallyColumns, c) {
+			return fmt.Errorf("cannot group shots on %q", c)
+		}
+	}
+	return nil
+}
+
+// whereShots applies a ShotFilter to a query over player_shot_charts.
+func whereShots(query *gorm.DB, f ShotFilter) *gorm.DB {
+	query = query.Where("league = ?", f.League)
+	if f.PlayerIDs != nil {
+		query = query.Where("player_id IN ?", f.PlayerIDs)
+	}
+	if f.Season != 0 {
+		query = query.Where("season = ?", f.Season)
+	}
+	if f.FromSeason != 0 {
+		query = query.Where("season >= ?", f.FromSeason)
+	}
+	if f.ToSeason != 0 {
+		query = query.Where("season <= ?", f.ToSeason)
+	}
+	if len(f.Zones) > 0 {
+		query = query.Where("zone IN ?", f.Zones)
+	}
+	switch {
+	case len(f.Periods) > 0 && f.Overtime:
+		query = query.Where("(period IN ? OR is_overtime = ?)", f.Periods, true)
+	case len(f.Periods) > 0:
+		query = query.Where("period IN ?", f.Periods)
+	case f.Overtime:
+		query = query.Where("is_overtime = ?", true)
+	}
+	if f.Made != nil {
+		query = query.Where("result = ?", *f.Made)
+	}
+	if f.ShotType != "" {
+		query = query.Where("shot_type = ?", f.ShotType)
+	}
+	if f.Team != "" {
+		query = query.Where("team = ?", f.Team)
+	}
+	if f.Opponent != "" {
+		query = query.Where("opponent = ?", f.Opponent)
+	}
+	// Shots without a running score match no score filter.
+	if f.Score != "" || f.MinMargin != nil || f.MaxMargin != nil {
+		query = query.Where("has_score = ?", true)
+	}
+	switch f.Score {
+	case ScoreLeading:
+		query = query.Where("team_score > opponent_team_score")
+	case ScoreTrailing:
+		query = query.Where("team_score < opponent_team_score")
+	case ScoreTied:
+		query = query.Where("team_score = opponent_team_score")
+	}
+	if f.IsPlayoff != nil {
+		query = query.Where("is_playoff = ?", *f.IsPlayoff)
+	}
+	if f.DateFrom != nil {
+		query = query.Where("game_date >= ?", *f.DateFrom)
+	}
+	if f.DateTo != nil {
+		query = query.Where("game_date <= ?", *f.DateTo)
+	}
+	for _, b := range []struct {
+		cond string
+		v    *float64
+	}{
+		{"court_x >= ?", f.MinX}, {"court_x <= ?", f.MaxX},
+		{"court_y >= ?", f.MinY}, {"court_y <= ?", f.MaxY},
+	} {
+		if b.v != nil {
+			query = query.Where(b.cond, *b.v)
+		}
+	}
+	for _, b := range []struct {
+		cond string
+		v    *int
+	}{
+		{"distance_ft >= ?", f.MinDistance}, {"distance_ft <= ?", f.MaxDistance},
+		{"team_score - opponent_team_score >= ?", f.MinMargin},
+		{"team_score - opponent_team_score <= ?", f.MaxMargin},
+	} {
+		if b.v != nil {
+			query = query.Where(b.cond, *b.v)
+		}
+	}
+	return query
+}
+
+type gormAPIKeys struct{ db *gorm.DB }
+
+func (r gormAPIKeys) List() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Find(&keys).Error
+	return keys, err
+}
+
+func (r gormAPIKeys) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r gormAPIKeys) Revoke(id uint) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("revoked", true).Error
+}
+
+func (r gormAPIKeys) FindActive(hash []byte) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.Where("hash = ? AND revoked = ?", hash, false).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+type gormPlayerIDs struct{ db *gorm.DB }
+
+func (r gormPlayerIDs) ResolvePlayerIDs(id string) ([]string, error) {
+	var mappings []models.PlayerIDMapping
+	if err := r.db.Where("status = ? AND (br_id = ? OR nba_id = ?)", models.MappingConfirmed, id, id).
+		Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	ids := []string{id}
+	for _, m := range mappings {
+		other := m.NBAID
+		if other == id {
+			other = m.BRID
+		}
+		if !slices.Contains(ids, other) {
+			ids = append(ids, other)
+		}
+	}
+	return ids, nil
+}
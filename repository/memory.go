@@ -0,0 +1,562 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"gorm.io/gorm/schema"
+)
+
+// Memory holds every dataset in slices and implements the repositories
+// over them with the same semantics as the GORM versions. Seed the fields
+// directly, then hand out Repositories(); give rows distinct IDs, as the
+// database would, for cursor pagination to work.
+type Memory struct {
+	mu       sync.RWMutex
+	Totals   []models.PlayerTotalStat
+	Advanced []models.PlayerAdvancedStat
+	Careers  []models.PlayerCareerStat
+	// Percentiles are looked up by dataset and record ID.
+	Percentiles []models.PlayerStatPercentile
+	Shots       []models.PlayerShotChart
+	Keys        []models.APIKey
+	// IDMappings maps a BR player ID to its confirmed NBA.com IDs; lookups
+	// work in both directions.
+	IDMappings map[string][]string
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{IDMappings: map[string][]string{}}
+}
+
+// Repositories returns the repositories backed by m.
+func (m *Memory) Repositories() Repositories {
+	return Repositories{
+		Totals:      memoryTotalStats{m},
+		Advanced:    memoryAdvancedStats{m},
+		Careers:     memoryCareerStats{m},
+		Percentiles: memoryPercentiles{m},
+		Shots:       memoryShotCharts{m},
+		APIKeys:     memoryAPIKeys{m},
+		PlayerIDs:   memoryPlayerIDs{m},
+	}
+}
+
+type memoryTotalStats struct{ m *Memory }
+
+func (r memoryTotalStats) List(q StatsQuery) ([]models.PlayerTotalStat, int64, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return listMemoryStats(r.m.Totals, q, func(s *models.PlayerTotalStat) rowFacts {
+		return rowFacts{s.League, s.PlayerID, s.Team, s.Season, s.IsPlayoff, s.IsAggregate, s.DeletedAt.Valid}
+	})
+}
+
+func (r memoryTotalStats) ListByKeys(keys []SeasonKey) ([]models.PlayerTotalStat, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return memoryByKeys(r.m.Totals, keys, TotalSeasonKey, func(s *models.PlayerTotalStat) bool { return s.DeletedAt.Valid }), nil
+}
+
+type memoryAdvancedStats struct{ m *Memory }
+
+func (r memoryAdvancedStats) List(q StatsQuery) ([]models.PlayerAdvancedStat, int64, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return listMemoryStats(r.m.Advanced, q, func(s *models.PlayerAdvancedStat) rowFacts {
+		return rowFacts{s.League, s.PlayerID, s.Team, s.Season, s.IsPlayoff, s.IsAggregate, s.DeletedAt.Valid}
+	})
+}
+
+func (r memoryAdvancedStats) ListByKeys(keys []SeasonKey) ([]models.PlayerAdvancedStat, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return memoryByKeys(r.m.Advanced, keys, AdvancedSeasonKey, func(s *models.PlayerAdvancedStat) bool { return s.DeletedAt.Valid }), nil
+}
+
+type memoryCareerStats struct{ m *Memory }
+
+func (r memoryCareerStats) List(q CareerQuery) ([]models.PlayerCareerStat, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	matched, err := r.match(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := sortByColumn(matched, q.Sort); err != nil {
+		return nil, err
+	}
+	if matched, err = rowsAfter(matched, q.After); err != nil {
+		return nil, err
+	}
+	return page(matched, q.Limit, q.Offset), nil
+}
+
+func (r memoryCareerStats) Count(q CareerQuery) (int64, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	matched, err := r.match(q)
+	return int64(len(matched)), err
+}
+
+// SearchCandidates returns every career in the league and leaves the
+// narrowing to the ranking.
+func (r memoryCareerStats) SearchCandidates(league, query string) ([]models.PlayerCareerStat, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var rows []models.PlayerCareerStat
+	for _, s := range r.m.Careers {
+		if s.League == league {
+			rows = append(rows, s)
+		}
+	}
+	return rows, nil
+}
+
+// match returns the careers passing q's filters; the caller holds the lock.
+func (r memoryCareerStats) match(q CareerQuery) ([]models.PlayerCareerStat, error) {
+	var matched []models.PlayerCareerStat
+	for i := range r.m.Careers {
+		s := &r.m.Careers[i]
+		if s.League != q.League || s.IsPlayoff != q.IsPlayoff ||
+			q.PlayerIDs != nil && !slices.Contains(q.PlayerIDs, s.PlayerID) {
+			continue
+		}
+		ok, err := matchConditions(s, q.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, *s)
+		}
+	}
+	return matched, nil
+}
+
+type memoryPercentiles struct{ m *Memory }
+
+func (r memoryPercentiles) ListByRecords(dataset string, ids []uint) ([]models.PlayerStatPercentile, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var out []models.PlayerStatPercentile
+	for _, p := range r.m.Percentiles {
+		if p.Dataset == dataset && slices.Contains(ids, p.RecordID) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// memoryByKeys returns the live rows whose key is one of keys.
+func memoryByKeys[T any](rows []T, keys []SeasonKey, key func(*T) SeasonKey, deleted func(*T) bool) []T {
+	var out []T
+	for i := range rows {
+		if !deleted(&rows[i]) && slices.Contains(keys, key(&rows[i])) {
+			out = append(out, rows[i])
+		}
+	}
+	return out
+}
+
+// rowFacts are the parts of a season stats row the filters look at.
+type rowFacts struct {
+	league, playerID, team string
+	season                 int
+	isPlayoff, isAggregate bool
+	deleted                bool
+}
+
+// listMemoryStats filters, sorts and pages rows like listStats does in SQL.
+func listMemoryStats[T any](rows []T, q StatsQuery, key func(*T) rowFacts) ([]T, int64, error) {
+	f := q.Filter
+	type seasonKey struct {
+		league, playerID string
+		season           int
+		isPlayoff        bool
+	}
+	hasAggregate := map[seasonKey]bool{}
+	for i := range rows {
+		if k := key(&rows[i]); k.isAggregate && !k.deleted {
+			hasAggregate[seasonKey{k.league, k.playerID, k.season, k.isPlayoff}] = true
+		}
+	}
+
+	var matched []T
+	for i := range rows {
+		k := key(&rows[i])
+		switch {
+		case k.deleted,
+			k.league != f.League,
+			f.Season != 0 && k.season != f.Season,
+			f.Team != "" && k.team != f.Team,
+			f.PlayerIDs != nil && !slices.Contains(f.PlayerIDs, k.playerID),
+			f.IsPlayoff != nil && k.isPlayoff != *f.IsPlayoff:
+			continue
+		}
+		switch f.TeamMode {
+		case TeamModeSplit:
+			if k.isAggregate {
+				continue
+			}
+		case TeamModeBoth:
+		default:
+			if !k.isAggregate && hasAggregate[seasonKey{k.league, k.playerID, k.season, k.isPlayoff}] {
+				continue
+			}
+		}
+		ok, err := matchConditions(&rows[i], f.Conditions)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			matched = append(matched, rows[i])
+		}
+	}
+
+	if err := sortByColumn(matched, q.Sort); err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if !q.SkipCount {
+		total = int64(len(matched))
+	}
+	matched, err := rowsAfter(matched, q.After)
+	if err != nil {
+		return nil, 0, err
+	}
+	return page(matched, q.Limit, q.Offset), total, nil
+}
+
+// rowsAfter drops the rows up to and including the cursor position; rows
+// must already be in keyset order.
+func rowsAfter[T any](rows []T, c *Cursor) ([]T, error) {
+	if c == nil {
+		return rows, nil
+	}
+	for i := range rows {
+		var v interface{}
+		if c.Column != "" {
+			var err error
+			if v, err = sortValue(&rows[i], c.sort()); err != nil {
+				return nil, err
+			}
+		}
+		id, err := columnValue(&rows[i], "id")
+		if err != nil {
+			return nil, err
+		}
+		if afterCursor(c, v, uint(toFloat(id))) {
+			return rows[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+type memoryShotCharts struct{ m *Memory }
+
+func (r memoryShotCharts) List(q ShotQuery) ([]models.PlayerShotChart, int64, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	var shots []models.PlayerShotChart
+	for _, s := range r.m.Shots {
+		if matchShot(&s, q.Filter) {
+			shots = append(shots, s)
+		}
+	}
+	if err := sortByColumn(shots, Sort{Ascending: true}); err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if !q.SkipCount {
+		total = int64(len(shots))
+	}
+	shots, err := rowsAfter(shots, q.After)
+	if err != nil {
+		return nil, 0, err
+	}
+	return page(shots, q.Limit, q.Offset), total, nil
+}
+
+func (r memoryShotCharts) Tally(f ShotFilter, groupBy []string) ([]ShotTally, error) {
+	if err := checkTallyColumns(groupBy); err != nil {
+		return nil, err
+	}
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	byKey := map[ShotTally]*ShotTally{}
+	var keys []ShotTally
+	for i := range r.m.Shots {
+		s := &r.m.Shots[i]
+		if !matchShot(s, f) {
+			continue
+		}
+		t := TallyShot(s)
+		var key ShotTally
+		for _, c := range groupBy {
+			switch c {
+			case "season":
+				key.Season = t.Season
+			case "is_playoff":
+				key.IsPlayoff = t.IsPlayoff
+			case "top":
+				key.Top = t.Top
+			case "left":
+				key.Left = t.Left
+			case "distance_ft":
+				key.DistanceFt = t.DistanceFt
+			case "shot_type":
+				key.ShotType = t.ShotType
+			}
+		}
+		sum, ok := byKey[key]
+		if !ok {
+			sum = &ShotTally{}
+			*sum = key
+			byKey[key] = sum
+			keys = append(keys, key)
+		}
+		sum.Attempts += t.Attempts
+		sum.Makes += t.Makes
+		sum.Points += t.Points
+	}
+	if len(keys) == 0 && len(groupBy) == 0 {
+		return []ShotTally{{}}, nil // SQL's one row of COUNT(*) = 0
+	}
+
+	tallies := make([]ShotTally, len(keys))
+	for i, k := range keys {
+		tallies[i] = *byKey[k]
+	}
+	slices.SortFunc(tallies, func(a, b ShotTally) int {
+		for _, c := range groupBy {
+			va, _ := columnValue(&a, c)
+			vb, _ := columnValue(&b, c)
+			if n := compareValues(va, vb); n != 0 {
+				return n
+			}
+		}
+		return 0
+	})
+	return tallies, nil
+}
+
+// matchShot reports whether a shot passes f, as whereShots does in SQL.
+func matchShot(s *models.PlayerShotChart, f ShotFilter) bool {
+	margin := s.TeamScore - s.OpponentTeamScore
+	inPeriods := slices.Contains(f.Periods, s.Period) || f.Overtime && s.IsOvertime
+	switch {
+	case s.DeletedAt.Valid,
+		s.League != f.League,
+		f.PlayerIDs != nil && !slices.Contains(f.PlayerIDs, s.PlayerID),
+		f.Season != 0 && s.Season != f.Season,
+		f.FromSeason != 0 && s.Season < f.FromSeason,
+		f.ToSeason != 0 && s.Season > f.ToSeason,
+		len(f.Zones) > 0 && !slices.Contains(f.Zones, s.Zone),
+		(len(f.Periods) > 0 || f.Overtime) && !inPeriods,
+		f.Made != nil && s.Result != *f.Made,
+		f.ShotType != "" && s.ShotType != f.ShotType,
+		f.Team != "" && s.Team != f.Team,
+		f.Opponent != "" && s.Opponent != f.Opponent,
+		(f.Score != "" || f.MinMargin != nil || f.MaxMargin != nil) && !s.HasScore,
+		f.Score == ScoreLeading && margin <= 0,
+		f.Score == ScoreTrailing && margin >= 0,
+		f.Score == ScoreTied && margin != 0,
+		f.IsPlayoff != nil && s.IsPlayoff != *f.IsPlayoff,
+		f.DateFrom != nil && (s.GameDate == nil || s.GameDate.Before(*f.DateFrom)),
+		f.DateTo != nil && (s.GameDate == nil || s.GameDate.After(*f.DateTo)),
+		f.MinX != nil && s.CourtX < *f.MinX,
+		f.MaxX != nil && s.CourtX > *f.MaxX,
+		f.MinY != nil && s.CourtY < *f.MinY,
+		f.MaxY != nil && s.CourtY > *f.MaxY,
+		f.MinDistance != nil && s.DistanceFt < *f.MinDistance,
+		f.MaxDistance != nil && s.DistanceFt > *f.MaxDistance,
+		f.MinMargin != nil && margin < *f.MinMargin,
+		f.MaxMargin != nil && margin > *f.MaxMargin:
+		return false
+	}
+	return true
+}
+
+type memoryAPIKeys struct{ m *Memory }
+
+func (r memoryAPIKeys) List() ([]models.APIKey, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	return slices.Clone(r.m.Keys), nil
+}
+
+func (r memoryAPIKeys) Create(key *models.APIKey) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	key.ID = uint(len(r.m.Keys) + 1)
+	key.CreatedAt = time.Now()
+	r.m.Keys = append(r.m.Keys, *key)
+	return nil
+}
+
+func (r memoryAPIKeys) Revoke(id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	for i := range r.m.Keys {
+		if r.m.Keys[i].ID == id {
+			r.m.Keys[i].Revoked = true
+		}
+	}
+	return nil
+}
+
+func (r memoryAPIKeys) FindActive(hash []byte) (*models.APIKey, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	for _, k := range r.m.Keys {
+		if !k.Revoked && !k.RevokedAt.Valid && bytes.Equal(k.Hash, hash) {
+			return &k, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+type memoryPlayerIDs struct{ m *Memory }
+
+func (r memoryPlayerIDs) ResolvePlayerIDs(id string) ([]string, error) {
+	r.m.mu.RLock()
+	defer r.m.mu.RUnlock()
+	ids := append([]string{id}, r.m.IDMappings[id]...)
+	for br, others := range r.m.IDMappings {
+		if slices.Contains(others, id) && !slices.Contains(ids, br) {
+			ids = append(ids, br)
+		}
+	}
+	return ids, nil
+}
+
+// page slices out one page; a non-positive limit means no limit.
+func page[T any](rows []T, limit, offset int) []T {
+	if offset >= len(rows) {
+		return nil
+	}
+	rows = rows[max(offset, 0):]
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+var schemaCache sync.Map
+
+// columnValue reads the field stored in a database column from a model row.
+func columnValue[T any](row *T, column string) (interface{}, error) {
+	s, err := schema.Parse(row, &schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	field := s.LookUpField(column)
+	if field == nil {
+		return nil, fmt.Errorf("unknown column %q", column)
+	}
+	v, _ := field.ValueOf(context.Background(), reflect.ValueOf(row).Elem())
+	return v, nil
+}
+
+// sortByColumn sorts rows by a database column, then by primary key, in
+// the direction of s; an empty column sorts by primary key alone.
+func sortByColumn[T any](rows []T, s Sort) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	values := make([]interface{}, len(rows))
+	ids := make([]float64, len(rows))
+	for i := range rows {
+		if s.Column != "" {
+			v, err := sortValue(&rows[i], s)
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+		id, err := columnValue(&rows[i], "id")
+		if err != nil {
+			return err
+		}
+		ids[i] = toFloat(id)
+	}
+	idx := make([]int, len(rows))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		c := 0
+		if s.Column != "" {
+			c = compareValues(values[idx[a]], values[idx[b]])
+		}
+		if c == 0 {
+			c = compareValues(ids[idx[a]], ids[idx[b]])
+		}
+		if s.Ascending {
+			return c < 0
+		}
+		return c > 0
+	})
+	sorted := make([]T, len(rows))
+	for i, j := range idx {
+		sorted[i] = rows[j]
+	}
+	copy(rows, sorted)
+	return nil
+}
+
+// compareValues orders two column values of the same kind.
+func compareValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case string:
+		y, _ := b.(string)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case bool:
+		y, _ := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case time.Time:
+		y, _ := b.(time.Time)
+		return x.Compare(y)
+	}
+	x, y := toFloat(a), toFloat(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v interface{}) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return 0
+}
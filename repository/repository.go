@@ -0,0 +1,190 @@
+// Package repository is the data access layer behind the HTTP handlers.
+// Each aggregate has an interface with a GORM implementation for the real
+// database and an in-memory one for tests; caching or replica routing can
+// wrap either without touching the handlers.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+// ErrNotFound is returned when a lookup matches no row.
+var ErrNotFound = errors.New("not found")
+
+// StatsFilter selects season stat rows (totals or advanced). Zero values
+// mean "any".
+type StatsFilter struct {
+	League    string
+	Season    int
+	Team      string
+	PlayerIDs []string // already resolved across ID styles
+	IsPlayoff *bool
+	TeamMode  string // one of the TeamMode constants; see ParseTeamMode
+	// Conditions are extra clauses from a filter expression; see ParseFilter.
+	Conditions []Condition
+}
+
+// Sort orders a list by a database column. Rows with equal values are
+// ordered by primary key in the same direction, so the order is total.
+type Sort struct {
+	Column    string
+	Ascending bool
+	// Per, when set, sorts on the rate Column * Scale / Per instead, such
+	// as points per 36 minutes. Rows where Per is 0 sort as 0.
+	Per   string
+	Scale float64
+}
+
+// StatsQuery is one page of a season stats list; a zero Limit means all rows.
+type StatsQuery struct {
+	Filter StatsFilter
+	Sort   Sort
+	Limit  int
+	Offset int
+	// After starts the page after a keyset position; see DecodeCursor.
+	After *Cursor
+	// SkipCount skips counting the matches; List then returns a total of 0.
+	SkipCount bool
+	// Columns limits the columns read; nil reads them all. Implementations
+	// may return more columns than asked for.
+	Columns []string
+}
+
+// SeasonKey identifies one season stat row; totals and advanced rows for
+// the same player, season, team and season type share it.
+type SeasonKey struct {
+	League    string
+	PlayerID  string
+	Season    int
+	Team      string
+	IsPlayoff bool
+}
+
+// TotalStatsRepository reads PlayerTotalStat rows.
+type TotalStatsRepository interface {
+	// List returns one page of matching rows and the total match count.
+	List(q StatsQuery) ([]models.PlayerTotalStat, int64, error)
+	// ListByKeys returns the rows stored under any of keys.
+	ListByKeys(keys []SeasonKey) ([]models.PlayerTotalStat, error)
+}
+
+// AdvancedStatsRepository reads PlayerAdvancedStat rows.
+type AdvancedStatsRepository interface {
+	// List returns one page of matching rows and the total match count.
+	List(q StatsQuery) ([]models.PlayerAdvancedStat, int64, error)
+	// ListByKeys returns the rows stored under any of keys.
+	ListByKeys(keys []SeasonKey) ([]models.PlayerAdvancedStat, error)
+}
+
+// CareerQuery is one page of career rows; a zero Limit means all rows.
+type CareerQuery struct {
+	League     string
+	IsPlayoff  bool
+	PlayerIDs  []string // nil means every player
+	Conditions []Condition
+	Sort       Sort
+	Limit      int
+	Offset     int
+	// After starts the page after a keyset position; see DecodeCursor.
+	After *Cursor
+}
+
+// CareerStatsRepository reads PlayerCareerStat rows.
+type CareerStatsRepository interface {
+	List(q CareerQuery) ([]models.PlayerCareerStat, error)
+	// Count returns how many rows match q, ignoring its sort and page.
+	Count(q CareerQuery) (int64, error)
+	// SearchCandidates returns a league's rows, regular season and
+	// playoffs, whose names could match a folded search query; see
+	// services.RankPlayerSearch.
+	SearchCandidates(league, query string) ([]models.PlayerCareerStat, error)
+}
+
+// PercentileRepository reads PlayerStatPercentile rows.
+type PercentileRepository interface {
+	// ListByRecords returns the percentiles of a dataset's rows by row ID.
+	ListByRecords(dataset string, ids []uint) ([]models.PlayerStatPercentile, error)
+}
+
+// Score states for ShotFilter.Score, from the shooting team's side.
+const (
+	ScoreLeading  = "leading"
+	ScoreTrailing = "trailing"
+	ScoreTied     = "tied"
+)
+
+// ShotFilter selects shot chart rows. Zero values mean "any"; every range
+// is inclusive.
+type ShotFilter struct {
+	League    string
+	PlayerIDs []string
+	Season    int
+	// FromSeason and ToSeason bound a range of seasons.
+	FromSeason, ToSeason int
+	Zones                []string
+	MinX, MaxX           *float64
+	MinY, MaxY           *float64
+
+	DateFrom, DateTo *time.Time // game date
+	// Periods are quarters 1–4; Overtime adds every overtime period.
+	Periods  []int
+	Overtime bool
+	Made     *bool
+	ShotType string // "2-pointer" or "3-pointer"
+
+	MinDistance, MaxDistance *int // feet
+	Team, Opponent           string
+	Score                    string // one of the Score constants
+	MinMargin, MaxMargin     *int   // team score minus opponent score
+	IsPlayoff                *bool
+}
+
+// ShotQuery is one page of shots in primary key order; a zero Limit
+// means all rows.
+type ShotQuery struct {
+	Filter    ShotFilter
+	Limit     int
+	Offset    int
+	After     *Cursor
+	SkipCount bool
+	// Columns limits the columns read, as in StatsQuery.
+	Columns []string
+}
+
+// ShotChartRepository reads PlayerShotChart rows.
+type ShotChartRepository interface {
+	// List returns one page of matching shots and the total match count.
+	List(q ShotQuery) ([]models.PlayerShotChart, int64, error)
+	// Tally counts the matching shots grouped on groupBy, a subset of
+	// ShotTallyColumns, in that column order.
+	Tally(f ShotFilter, groupBy []string) ([]ShotTally, error)
+}
+
+// APIKeyRepository stores hashed API keys.
+type APIKeyRepository interface {
+	List() ([]models.APIKey, error)
+	Create(key *models.APIKey) error
+	Revoke(id uint) error
+	// FindActive returns the unrevoked key with this hash, or ErrNotFound.
+	FindActive(hash []byte) (*models.APIKey, error)
+}
+
+// PlayerIDResolver expands a Basketball-Reference or NBA.com player ID to
+// every ID the player's rows may be stored under, the given one first.
+type PlayerIDResolver interface {
+	ResolvePlayerIDs(id string) ([]string, error)
+}
+
+// Repositories bundles one implementation of each repository.
+type Repositories struct {
+	Totals      TotalStatsRepository
+	Advanced    AdvancedStatsRepository
+	Careers     CareerStatsRepository
+	Percentiles PercentileRepository
+	Shots       ShotChartRepository
+	APIKeys     APIKeyRepository
+	PlayerIDs   PlayerIDResolver
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+var seedTotals = []models.PlayerTotalStat{
+	{League: models.LeagueNBA, PlayerID: "hardeja01", Team: "TOT", Season: 2021, Points: 1800, Games: 60, MinutesPG: 2100, IsAggregate: true},
+	{League: models.LeagueNBA, PlayerID: "hardeja01", Team: "HOU", Season: 2021, Points: 200, Games: 8, MinutesPG: 280},
+	{League: models.LeagueNBA, PlayerID: "hardeja01", Team: "BRK", Season: 2021, Points: 1600, Games: 52, MinutesPG: 1820},
+	{League: models.LeagueNBA, PlayerID: "curryst01", Team: "GSW", Season: 2021, Points: 2000, Games: 63, MinutesPG: 2150},
+	{League: models.LeagueNBA, PlayerID: "curryst01", Team: "GSW", Season: 2021, Points: 300, IsPlayoff: true},
+	{League: models.LeagueNBA, PlayerID: "201939", Team: "GSW", Season: 2022, Points: 1500, Games: 50, MinutesPG: 1700},
+	{League: models.LeagueWNBA, PlayerID: "wilsoa01w", Team: "LVA", Season: 2021, Points: 500, Games: 20, MinutesPG: 600},
+}
+
+func shotOn(date string, qtr int, made bool, shotType string, dist, team, opp int, playoff bool) models.PlayerShotChart {
+	d, _ := time.Parse(time.DateOnly, date)
+	return models.PlayerShotChart{
+		League: models.LeagueNBA, PlayerID: "curryst01", Team: "GSW", Opponent: "LAL", Season: 2024,
+		Date: date, Quarter: strconv.Itoa(qtr), GameDate: &d, Period: qtr, IsOvertime: qtr > 4,
+		Result: made, ShotType: shotType, DistanceFt: dist, TeamScore: team, OpponentTeamScore: opp,
+		HasScore: true, IsPlayoff: playoff,
+	}
+}
+
+var seedShots = []models.PlayerShotChart{
+	shotOn("2024-01-10", 1, true, "3-pointer", 26, 10, 8, false),
+	shotOn("2024-01-10", 4, false, "2-pointer", 3, 90, 95, false),
+	shotOn("2024-02-02", 5, true, "2-pointer", 12, 110, 110, false),
+	shotOn("2024-04-25", 2, false, "3-pointer", 28, 40, 52, true),
+	func() models.PlayerShotChart { // NBA.com: no running score
+		s := shotOn("2024-03-01", 3, false, "2-pointer", 5, 0, 0, false)
+		s.GameID, s.HasScore = "0022300871", false
+		return s
+	}(),
+}
+
+var seedCareers = []models.PlayerCareerStat{
+	{League: "NBA", PlayerID: "curryst01", Games: 956, ThreeFG: 3747, ThreePercent: 0.426, Points: 23668},
+	{League: "NBA", PlayerID: "jamesle01", Games: 1492, ThreeFG: 2410, ThreePercent: 0.349, Points: 41563},
+	{League: "NBA", PlayerID: "korveky01", Games: 1232, ThreeFG: 2450, ThreePercent: 0.429, Points: 10075},
+	{League: "NBA", PlayerID: "curryst01", IsPlayoff: true, Games: 156, ThreeFG: 618, ThreePercent: 0.397, Points: 4236},
+}
+
+var seedPercentiles = []models.PlayerStatPercentile{
+	{Dataset: "totals", RecordID: 1, League: "NBA", Season: 2021, Percentiles: map[string]float64{"points": 90}},
+	{Dataset: "totals", RecordID: 2, League: "NBA", Season: 2021, Percentiles: map[string]float64{"points": 10}},
+	{Dataset: "advanced", RecordID: 1, League: "NBA", Season: 2021, Percentiles: map[string]float64{"per": 50}},
+}
+
+// implementations returns the GORM and in-memory repositories over the
+// same seed data, so every case runs against both.
+func implementations(t *testing.T) map[string]Repositories {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerShotChart{}, &models.PlayerCareerStat{}, &models.PlayerStatPercentile{}, &models.APIKey{}, &models.PlayerIDMapping{}))
+	totals := append([]models.PlayerTotalStat{}, seedTotals...)
+	require.NoError(t, db.Create(&totals).Error)
+	shots := append([]models.PlayerShotChart{}, seedShots...)
+	require.NoError(t, db.Create(&shots).Error)
+	careers := append([]models.PlayerCareerStat{}, seedCareers...)
+	require.NoError(t, db.Create(&careers).Error)
+	percentiles := append([]models.PlayerStatPercentile{}, seedPercentiles...)
+	require.NoError(t, db.Create(&percentiles).Error)
+	require.NoError(t, db.Create(&models.PlayerIDMapping{
+		BRID: "curryst01", NBAID: "201939", Source: models.MappingSourceCSV,
+		Confidence: 1, Status: models.MappingConfirmed,
+	}).Error)
+
+	mem := NewMemory()
+	mem.Totals = append(mem.Totals, totals...) // with the IDs the database assigned
+	mem.Shots = append(mem.Shots, shots...)
+	mem.Careers = append(mem.Careers, careers...)
+	mem.Percentiles = append(mem.Percentiles, percentiles...)
+	mem.IDMappings["curryst01"] = []string{"201939"}
+
+	return map[string]Repositories{"gorm": NewGorm(db), "memory": mem.Repositories()}
+}
+
+func TestTotalStatsList(t *testing.T) {
+	playoffs := true
+	cases := []struct {
+		name      string
+		query     StatsQuery
+		wantTotal int64
+		wantFirst int // points of the first row
+	}{
+		{"combined", StatsQuery{Filter: StatsFilter{League: "NBA", Season: 2021, TeamMode: TeamModeCombined}, Sort: Sort{Column: "points", Ascending: false}}, 3, 2000},
+		{"split", StatsQuery{Filter: StatsFilter{League: "NBA", Season: 2021, TeamMode: TeamModeSplit}, Sort: Sort{Column: "points", Ascending: true}}, 4, 200},
+		{"both", StatsQuery{Filter: StatsFilter{League: "NBA", Season: 2021, TeamMode: TeamModeBoth}, Sort: Sort{Column: "points", Ascending: false}}, 5, 2000},
+		{"playoffs", StatsQuery{Filter: StatsFilter{League: "NBA", IsPlayoff: &playoffs}, Sort: Sort{Column: "points", Ascending: false}}, 1, 300},
+		{"team", StatsQuery{Filter: StatsFilter{League: "NBA", Team: "GSW", TeamMode: TeamModeSplit}, Sort: Sort{Column: "season", Ascending: false}}, 3, 1500},
+		{"players", StatsQuery{Filter: StatsFilter{League: "NBA", PlayerIDs: []string{"curryst01", "201939"}}, Sort: Sort{Column: "points", Ascending: false}}, 3, 2000},
+		{"league", StatsQuery{Filter: StatsFilter{League: "WNBA"}, Sort: Sort{Column: "points", Ascending: false}}, 1, 500},
+		{"paged", StatsQuery{Filter: StatsFilter{League: "NBA", TeamMode: TeamModeBoth}, Sort: Sort{Column: "points", Ascending: false}, Limit: 2, Offset: 2}, 6, 1600},
+	}
+
+	for name, repos := range implementations(t) {
+		for _, tc := range cases {
+			rows, total, err := repos.Totals.List(tc.query)
+			require.NoError(t, err, name+"/"+tc.name)
+			assert.Equal(t, tc.wantTotal, total, name+"/"+tc.name)
+			if assert.NotEmpty(t, rows, name+"/"+tc.name) {
+				assert.Equal(t, tc.wantFirst, rows[0].Points, name+"/"+tc.name)
+			}
+		}
+	}
+}
+
+func TestCareerStatsList(t *testing.T) {
+	for name, repos := range implementations(t) {
+		rows, err := repos.Careers.List(CareerQuery{League: "NBA", Sort: Sort{Column: "points", Ascending: false}})
+		require.NoError(t, err, name)
+		require.Len(t, rows, 3, name)
+		assert.Equal(t, "jamesle01", rows[0].PlayerID, name)
+
+		rows, err = repos.Careers.List(CareerQuery{
+			League:     "NBA",
+			Conditions: []Condition{{Column: "games", Op: OpLt, Values: []interface{}{1300}}},
+			Sort:       Sort{Column: "three_percent", Ascending: false},
+			Limit:      1,
+		})
+		require.NoError(t, err, name)
+		require.Len(t, rows, 1, name)
+		assert.Equal(t, "korveky01", rows[0].PlayerID, name)
+
+		total, err := repos.Careers.Count(CareerQuery{
+			League:     "NBA",
+			Conditions: []Condition{{Column: "games", Op: OpLt, Values: []interface{}{1300}}},
+			Limit:      1,
+		})
+		require.NoError(t, err, name)
+		assert.EqualValues(t, 2, total, name)
+
+		// A keyset page starts after the cursor row.
+		sort := Sort{Column: "points", Ascending: false}
+		rows, err = repos.Careers.List(CareerQuery{League: "NBA", Sort: sort, Limit: 1})
+		require.NoError(t, err, name)
+		next, err := NextCursor(&rows[0], sort)
+		require.NoError(t, err, name)
+		after, err := DecodeCursor(next, sort)
+		require.NoError(t, err, name)
+		rows, err = repos.Careers.List(CareerQuery{League: "NBA", Sort: sort, After: after})
+		require.NoError(t, err, name)
+		assert.Len(t, rows, 2, name)
+		assert.NotContains(t, []string{rows[0].PlayerID, rows[1].PlayerID}, "jamesle01", name)
+
+		rows, err = repos.Careers.List(CareerQuery{League: "NBA", IsPlayoff: true, PlayerIDs: []string{"curryst01"}})
+		require.NoError(t, err, name)
+		if assert.Len(t, rows, 1, name) {
+			assert.Equal(t, 156, rows[0].Games, name)
+		}
+	}
+}
+
+func TestPercentilesByRecords(t *testing.T) {
+	for name, repos := range implementations(t) {
+		rows, err := repos.Percentiles.ListByRecords("totals", []uint{1, 3})
+		require.NoError(t, err, name)
+		if assert.Len(t, rows, 1, name) {
+			assert.Equal(t, 90.0, rows[0].Percentiles["points"], name)
+		}
+		rows, err = repos.Percentiles.ListByRecords("advanced", nil)
+		require.NoError(t, err, name)
+		assert.Empty(t, rows, name)
+	}
+}
+
+func TestAPIKeysAndPlayerIDs(t *testing.T) {
+	for name, repos := range implementations(t) {
+		live := models.APIKey{Hash: []byte("live"), Label: "app"}
+		require.NoError(t, repos.APIKeys.Create(&live), name)
+		dead := models.APIKey{Hash: []byte("dead")}
+		require.NoError(t, repos.APIKeys.Create(&dead), name)
+		require.NoError(t, repos.APIKeys.Revoke(dead.ID), name)
+
+		got, err := repos.APIKeys.FindActive([]byte("live"))
+		require.NoError(t, err, name)
+		assert.Equal(t, live.ID, got.ID, name)
+		_, err = repos.APIKeys.FindActive([]byte("dead"))
+		assert.ErrorIs(t, err, ErrNotFound, name)
+
+		keys, err := repos.APIKeys.List()
+		require.NoError(t, err, name)
+		assert.Len(t, keys, 2, name)
+
+		ids, err := repos.PlayerIDs.ResolvePlayerIDs("201939")
+		require.NoError(t, err, name)
+		assert.Equal(t, []string{"201939", "curryst01"}, ids, name)
+	}
+}
+
+func TestParseTeamMode(t *testing.T) {
+	mode, err := ParseTeamMode("", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, TeamModeCombined, mode)
+
+	mode, err = ParseTeamMode(TeamModeBoth, "BRK", nil)
+	require.NoError(t, err)
+	assert.Equal(t, TeamModeSplit, mode)
+
+	mode, err = ParseTeamMode("", "", []Condition{{Column: "team", Op: OpIn, Values: []interface{}{"BRK"}}})
+	require.NoError(t, err)
+	assert.Equal(t, TeamModeSplit, mode)
+
+	_, err = ParseTeamMode("bogus", "", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import "github.com/nprasad2077/NBA_Go/models"
+
+// ShotTallyColumns are the shot columns a ShotTally can be grouped on.
+var ShotTallyColumns = []string{"season", "is_playoff", "top", "left", "distance_ft", "shot_type"}
+
+// ShotTally is the shooting of one group of shots, as the shot chart
+// repository counts it. Only the columns grouped on are set.
+type ShotTally struct {
+	Season     int
+	IsPlayoff  bool
+	Top        int
+	Left       int
+	DistanceFt int
+	ShotType   string
+	Attempts   int
+	Makes      int
+	Points     int
+}
+
+// TallyShot is the tally of the single shot s, keyed on every column.
+func TallyShot(s *models.PlayerShotChart) ShotTally {
+	t := ShotTally{
+		Season: s.Season, IsPlayoff: s.IsPlayoff, Top: s.Top, Left: s.Left,
+		DistanceFt: s.DistanceFt, ShotType: s.ShotType, Attempts: 1,
+	}
+	if s.Result {
+		t.Makes, t.Points = 1, shotValue(s.ShotType)
+	}
+	return t
+}
+
+// shotValue is the points a made shot of this type is worth.
+func shotValue(shotType string) int {
+	if shotType == "3-pointer" {
+		return 3
+	}
+	return 2
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShotChartList(t *testing.T) {
+	yes, no := true, false
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	jan10 := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	ten, twenty, zero, minus5 := 10, 20, 0, -5
+	cases := []struct {
+		name   string
+		filter ShotFilter
+		want   int
+	}{
+		{"all", ShotFilter{}, 5},
+		{"date from", ShotFilter{DateFrom: &feb}, 3},
+		{"single day", ShotFilter{DateFrom: &jan10, DateTo: &jan10}, 2},
+		{"fourth quarter", ShotFilter{Periods: []int{4}}, 1},
+		{"clutch", ShotFilter{Periods: []int{4}, Overtime: true}, 2},
+		{"overtime", ShotFilter{Overtime: true}, 1},
+		{"made", ShotFilter{Made: &yes}, 2},
+		{"threes", ShotFilter{ShotType: "3-pointer"}, 2},
+		{"distance", ShotFilter{MinDistance: &ten, MaxDistance: &twenty}, 1},
+		{"opponent", ShotFilter{Opponent: "LAL"}, 5},
+		{"team", ShotFilter{Team: "BOS"}, 0},
+		{"leading", ShotFilter{Score: ScoreLeading}, 1},
+		{"trailing", ShotFilter{Score: ScoreTrailing}, 2},
+		{"tied", ShotFilter{Score: ScoreTied}, 1}, // not the unscored NBA.com shot
+		{"margin", ShotFilter{MinMargin: &minus5, MaxMargin: &zero}, 2},
+		{"playoffs", ShotFilter{IsPlayoff: &yes}, 1},
+		{"regular season", ShotFilter{IsPlayoff: &no}, 4},
+	}
+
+	for name, repos := range implementations(t) {
+		for _, tc := range cases {
+			tc.filter.League = "NBA"
+			shots, total, err := repos.Shots.List(ShotQuery{Filter: tc.filter})
+			require.NoError(t, err, name+"/"+tc.name)
+			assert.Len(t, shots, tc.want, name+"/"+tc.name)
+			assert.EqualValues(t, tc.want, total, name+"/"+tc.name)
+		}
+
+		// Offset pages in primary key order.
+		shots, total, err := repos.Shots.List(ShotQuery{Filter: ShotFilter{League: "NBA"}, Limit: 2, Offset: 2})
+		require.NoError(t, err, name)
+		assert.EqualValues(t, 5, total, name)
+		if assert.Len(t, shots, 2, name) {
+			assert.Equal(t, 12, shots[0].DistanceFt, name)
+		}
+	}
+}
+
+func TestShotChartTally(t *testing.T) {
+	yes := true
+	for name, repos := range implementations(t) {
+		tallies, err := repos.Shots.Tally(ShotFilter{League: "NBA"}, []string{"is_playoff", "shot_type"})
+		require.NoError(t, err, name)
+		assert.Equal(t, []ShotTally{
+			{ShotType: "2-pointer", Attempts: 3, Makes: 1, Points: 2},
+			{ShotType: "3-pointer", Attempts: 1, Makes: 1, Points: 3},
+			{IsPlayoff: true, ShotType: "3-pointer", Attempts: 1},
+		}, tallies, name)
+
+		tallies, err = repos.Shots.Tally(ShotFilter{League: "NBA", Made: &yes}, nil)
+		require.NoError(t, err, name)
+		assert.Equal(t, []ShotTally{{Attempts: 2, Makes: 2, Points: 5}}, tallies, name)
+
+		tallies, err = repos.Shots.Tally(ShotFilter{League: "WNBA"}, nil)
+		require.NoError(t, err, name)
+		assert.Equal(t, []ShotTally{{}}, tallies, name)
+
+		_, err = repos.Shots.Tally(ShotFilter{League: "NBA"}, []string{"player_name"})
+		assert.Error(t, err, name)
+	}
+}
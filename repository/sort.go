@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// sortExpr is the SQL expression s orders table by. Rates are computed in
+// double precision so they match sortValue exactly, which keyset cursors
+// rely on.
+func sortExpr(table string, s Sort) string {
+	col := table + "." + s.Column
+	if s.Per == "" {
+		return col
+	}
+	return fmt.Sprintf("COALESCE(CAST(%s AS DOUBLE PRECISION) * %s / NULLIF(%s.%s, 0), 0)",
+		col, strconv.FormatFloat(s.Scale, 'g', -1, 64), table, s.Per)
+}
+
+// sortValue is a row's value under s, as sortExpr computes it.
+func sortValue[T any](row *T, s Sort) (interface{}, error) {
+	v, err := columnValue(row, s.Column)
+	if err != nil || s.Per == "" {
+		return v, err
+	}
+	per, err := columnValue(row, s.Per)
+	if err != nil {
+		return nil, err
+	}
+	if d := toFloat(per); d != 0 {
+		return toFloat(v) * s.Scale / d, nil
+	}
+	return 0.0, nil
+}
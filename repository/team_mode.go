@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Team modes for endpoints that return per-team season rows.
+const (
+	// TeamModeCombined returns one row per player-season: the aggregate row
+	// for traded players, the single team row for everyone else.
+	TeamModeCombined = "combined"
+	// TeamModeSplit returns only per-team rows, never aggregates.
+	TeamModeSplit = "split"
+	// TeamModeBoth returns aggregate and per-team rows together.
+	TeamModeBoth = "both"
+)
+
+// ParseTeamMode validates a teamMode option; "" means combined. A team
+// filter, from team= or a team condition in a filter expression, always
+// implies split, since aggregate rows belong to no team.
+func ParseTeamMode(mode, team string, conds []Condition) (string, error) {
+	if team != "" || slices.ContainsFunc(conds, func(c Condition) bool { return c.Column == "team" }) {
+		return TeamModeSplit, nil
+	}
+	switch mode {
+	case "":
+		return TeamModeCombined, nil
+	case TeamModeCombined, TeamModeSplit, TeamModeBoth:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid teamMode %q (want combined, split or both)", mode)
+}
+
+// applyTeamMode restricts query (over table) to the rows selected by mode.
+func applyTeamMode(query *gorm.DB, table, mode string) *gorm.DB {
+	switch mode {
+	case TeamModeSplit:
+		return query.Where(table+".is_aggregate = ?", false)
+	case TeamModeBoth:
+		return query
+	default:
+		// Keep aggregates, and team rows only when no aggregate exists for
+		// the same player-season.
+		return query.Where(fmt.Sprintf(
+			"(%[1]s.is_aggregate = ? OR NOT EXISTS ("+
+				"SELECT 1 FROM %[1]s agg WHERE agg.league = %[1]s.league AND agg.player_id = %[1]s.player_id "+
+				"AND agg.season = %[1]s.season AND agg.is_playoff = %[1]s.is_playoff "+
+				"AND agg.is_aggregate = ? AND agg.deleted_at IS NULL))",
+			table,
+		), true, true)
+	}
+}
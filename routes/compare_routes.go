@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/controllers"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// RegisterCompareRoutes sets up the player comparison endpoint.
+func RegisterCompareRoutes(app *fiber.App, repos repository.Repositories) {
+	app.Get("/api/compare", controllers.ComparePlayers(repos.Totals, repos.Advanced, repos.Careers, repos.PlayerIDs))
+}
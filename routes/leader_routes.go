@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/controllers"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// RegisterLeaderRoutes sets up the stat leaderboards.
+func RegisterLeaderRoutes(app *fiber.App, repos repository.Repositories) {
+	api := app.Group("/api/leaders")
+
+	api.Get("/:stat", controllers.GetStatLeaders(repos.Totals, repos.Advanced, repos.Careers))
+}
@@ -4,12 +4,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 	"github.com/nprasad2077/NBA_Go/controllers"
+	"github.com/nprasad2077/NBA_Go/repository"
 )
 
-func RegisterPlayerAdvancedRoutes(app *fiber.App, db *gorm.DB) {
+func RegisterPlayerAdvancedRoutes(app *fiber.App, db *gorm.DB, repos repository.Repositories) {
 	api := app.Group("/api/playeradvancedstats")
 
 	// api.Get("/fetch", controllers.FetchPlayerAdvancedStats(db))
 	api.Get("/scrape", controllers.ScrapePlayerAdvancedStats(db))
-	api.Get("/", controllers.GetAllAdvancedPlayerStats(db))
+	api.Get("/", controllers.GetAllAdvancedPlayerStats(repos.Advanced, repos.Totals, repos.Percentiles, repos.PlayerIDs))
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nprasad2077/NBA_Go/controllers"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// RegisterPlayerRoutes sets up the per-player endpoints.
+func RegisterPlayerRoutes(app *fiber.App, repos repository.Repositories) {
+	api := app.Group("/api/players")
+
+	api.Get("/search", controllers.SearchPlayers(repos.Careers))
+	api.Get("/career", controllers.GetCareerLeaders(repos.Careers))
+	api.Get("/:id/career", controllers.GetPlayerCareer(repos.Careers, repos.PlayerIDs))
+	api.Get("/:id/profile", controllers.GetPlayerProfile(repos.Totals, repos.Advanced, repos.Shots, repos.PlayerIDs))
+	api.Get("/:id/similar", controllers.GetSimilarPlayers(repos.Advanced, repos.PlayerIDs))
+}
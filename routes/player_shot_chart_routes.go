@@ -3,13 +3,17 @@ package routes
 import (
     "github.com/gofiber/fiber/v2"
     "github.com/nprasad2077/NBA_Go/controllers"
+    "github.com/nprasad2077/NBA_Go/repository"
     "gorm.io/gorm"
 )
 
-// RegisterPlayerShotChartRoutes sets up the shot-chart endpoints
-func RegisterPlayerShotChartRoutes(app *fiber.App, db *gorm.DB) {
+// RegisterPlayerShotChartRoutes sets up the shot-chart endpoints; the
+// scrape and import status endpoints write through db directly.
+func RegisterPlayerShotChartRoutes(app *fiber.App, db *gorm.DB, repos repository.Repositories) {
     api := app.Group("/api/playershotchart")
     // api.Get("/fetch",  controllers.FetchPlayerShotChartAPI(db))
     api.Get("/scrape", controllers.ScrapePlayerShotChart(db))
-    api.Get("/",        controllers.GetPlayerShotChart(db))
+    api.Get("/import/status", controllers.GetShotChartImportStatus(db))
+    api.Get("/aggregate", controllers.GetShotChartAggregate(repos.Shots, repos.PlayerIDs))
+    api.Get("/",        controllers.GetPlayerShotChart(repos.Shots, repos.PlayerIDs))
 }
\ No newline at end of file
@@ -3,13 +3,14 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/nprasad2077/NBA_Go/controllers"
+	"github.com/nprasad2077/NBA_Go/repository"
 	"gorm.io/gorm"
 )
 
-func RegisterPlayerTotalRoutes(app *fiber.App, db *gorm.DB) {
+func RegisterPlayerTotalRoutes(app *fiber.App, db *gorm.DB, repos repository.Repositories) {
 	api := app.Group("/api/playertotals")
 
 	// api.Get("/fetch", controllers.FetchPlayerTotalStats(db))
 	api.Get("/scrape", controllers.ScrapePlayerTotalStats(db))
-	api.Get("/", controllers.GetPlayerTotalStats(db))
+	api.Get("/", controllers.GetPlayerTotalStats(repos.Totals, repos.Advanced, repos.Percentiles, repos.PlayerIDs))
 }
\ No newline at end of file
@@ -0,0 +1,140 @@
+// File: services/career_stats.go
+package services
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"gorm.io/gorm"
+)
+
+// careerKey identifies one PlayerCareerStat row within a league.
+type careerKey struct {
+	playerID  string
+	isPlayoff bool
+}
+
+// RebuildCareerStats recomputes PlayerCareerStat for a league from the
+// stored season totals. With playerIDs it only rebuilds those players;
+// without, the whole league.
+func RebuildCareerStats(db *gorm.DB, league string, playerIDs ...string) error {
+	query := db.Model(&models.PlayerTotalStat{}).Where("league = ?", league)
+	if len(playerIDs) > 0 {
+		query = query.Where("player_id IN ?", playerIDs)
+	}
+	var rows []models.PlayerTotalStat
+	if err := query.Order("season, id").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	careers := buildCareerStats(league, rows)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		del := tx.Where("league = ?", league)
+		if len(playerIDs) > 0 {
+			del = del.Where("player_id IN ?", playerIDs)
+		}
+		if err := del.Delete(&models.PlayerCareerStat{}).Error; err != nil {
+			return err
+		}
+		if len(careers) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(careers, 500).Error; err != nil {
+			return err
+		}
+		log.Printf("Rebuilt %d %s career rows", len(careers), league)
+		return nil
+	})
+}
+
+// RebuildAllCareerStats rebuilds every league's career table.
+func RebuildAllCareerStats(db *gorm.DB) error {
+	for _, league := range models.Leagues {
+		if err := RebuildCareerStats(db, league); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildCareerStats sums season rows (ordered by season) into career rows.
+// A traded season counts once through its aggregate row; the per-team rows
+// only contribute the teams played for.
+func buildCareerStats(league string, rows []models.PlayerTotalStat) []models.PlayerCareerStat {
+	type seasonKey struct {
+		careerKey
+		season int
+	}
+	hasAggregate := map[seasonKey]bool{}
+	for _, r := range rows {
+		if r.IsAggregate {
+			hasAggregate[seasonKey{careerKey{r.PlayerID, r.IsPlayoff}, r.Season}] = true
+		}
+	}
+
+	var order []careerKey
+	careers := map[careerKey]*models.PlayerCareerStat{}
+	teams := map[careerKey][]string{}
+	for _, r := range rows {
+		key := careerKey{r.PlayerID, r.IsPlayoff}
+		c, ok := careers[key]
+		if !ok {
+			c = &models.PlayerCareerStat{League: league, PlayerID: r.PlayerID, IsPlayoff: r.IsPlayoff, FirstSeason: r.Season}
+			careers[key] = c
+			order = append(order, key)
+		}
+		if !r.IsAggregate && !slices.Contains(teams[key], r.Team) {
+			teams[key] = append(teams[key], r.Team)
+		}
+		if !r.IsAggregate && hasAggregate[seasonKey{key, r.Season}] {
+			continue
+		}
+
+		if r.Season != c.LastSeason {
+			c.Seasons++
+			c.LastSeason = r.Season
+		}
+		if r.PlayerName != "" {
+			c.PlayerName = r.PlayerName
+		}
+		c.Games += r.Games
+		c.GamesStarted += r.GamesStarted
+		c.Minutes += r.MinutesPG // season total minutes on the totals tables
+		c.FieldGoals += r.FieldGoals
+		c.FieldAttempts += r.FieldAttempts
+		c.ThreeFG += r.ThreeFG
+		c.ThreeAttempts += r.ThreeAttempts
+		c.TwoFG += r.TwoFG
+		c.TwoAttempts += r.TwoAttempts
+		c.FT += r.FT
+		c.FTAttempts += r.FTAttempts
+		c.OffensiveRB += r.OffensiveRB
+		c.DefensiveRB += r.DefensiveRB
+		c.TotalRB += r.TotalRB
+		c.Assists += r.Assists
+		c.Steals += r.Steals
+		c.Blocks += r.Blocks
+		c.Turnovers += r.Turnovers
+		c.PersonalFouls += r.PersonalFouls
+		c.Points += r.Points
+	}
+
+	out := make([]models.PlayerCareerStat, 0, len(order))
+	for _, key := range order {
+		c := careers[key]
+		c.Teams = strings.Join(teams[key], ",")
+		c.SearchName = FoldName(c.PlayerName)
+		c.FieldPercent = ratio(c.FieldGoals, c.FieldAttempts)
+		c.ThreePercent = ratio(c.ThreeFG, c.ThreeAttempts)
+		c.TwoPercent = ratio(c.TwoFG, c.TwoAttempts)
+		c.FTPercent = ratio(c.FT, c.FTAttempts)
+		if c.FieldAttempts > 0 {
+			c.EffectFGPercent = round3((float64(c.FieldGoals) + 0.5*float64(c.ThreeFG)) / float64(c.FieldAttempts))
+		}
+		out = append(out, *c)
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestRebuildCareerStats(t *testing.T) {
+	db := newQualityTestDB(t)
+	require.NoError(t, db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "hardeja01", PlayerName: "James Harden", Team: "HOU", Season: 2020, Games: 68, Points: 2335, FieldGoals: 672, FieldAttempts: 1514, ThreeFG: 299, ThreeAttempts: 843, FT: 692, FTAttempts: 800},
+		// Traded in 2021: the TOT row counts, the team rows only add teams.
+		{PlayerID: "hardeja01", PlayerName: "James Harden", Team: "TOT", Season: 2021, IsAggregate: true, Games: 44, Points: 1090, FieldGoals: 355, FieldAttempts: 763, ThreeFG: 126, ThreeAttempts: 348, FT: 254, FTAttempts: 294},
+		{PlayerID: "hardeja01", PlayerName: "James Harden", Team: "HOU", Season: 2021, Games: 8, Points: 198, FieldGoals: 63, FieldAttempts: 142},
+		{PlayerID: "hardeja01", PlayerName: "James Harden", Team: "BRK", Season: 2021, Games: 36, Points: 892, FieldGoals: 292, FieldAttempts: 621},
+		{PlayerID: "hardeja01", PlayerName: "James Harden", Team: "BRK", Season: 2021, IsPlayoff: true, Games: 9, Points: 184},
+		{PlayerID: "curryst01", PlayerName: "Stephen Curry", Team: "GSW", Season: 2021, Games: 63, Points: 2015},
+		{League: models.LeagueWNBA, PlayerID: "hardeja01", Team: "LVA", Season: 2021, Games: 1, Points: 1},
+	}).Error)
+
+	require.NoError(t, RebuildCareerStats(db, models.LeagueNBA))
+
+	var rows []models.PlayerCareerStat
+	require.NoError(t, db.Order("player_id, is_playoff").Find(&rows).Error)
+	require.Len(t, rows, 3)
+
+	curry, harden, hardenPO := rows[0], rows[1], rows[2]
+	assert.Equal(t, 2015, curry.Points)
+
+	assert.Equal(t, models.LeagueNBA, harden.League)
+	assert.False(t, harden.IsPlayoff)
+	assert.Equal(t, 2, harden.Seasons)
+	assert.Equal(t, 2020, harden.FirstSeason)
+	assert.Equal(t, 2021, harden.LastSeason)
+	assert.Equal(t, "HOU,BRK", harden.Teams)
+	assert.Equal(t, 112, harden.Games)
+	assert.Equal(t, 2335+1090, harden.Points)
+	assert.InDelta(t, 0.451, harden.FieldPercent, 1e-9) // 1027/2277
+	assert.InDelta(t, 0.357, harden.ThreePercent, 1e-9) // 425/1191
+	assert.InDelta(t, 0.865, harden.FTPercent, 1e-9)    // 946/1094
+	assert.InDelta(t, 0.544, harden.EffectFGPercent, 1e-9)
+
+	assert.True(t, hardenPO.IsPlayoff)
+	assert.Equal(t, "BRK", hardenPO.Teams)
+	assert.Zero(t, hardenPO.FieldPercent)
+
+	// Rebuilding one player replaces only that player's rows.
+	require.NoError(t, db.Where("player_id = ? AND season = ?", "curryst01", 2021).
+		Delete(&models.PlayerTotalStat{}).Error)
+	require.NoError(t, RebuildCareerStats(db, models.LeagueNBA, "hardeja01"))
+	var count int64
+	db.Model(&models.PlayerCareerStat{}).Where("player_id = ?", "curryst01").Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, RebuildCareerStats(db, models.LeagueNBA, "curryst01"))
+	db.Model(&models.PlayerCareerStat{}).Where("player_id = ?", "curryst01").Count(&count)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,408 @@
+// File: services/data_quality.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"gorm.io/gorm"
+)
+
+// Datasets the data quality engine knows how to validate.
+const (
+	DatasetTotals   = "totals"
+	DatasetAdvanced = "advanced"
+	DatasetShots    = "shots"
+)
+
+// ErrQualityThreshold is returned by a strict import whose season has more
+// violations than DQ_MAX_VIOLATIONS; nothing from that import is committed.
+var ErrQualityThreshold = errors.New("data quality threshold exceeded")
+
+// qualityRule is one integrity check. Check returns "" when the row passes,
+// otherwise a human-readable description of the problem.
+type qualityRule[T any] struct {
+	Name        string
+	Description string
+	Check       func(*T) string
+}
+
+// DataQualityRuleInfo describes a built-in rule for the report endpoint.
+type DataQualityRuleInfo struct {
+	Dataset     string `json:"dataset"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// DataQualityReport is the stored violation summary for a season.
+type DataQualityReport struct {
+	League     string                        `json:"league"`
+	Season     int                           `json:"season"`
+	Total      int64                         `json:"total"`
+	ByRule     map[string]int64              `json:"byRule"`
+	Rules      []DataQualityRuleInfo         `json:"rules"`
+	Violations []models.DataQualityViolation `json:"violations"`
+}
+
+// ─────────────────────────────  built-in rules  ─────────────────────────────
+
+var totalsRules = []qualityRule[models.PlayerTotalStat]{
+	{"fg_sum", "FieldGoals = TwoFG + ThreeFG", func(s *models.PlayerTotalStat) string {
+		return mismatch("FG", s.FieldGoals, "2P+3P", s.TwoFG+s.ThreeFG)
+	}},
+	{"fga_sum", "FieldAttempts = TwoAttempts + ThreeAttempts", func(s *models.PlayerTotalStat) string {
+		return mismatch("FGA", s.FieldAttempts, "2PA+3PA", s.TwoAttempts+s.ThreeAttempts)
+	}},
+	{"trb_sum", "TotalRB = OffensiveRB + DefensiveRB (when the split is recorded)", func(s *models.PlayerTotalStat) string {
+		if s.OffensiveRB == 0 && s.DefensiveRB == 0 {
+			return ""
+		}
+		return mismatch("TRB", s.TotalRB, "ORB+DRB", s.OffensiveRB+s.DefensiveRB)
+	}},
+	{"points_sum", "Points = 2*TwoFG + 3*ThreeFG + FT", func(s *models.PlayerTotalStat) string {
+		return mismatch("PTS", s.Points, "2*2P+3*3P+FT", 2*s.TwoFG+3*s.ThreeFG+s.FT)
+	}},
+	{"makes_le_attempts", "Makes never exceed attempts", func(s *models.PlayerTotalStat) string {
+		switch {
+		case s.FieldGoals > s.FieldAttempts:
+			return fmt.Sprintf("FG %d > FGA %d", s.FieldGoals, s.FieldAttempts)
+		case s.ThreeFG > s.ThreeAttempts:
+			return fmt.Sprintf("3P %d > 3PA %d", s.ThreeFG, s.ThreeAttempts)
+		case s.TwoFG > s.TwoAttempts:
+			return fmt.Sprintf("2P %d > 2PA %d", s.TwoFG, s.TwoAttempts)
+		case s.FT > s.FTAttempts:
+			return fmt.Sprintf("FT %d > FTA %d", s.FT, s.FTAttempts)
+		}
+		return ""
+	}},
+	{"pct_range", "Shooting percentages are within [0, 1] (eFG% up to 1.5)", func(s *models.PlayerTotalStat) string {
+		return firstOutOfRange(
+			bounded{"fieldPercent", s.FieldPercent, 0, 1},
+			bounded{"threePercent", s.ThreePercent, 0, 1},
+			bounded{"twoPercent", s.TwoPercent, 0, 1},
+			bounded{"ftPercent", s.FTPercent, 0, 1},
+			bounded{"effectFgPercent", s.EffectFGPercent, 0, 1.5},
+		)
+	}},
+	{"gs_le_games", "GamesStarted <= Games", func(s *models.PlayerTotalStat) string {
+		if s.GamesStarted > s.Games {
+			return fmt.Sprintf("GS %d > G %d", s.GamesStarted, s.Games)
+		}
+		return ""
+	}},
+	{"non_negative", "Counting stats are never negative", func(s *models.PlayerTotalStat) string {
+		for _, c := range []struct {
+			name string
+			v    int
+		}{
+			{"games", s.Games}, {"fieldAttempts", s.FieldAttempts}, {"totalRb", s.TotalRB},
+			{"assists", s.Assists}, {"steals", s.Steals}, {"blocks", s.Blocks},
+			{"turnovers", s.Turnovers}, {"personalFouls", s.PersonalFouls}, {"points", s.Points},
+		} {
+			if c.v < 0 {
+				return fmt.Sprintf("%s is %d", c.name, c.v)
+			}
+		}
+		return ""
+	}},
+}
+
+var advancedRules = []qualityRule[models.PlayerAdvancedStat]{
+	{"ws_sum", "WinShares = OffensiveWS + DefensiveWS (±0.15 rounding)", func(s *models.PlayerAdvancedStat) string {
+		return floatMismatch("WS", s.WinShares, "OWS+DWS", s.OffensiveWS+s.DefensiveWS, 0.15)
+	}},
+	{"bpm_sum", "Box = OffensiveBox + DefensiveBox (±0.15 rounding)", func(s *models.PlayerAdvancedStat) string {
+		return floatMismatch("BPM", s.Box, "OBPM+DBPM", s.OffensiveBox+s.DefensiveBox, 0.15)
+	}},
+	{"pct_range", "Rate stats are within their possible range", func(s *models.PlayerAdvancedStat) string {
+		return firstOutOfRange(
+			bounded{"tsPercent", s.TSPercent, 0, 1.5},
+			bounded{"threePAR", s.ThreePAR, 0, 1},
+			bounded{"offensiveRBPercent", s.OffensiveRBPercent, 0, 100},
+			bounded{"defensiveRBPercent", s.DefensiveRBPercent, 0, 100},
+			bounded{"totalRBPercent", s.TotalRBPercent, 0, 100},
+			bounded{"assistPercent", s.AssistPercent, 0, 100},
+			bounded{"stealPercent", s.StealPercent, 0, 100},
+			bounded{"blockPercent", s.BlockPercent, 0, 100},
+			bounded{"turnoverPercent", s.TurnoverPercent, 0, 100},
+			bounded{"usagePercent", s.UsagePercent, 0, 100},
+		)
+	}},
+	{"games_positive", "Games > 0 and MinutesPlayed >= 0", func(s *models.PlayerAdvancedStat) string {
+		if s.Games <= 0 {
+			return fmt.Sprintf("games is %d", s.Games)
+		}
+		if s.MinutesPlayed < 0 {
+			return fmt.Sprintf("minutesPlayed is %d", s.MinutesPlayed)
+		}
+		return ""
+	}},
+}
+
+var shotRules = []qualityRule[models.PlayerShotChart]{
+	{"three_distance", "3-pointers are taken from at least 22 ft", func(s *models.PlayerShotChart) string {
+		if s.ShotType == "3-pointer" && s.DistanceFt < 22 {
+			return fmt.Sprintf("3-pointer from %d ft", s.DistanceFt)
+		}
+		return ""
+	}},
+	{"score_non_negative", "Scores are never negative", func(s *models.PlayerShotChart) string {
+		if s.TeamScore < 0 || s.OpponentTeamScore < 0 {
+			return fmt.Sprintf("score %d-%d", s.TeamScore, s.OpponentTeamScore)
+		}
+		return ""
+	}},
+	{"context_parsed", "Game date and period are parsed", func(s *models.PlayerShotChart) string {
+		if s.GameDate == nil || s.Period < 1 {
+			return fmt.Sprintf("date %q / period %q not parsed", s.Date, s.Quarter)
+		}
+		return ""
+	}},
+}
+
+// DataQualityRules lists every built-in rule.
+func DataQualityRules() []DataQualityRuleInfo {
+	var out []DataQualityRuleInfo
+	for _, r := range totalsRules {
+		out = append(out, DataQualityRuleInfo{DatasetTotals, r.Name, r.Description})
+	}
+	for _, r := range advancedRules {
+		out = append(out, DataQualityRuleInfo{DatasetAdvanced, r.Name, r.Description})
+	}
+	for _, r := range shotRules {
+		out = append(out, DataQualityRuleInfo{DatasetShots, r.Name, r.Description})
+	}
+	return out
+}
+
+// ───────────────────────────────  engine  ───────────────────────────────
+
+// CheckDataQuality evaluates a dataset's rules over one league-season without
+// storing anything. Shot checks cover the whole season, so isPlayoff is
+// ignored there.
+func CheckDataQuality(db *gorm.DB, dataset, league string, season int, isPlayoff bool) ([]models.DataQualityViolation, error) {
+	switch dataset {
+	case DatasetTotals:
+		return checkRows(db.Where("league = ? AND season = ? AND is_playoff = ?", league, season, isPlayoff), totalsRules,
+			func(s *models.PlayerTotalStat) models.DataQualityViolation {
+				return models.DataQualityViolation{Dataset: dataset, League: league, Season: season, IsPlayoff: isPlayoff,
+					RecordID: s.ID, PlayerID: s.PlayerID, Team: s.Team}
+			})
+	case DatasetAdvanced:
+		return checkRows(db.Where("league = ? AND season = ? AND is_playoff = ?", league, season, isPlayoff), advancedRules,
+			func(s *models.PlayerAdvancedStat) models.DataQualityViolation {
+				return models.DataQualityViolation{Dataset: dataset, League: league, Season: season, IsPlayoff: isPlayoff,
+					RecordID: s.ID, PlayerID: s.PlayerID, Team: s.Team}
+			})
+	case DatasetShots:
+		return checkShots(db.Where("league = ? AND season = ?", league, season), league, season)
+	}
+	return nil, fmt.Errorf("unknown dataset %q", dataset)
+}
+
+// checkShots runs the shot rules over the shots query selects.
+func checkShots(query *gorm.DB, league string, season int) ([]models.DataQualityViolation, error) {
+	return checkRows(query, shotRules, func(s *models.PlayerShotChart) models.DataQualityViolation {
+		return models.DataQualityViolation{Dataset: DatasetShots, League: league, Season: season,
+			RecordID: s.ID, PlayerID: s.PlayerID, Team: s.Team}
+	})
+}
+
+// RunDataQuality checks one dataset-season and replaces its stored violations.
+func RunDataQuality(db *gorm.DB, dataset, league string, season int, isPlayoff bool) ([]models.DataQualityViolation, error) {
+	violations, err := CheckDataQuality(db, dataset, league, season, isPlayoff)
+	if err != nil {
+		return nil, err
+	}
+	if err := storeViolations(db, dataset, league, season, isPlayoff, violations); err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		log.Printf("⚠️  %d data quality violations in %s %s %d (playoffs=%v)", len(violations), league, dataset, season, isPlayoff)
+	}
+	return violations, nil
+}
+
+// RunPlayerShotDataQuality checks one player's shots in a season and
+// replaces only that player's stored shot violations, so a single-player
+// import does not rescan the whole season.
+func RunPlayerShotDataQuality(db *gorm.DB, league, playerID string, season int) ([]models.DataQualityViolation, error) {
+	violations, err := checkShots(db.Where("league = ? AND season = ? AND player_id = ?", league, season, playerID), league, season)
+	if err != nil {
+		return nil, err
+	}
+	if err := replaceViolations(db, violations, "dataset = ? AND league = ? AND season = ? AND is_playoff = ? AND player_id = ?",
+		DatasetShots, league, season, false, playerID); err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		log.Printf("⚠️  %d data quality violations in %s shots of %s in %d", len(violations), league, playerID, season)
+	}
+	return violations, nil
+}
+
+// RunSeasonDataQuality validates every dataset of a league-season, regular
+// season and playoffs, and returns the number of violations found.
+func RunSeasonDataQuality(db *gorm.DB, league string, season int) (int, error) {
+	total := 0
+	for _, dataset := range []string{DatasetTotals, DatasetAdvanced} {
+		for _, isPlayoff := range []bool{false, true} {
+			v, err := RunDataQuality(db, dataset, league, season, isPlayoff)
+			if err != nil {
+				return total, err
+			}
+			total += len(v)
+		}
+	}
+	v, err := RunDataQuality(db, DatasetShots, league, season, false)
+	if err != nil {
+		return total, err
+	}
+	return total + len(v), nil
+}
+
+// GetDataQualityReport summarizes the stored violations for a league-season.
+// limit caps the number of violation rows returned.
+func GetDataQualityReport(db *gorm.DB, league string, season, limit int) (DataQualityReport, error) {
+	report := DataQualityReport{League: league, Season: season, ByRule: map[string]int64{}, Rules: DataQualityRules()}
+
+	var counts []struct {
+		Dataset string
+		Rule    string
+		Count   int64
+	}
+	if err := db.Model(&models.DataQualityViolation{}).
+		Select("dataset, rule, COUNT(*) AS count").
+		Where("league = ? AND season = ?", league, season).
+		Group("dataset, rule").
+		Scan(&counts).Error; err != nil {
+		return report, err
+	}
+	for _, c := range counts {
+		report.ByRule[c.Dataset+"."+c.Rule] = c.Count
+		report.Total += c.Count
+	}
+
+	err := db.Where("league = ? AND season = ?", league, season).
+		Order("dataset, rule, player_id").
+		Limit(limit).
+		Find(&report.Violations).Error
+	return report, err
+}
+
+// commitWithQualityGate runs write inside a transaction, validates the
+// dataset-season it touched and records the violations. In strict mode
+// (DQ_STRICT=true) the write is rolled back when the violation count exceeds
+// DQ_MAX_VIOLATIONS; the violations are still stored for the report.
+func commitWithQualityGate(db *gorm.DB, dataset, league string, season int, isPlayoff bool, write func(tx *gorm.DB) error) error {
+	var violations []models.DataQualityViolation
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		var err error
+		if violations, err = CheckDataQuality(tx, dataset, league, season, isPlayoff); err != nil {
+			return err
+		}
+		if strict, maxViolations := qualityGateSettings(); strict && len(violations) > maxViolations {
+			return fmt.Errorf("%w: %s %s %d (playoffs=%v) has %d violations, max %d",
+				ErrQualityThreshold, league, dataset, season, isPlayoff, len(violations), maxViolations)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrQualityThreshold) {
+		return err
+	}
+
+	if serr := storeViolations(db, dataset, league, season, isPlayoff, violations); serr != nil {
+		log.Printf("failed to store data quality violations: %v", serr)
+	}
+	if len(violations) > 0 {
+		log.Printf("⚠️  %d data quality violations in %s %s %d (playoffs=%v)", len(violations), league, dataset, season, isPlayoff)
+	}
+	return err
+}
+
+// qualityGateSettings reads DQ_STRICT and DQ_MAX_VIOLATIONS (default 0).
+func qualityGateSettings() (strict bool, maxViolations int) {
+	strict, _ = strconv.ParseBool(os.Getenv("DQ_STRICT"))
+	maxViolations, _ = strconv.Atoi(os.Getenv("DQ_MAX_VIOLATIONS"))
+	return strict, maxViolations
+}
+
+// storeViolations replaces the stored violations of one dataset-league-season.
+func storeViolations(db *gorm.DB, dataset, league string, season int, isPlayoff bool, violations []models.DataQualityViolation) error {
+	return replaceViolations(db, violations, "dataset = ? AND league = ? AND season = ? AND is_playoff = ?",
+		dataset, league, season, isPlayoff)
+}
+
+// replaceViolations swaps the stored violations matching a condition for
+// violations.
+func replaceViolations(db *gorm.DB, violations []models.DataQualityViolation, query string, args ...interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(query, args...).Delete(&models.DataQualityViolation{}).Error; err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&violations, 500).Error
+	})
+}
+
+// checkRows streams a dataset's rows through its rules.
+func checkRows[T any](query *gorm.DB, rules []qualityRule[T], base func(*T) models.DataQualityViolation) ([]models.DataQualityViolation, error) {
+	var violations []models.DataQualityViolation
+	var rows []T
+	res := query.FindInBatches(&rows, 1000, func(_ *gorm.DB, _ int) error {
+		for i := range rows {
+			for _, rule := range rules {
+				if msg := rule.Check(&rows[i]); msg != "" {
+					v := base(&rows[i])
+					v.Rule, v.Message = rule.Name, msg
+					violations = append(violations, v)
+				}
+			}
+		}
+		return nil
+	})
+	return violations, res.Error
+}
+
+// bounded is a named value with its allowed range.
+type bounded struct {
+	name     string
+	v        float64
+	min, max float64
+}
+
+// firstOutOfRange reports the first value outside its range.
+func firstOutOfRange(values ...bounded) string {
+	for _, b := range values {
+		if b.v < b.min || b.v > b.max {
+			return fmt.Sprintf("%s %.3f outside [%g, %g]", b.name, b.v, b.min, b.max)
+		}
+	}
+	return ""
+}
+
+// mismatch reports two integer quantities that should be equal.
+func mismatch(name string, got int, wantName string, want int) string {
+	if got != want {
+		return fmt.Sprintf("%s %d != %s %d", name, got, wantName, want)
+	}
+	return ""
+}
+
+// floatMismatch reports two float quantities that differ by more than tol.
+func floatMismatch(name string, got float64, wantName string, want, tol float64) string {
+	if math.Abs(got-want) > tol {
+		return fmt.Sprintf("%s %.2f != %s %.2f", name, got, wantName, want)
+	}
+	return ""
+}
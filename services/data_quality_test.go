@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func newQualityTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerAdvancedStat{}, &models.DataQualityViolation{}, &models.PlayerCareerStat{}, &models.PlayerStatPercentile{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// cleanTotal is a self-consistent totals row.
+func cleanTotal(pid string) models.PlayerTotalStat {
+	return models.PlayerTotalStat{
+		PlayerID: pid, Team: "BOS", Season: 2024, Games: 70, GamesStarted: 70,
+		FieldGoals: 500, FieldAttempts: 1000, FieldPercent: 0.5,
+		TwoFG: 350, TwoAttempts: 600, TwoPercent: 0.583,
+		ThreeFG: 150, ThreeAttempts: 400, ThreePercent: 0.375,
+		FT: 200, FTAttempts: 250, FTPercent: 0.8, EffectFGPercent: 0.575,
+		OffensiveRB: 50, DefensiveRB: 300, TotalRB: 350,
+		Points: 2*350 + 3*150 + 200,
+	}
+}
+
+// rankedTotal is a 2024 NBA totals row with the playing time and scoring
+// that percentile rankings qualify and sort on.
+func rankedTotal(pid, pos, team string, games int, minutes float64, points, fg int, pct float64) models.PlayerTotalStat {
+	return models.PlayerTotalStat{
+		League: models.LeagueNBA, PlayerID: pid, Position: pos, Team: team, Season: 2024,
+		Games: games, MinutesPG: minutes, Points: points, FieldGoals: fg, FieldPercent: pct,
+	}
+}
+
+func TestCheckDataQualityTotals(t *testing.T) {
+	db := newQualityTestDB(t)
+
+	bad := cleanTotal("badbad01")
+	bad.FieldGoals = 501 // FG != 2P + 3P
+	bad.GamesStarted = 71
+	bad.FTPercent = 8.0
+	db.Create(&[]models.PlayerTotalStat{cleanTotal("goodgo01"), bad})
+
+	violations, err := CheckDataQuality(db, DatasetTotals, models.LeagueNBA, 2024, false)
+	assert.NoError(t, err)
+
+	rules := map[string]bool{}
+	for _, v := range violations {
+		assert.Equal(t, "badbad01", v.PlayerID)
+		rules[v.Rule] = true
+	}
+	assert.True(t, rules["fg_sum"])
+	assert.False(t, rules["makes_le_attempts"])
+	assert.True(t, rules["gs_le_games"])
+	assert.True(t, rules["pct_range"])
+}
+
+func TestCommitWithQualityGateStrict(t *testing.T) {
+	db := newQualityTestDB(t)
+	t.Setenv("DQ_STRICT", "true")
+	t.Setenv("DQ_MAX_VIOLATIONS", "0")
+
+	bad := cleanTotal("badbad01")
+	bad.GamesStarted = 99
+	err := commitWithQualityGate(db, DatasetTotals, models.LeagueNBA, 2024, false, func(tx *gorm.DB) error {
+		return tx.Create(&[]models.PlayerTotalStat{cleanTotal("goodgo01"), bad}).Error
+	})
+	assert.ErrorIs(t, err, ErrQualityThreshold)
+
+	var rows int64
+	db.Model(&models.PlayerTotalStat{}).Count(&rows)
+	assert.Zero(t, rows, "strict import must roll back the season")
+
+	report, err := GetDataQualityReport(db, models.LeagueNBA, 2024, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), report.ByRule["totals.gs_le_games"])
+
+	t.Setenv("DQ_MAX_VIOLATIONS", "5")
+	err = commitWithQualityGate(db, DatasetTotals, models.LeagueNBA, 2024, false, func(tx *gorm.DB) error {
+		return tx.Create(&[]models.PlayerTotalStat{cleanTotal("goodgo01"), bad}).Error
+	})
+	assert.NoError(t, err)
+	db.Model(&models.PlayerTotalStat{}).Count(&rows)
+	assert.Equal(t, int64(2), rows)
+}
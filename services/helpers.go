@@ -1,6 +1,22 @@
 package services
 
-import "strconv"
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+
+    "github.com/nprasad2077/NBA_Go/models"
+)
+
+const brBaseURL = "https://www.basketball-reference.com"
+
+// AggregateTeam is the normalized Team value stored on a traded player's
+// multi-team season row.
+const AggregateTeam = "TOT"
+
+// aggregateTeamRe matches every spelling BR uses for that row: "TOT" on
+// older pages, "2TM"/"3TM"/… on newer ones.
+var aggregateTeamRe = regexp.MustCompile(`^(TOT|\d+TM)$`)
 
 // mustAtoi parses s into an int, or returns 0 on error.
 func mustAtoi(s string) int {
@@ -14,4 +30,47 @@ func mustParseFloat(s string) float64 {
     return f
 }
 
+// NumberValue converts a numeric model field, as read by reflection or
+// repository.Project, to float64; anything else is 0.
+func NumberValue(v interface{}) float64 {
+    switch n := v.(type) {
+    case int:
+        return float64(n)
+    case int64:
+        return float64(n)
+    case uint:
+        return float64(n)
+    case float64:
+        return n
+    }
+    return 0
+}
+
+// normalizeTeam folds the aggregate spellings into AggregateTeam and
+// reports whether the row is a multi-team aggregate.
+func normalizeTeam(team string) (string, bool) {
+    if aggregateTeamRe.MatchString(team) {
+        return AggregateTeam, true
+    }
+    return team, false
+}
 
+// brSeasonURL builds a Basketball-Reference season table URL under
+// baseURL, e.g. ("NBA", 2024, false, "totals") → /leagues/NBA_2024_totals.html.
+// WNBA seasons live under /wnba/years/ and have no playoff tables there.
+func brSeasonURL(baseURL, league string, season int, isPlayoff bool, table string) (string, error) {
+    switch league {
+    case models.LeagueNBA, models.LeagueABA:
+        section := "leagues"
+        if isPlayoff {
+            section = "playoffs"
+        }
+        return fmt.Sprintf("%s/%s/%s_%d_%s.html", baseURL, section, league, season, table), nil
+    case models.LeagueWNBA:
+        if isPlayoff {
+            return "", fmt.Errorf("no %s playoff %s table on Basketball-Reference", league, table)
+        }
+        return fmt.Sprintf("%s/wnba/years/%d_%s.html", baseURL, season, table), nil
+    }
+    return "", fmt.Errorf("unsupported league %q", league)
+}
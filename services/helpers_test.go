@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestBRSeasonURL(t *testing.T) {
+	cases := []struct {
+		league    string
+		season    int
+		isPlayoff bool
+		table     string
+		want      string
+	}{
+		{"NBA", 2024, false, "totals", "https://www.basketball-reference.com/leagues/NBA_2024_totals.html"},
+		{"NBA", 2024, true, "advanced", "https://www.basketball-reference.com/playoffs/NBA_2024_advanced.html"},
+		{"ABA", 1976, false, "totals", "https://www.basketball-reference.com/leagues/ABA_1976_totals.html"},
+		{"WNBA", 2024, false, "advanced", "https://www.basketball-reference.com/wnba/years/2024_advanced.html"},
+	}
+	for _, tc := range cases {
+		got, err := brSeasonURL(brBaseURL, tc.league, tc.season, tc.isPlayoff, tc.table)
+		assert.NoError(t, err, tc.want)
+		assert.Equal(t, tc.want, got)
+	}
+
+	_, err := brSeasonURL(brBaseURL, "WNBA", 2024, true, "totals")
+	assert.Error(t, err)
+	_, err = brSeasonURL(brBaseURL, "BAA", 1947, false, "totals")
+	assert.Error(t, err)
+}
+
+func TestBRProviderBaseURL(t *testing.T) {
+	srv := newBRStub(t, map[string]string{
+		"/leagues/NBA_2024_totals.html": `<html><body><table id="totals_stats">
+<thead><tr><th data-stat="ranker">Rk</th><th data-stat="name_display">Player</th><th data-stat="team_name_abbr">Team</th><th data-stat="pts">PTS</th></tr></thead>
+<tbody><tr><th>1</th><td data-append-csv="tatumja01">Jayson Tatum</td><td>BOS</td><td>2033</td></tr></tbody>
+</table></body></html>`,
+	})
+
+	stats, err := BRProvider{BaseURL: srv.URL}.FetchPlayerTotals(models.LeagueNBA, 2024, false)
+	require.NoError(t, err)
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, "tatumja01", stats[0].PlayerID)
+		assert.Equal(t, 2033, stats[0].Points)
+	}
+	// Advanced reads from the same base URL, where the stub has no table.
+	_, err = BRProvider{BaseURL: srv.URL}.FetchPlayerAdvanced(models.LeagueNBA, 2024, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+// File: services/leaders.go
+package services
+
+import (
+	"math"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+// Leaderboard datasets.
+const (
+	LeaderTotals   = "totals"
+	LeaderAdvanced = "advanced"
+	LeaderCareer   = "career"
+)
+
+// Qualification is the floor a row must reach to appear on a leaderboard.
+// Zero values impose nothing.
+type Qualification struct {
+	MinGames   int     `json:"minGames,omitempty"`
+	MinMinutes float64 `json:"minMinutes,omitempty"`
+	MinMade    int     `json:"minMade,omitempty"`
+	// MadeField is the JSON field MinMade applies to, e.g. "fieldGoals".
+	MadeField string `json:"madeField,omitempty"`
+}
+
+// scaled returns q with its floors multiplied by f, rounded up.
+func (q Qualification) scaled(f float64) Qualification {
+	q.MinGames = int(math.Ceil(float64(q.MinGames) * f))
+	q.MinMinutes = math.Ceil(q.MinMinutes * f)
+	q.MinMade = int(math.Ceil(float64(q.MinMade) * f))
+	return q
+}
+
+// Full-schedule floors, after Basketball-Reference's leaderboards. Rate
+// stats need makes or minutes; counting stats need nothing.
+var (
+	seasonQualifications = map[string]map[string]Qualification{
+		LeaderTotals: {
+			"fieldPercent":    {MinMade: 300, MadeField: "fieldGoals"},
+			"effectFgPercent": {MinMade: 300, MadeField: "fieldGoals"},
+			"twoPercent":      {MinMade: 300, MadeField: "twoFg"},
+			"threePercent":    {MinMade: 82, MadeField: "threeFg"},
+			"ftPercent":       {MinMade: 125, MadeField: "ft"},
+		},
+		LeaderAdvanced: {
+			"per":                {MinMinutes: 1500},
+			"tsPercent":          {MinMinutes: 1500},
+			"threePAR":           {MinMinutes: 1500},
+			"ftr":                {MinMinutes: 1500},
+			"offensiveRBPercent": {MinMinutes: 1500},
+			"defensiveRBPercent": {MinMinutes: 1500},
+			"totalRBPercent":     {MinMinutes: 1500},
+			"assistPercent":      {MinMinutes: 1500},
+			"stealPercent":       {MinMinutes: 1500},
+			"blockPercent":       {MinMinutes: 1500},
+			"turnoverPercent":    {MinMinutes: 1500},
+			"usagePercent":       {MinMinutes: 1500},
+			"winSharesPer":       {MinMinutes: 1500},
+			"offensiveBox":       {MinMinutes: 1500},
+			"defensiveBox":       {MinMinutes: 1500},
+			"box":                {MinMinutes: 1500},
+		},
+		LeaderCareer: {
+			"fieldPercent":    {MinMade: 2000, MadeField: "fieldGoals"},
+			"effectFgPercent": {MinMade: 2000, MadeField: "fieldGoals"},
+			"twoPercent":      {MinMade: 2000, MadeField: "twoFg"},
+			"threePercent":    {MinMade: 250, MadeField: "threeFg"},
+			"ftPercent":       {MinMade: 1200, MadeField: "ft"},
+		},
+	}
+
+	// playoffQualificationFactor scales the floors for playoff leaderboards.
+	playoffQualificationFactor = 0.1
+
+	// fullSeasonGames is each league's regular-season schedule length.
+	fullSeasonGames = map[string]int{models.LeagueNBA: 82, models.LeagueWNBA: 40, models.LeagueABA: 84}
+
+	// shortenedSeasonGames are NBA regular seasons cut short: the 1999 and
+	// 2012 lockouts, and the 2020 and 2021 pandemic seasons (2020's
+	// schedules ran 63–75 games; 72 is used league-wide).
+	shortenedSeasonGames = map[int]int{1999: 50, 2012: 66, 2020: 72, 2021: 72}
+)
+
+// seasonLengthFactor is a season's schedule length relative to the NBA's
+// 82 games.
+func seasonLengthFactor(league string, season int) float64 {
+	games := fullSeasonGames[league]
+	if league == models.LeagueNBA {
+		if g, ok := shortenedSeasonGames[season]; ok {
+			games = g
+		}
+	}
+	if games == 0 {
+		return 1
+	}
+	return float64(games) / 82
+}
+
+// LeaderQualification is the floor for stat on a dataset's leaderboard in
+// one league season (season is ignored for careers), prorated for the
+// schedule length and scaled down for the playoffs.
+func LeaderQualification(dataset, stat, league string, season int, isPlayoff bool) Qualification {
+	f := qualificationFactor(league, season, isPlayoff)
+	if dataset == LeaderCareer {
+		f = qualificationFactor("", 0, isPlayoff)
+	}
+	return seasonQualifications[dataset][stat].scaled(f)
+}
+
+// qualificationFactor scales full-schedule floors to one league season
+// and season type; an unknown league counts as 82 games.
+func qualificationFactor(league string, season int, isPlayoff bool) float64 {
+	f := seasonLengthFactor(league, season)
+	if isPlayoff {
+		f *= playoffQualificationFactor
+	}
+	return f
+}
+
+// LowestLeaderQualification is the loosest floor any season of the league
+// can have: what a leaderboard across every season can filter on before
+// applying each row's own season's floor.
+func LowestLeaderQualification(dataset, stat, league string, isPlayoff bool) Qualification {
+	return seasonQualifications[dataset][stat].scaled(lowestQualificationFactor(league, isPlayoff))
+}
+
+// lowestQualificationFactor is the smallest qualificationFactor of any
+// season of the league.
+func lowestQualificationFactor(league string, isPlayoff bool) float64 {
+	f := qualificationFactor(league, 0, isPlayoff)
+	if league == models.LeagueNBA {
+		for season := range shortenedSeasonGames {
+			f = min(f, qualificationFactor(league, season, isPlayoff))
+		}
+	}
+	return f
+}
+
+// Meets reports whether a row with these games, minutes and makes of the
+// MadeField qualifies.
+func (q Qualification) Meets(games int, minutes float64, made int) bool {
+	return games >= q.MinGames && minutes >= q.MinMinutes && made >= q.MinMade
+}
+
+// Leader is one leaderboard entry.
+type Leader struct {
+	Rank       int     `json:"rank"`
+	Tied       bool    `json:"tied"`
+	PlayerID   string  `json:"playerId"`
+	PlayerName string  `json:"playerName"`
+	Season     int     `json:"season,omitempty"` // not set on career boards
+	Team       string  `json:"team,omitempty"`
+	Value      float64 `json:"value"`
+	Games      int     `json:"games"`
+}
+
+// RankLeaders numbers leaders already sorted by Value. Equal values share
+// a rank and the next distinct value skips ahead (1, 2, 2, 4).
+func RankLeaders(leaders []Leader) {
+	for i := range leaders {
+		if i > 0 && leaders[i].Value == leaders[i-1].Value {
+			leaders[i].Rank = leaders[i-1].Rank
+			leaders[i].Tied, leaders[i-1].Tied = true, true
+		} else {
+			leaders[i].Rank = i + 1
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestLeaderQualification(t *testing.T) {
+	full := LeaderQualification(LeaderTotals, "threePercent", models.LeagueNBA, 2024, false)
+	assert.Equal(t, Qualification{MinMade: 82, MadeField: "threeFg"}, full)
+
+	// Shortened seasons are prorated: 66 of 82 games in 2012.
+	assert.Equal(t, 242, LeaderQualification(LeaderTotals, "fieldPercent", models.LeagueNBA, 2012, false).MinMade)
+	assert.Equal(t, 915.0, LeaderQualification(LeaderAdvanced, "per", models.LeagueNBA, 1999, false).MinMinutes)
+	// Playoffs need a tenth.
+	assert.Equal(t, 150.0, LeaderQualification(LeaderAdvanced, "per", models.LeagueNBA, 2024, true).MinMinutes)
+	// The WNBA plays 40 games.
+	assert.Equal(t, 732.0, LeaderQualification(LeaderAdvanced, "tsPercent", models.LeagueWNBA, 2024, false).MinMinutes)
+	// Careers ignore the season.
+	assert.Equal(t, 250, LeaderQualification(LeaderCareer, "threePercent", models.LeagueNBA, 2012, false).MinMade)
+	// Counting stats have no floor.
+	assert.Equal(t, Qualification{}, LeaderQualification(LeaderTotals, "points", models.LeagueNBA, 2024, false))
+
+	assert.Equal(t, 183, LowestLeaderQualification(LeaderTotals, "fieldPercent", models.LeagueNBA, false).MinMade)
+
+	q := Qualification{MinGames: 10, MinMinutes: 100, MinMade: 5}
+	assert.True(t, q.Meets(10, 100, 5))
+	assert.False(t, q.Meets(10, 99, 5))
+}
+
+func TestRankLeaders(t *testing.T) {
+	leaders := []Leader{{Value: 30}, {Value: 28}, {Value: 28}, {Value: 25}, {Value: 25}}
+	RankLeaders(leaders)
+	var ranks []int
+	var tied []bool
+	for _, l := range leaders {
+		ranks = append(ranks, l.Rank)
+		tied = append(tied, l.Tied)
+	}
+	assert.Equal(t, []int{1, 2, 2, 4, 4}, ranks)
+	assert.Equal(t, []bool{false, true, true, true, true}, tied)
+}
@@ -0,0 +1,534 @@
+// File: services/nba_stats_provider.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/utils"
+	"github.com/nprasad2077/NBA_Go/utils/metrics"
+)
+
+const nbaStatsBaseURL = "https://stats.nba.com/stats"
+
+// nbaStatsTimeout is longer than utils.DefaultTimeout: stats.nba.com often
+// takes well over ten seconds on league-wide queries.
+const nbaStatsTimeout = 30 * time.Second
+
+// nbaStatsHeaders are the browser-like headers stats.nba.com insists on.
+var nbaStatsHeaders = map[string]string{
+	"User-Agent":         "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/113.0.0.0 Safari/537.36",
+	"Accept":             "application/json, text/plain, */*",
+	"Referer":            "https://www.nba.com/",
+	"Origin":             "https://www.nba.com",
+	"x-nba-stats-origin": "stats",
+	"x-nba-stats-token":  "true",
+}
+
+// nbaTeamAbbr maps NBA.com franchise team IDs to their current abbreviation.
+var nbaTeamAbbr = map[int]string{
+	1610612737: "ATL", 1610612738: "BOS", 1610612739: "CLE", 1610612740: "NOP",
+	1610612741: "CHI", 1610612742: "DAL", 1610612743: "DEN", 1610612744: "GSW",
+	1610612745: "HOU", 1610612746: "LAC", 1610612747: "LAL", 1610612748: "MIA",
+	1610612749: "MIL", 1610612750: "MIN", 1610612751: "BKN", 1610612752: "NYK",
+	1610612753: "ORL", 1610612754: "IND", 1610612755: "PHI", 1610612756: "PHX",
+	1610612757: "POR", 1610612758: "SAC", 1610612759: "SAS", 1610612760: "OKC",
+	1610612761: "TOR", 1610612762: "UTA", 1610612763: "MEM", 1610612764: "WAS",
+	1610612765: "DET", 1610612766: "CHA",
+}
+
+// nbaToBRTeams maps the NBA.com team abbreviations Basketball-Reference
+// spells differently, so both providers' rows share one Team value.
+var nbaToBRTeams = map[string]string{"BKN": "BRK", "PHX": "PHO"}
+
+// brTeamAbbr translates an NBA.com team abbreviation into BR's for a
+// season. Charlotte is CHA on both sites for the Bobcats; BR files the
+// Hornets from 2015 on as CHO.
+func brTeamAbbr(abbr string, season int) string {
+	if abbr == "CHA" && season >= 2015 {
+		return "CHO"
+	}
+	if br, ok := nbaToBRTeams[abbr]; ok {
+		return br
+	}
+	return abbr
+}
+
+// nbaZones maps shotchartdetail's SHOT_ZONE_BASIC onto ShotZones.
+var nbaZones = map[string]string{
+	"Restricted Area":       ZoneRestrictedArea,
+	"In The Paint (Non-RA)": ZonePaintNonRA,
+	"Mid-Range":             ZoneMidRange,
+	"Left Corner 3":         ZoneLeftCorner3,
+	"Right Corner 3":        ZoneRightCorner3,
+	"Above the Break 3":     ZoneAboveBreak3,
+	"Backcourt":             ZoneBackcourt,
+}
+
+// NBAStatsProvider reads the stats.nba.com JSON endpoints
+// (leaguedashplayerstats, shotchartdetail). Player IDs are NBA.com person IDs.
+type NBAStatsProvider struct {
+	// BaseURL defaults to stats.nba.com; tests point it at a stub server.
+	BaseURL string
+	Headers map[string]string
+}
+
+// NewNBAStatsProvider returns a provider for the live stats.nba.com API.
+func NewNBAStatsProvider() *NBAStatsProvider {
+	return &NBAStatsProvider{BaseURL: nbaStatsBaseURL, Headers: nbaStatsHeaders}
+}
+
+func (p *NBAStatsProvider) Name() string { return ProviderNBA }
+
+// OwnsPlayerID accepts numeric NBA.com person IDs such as "201935".
+func (p *NBAStatsProvider) OwnsPlayerID(id string) bool { return isNumericID(id) }
+
+// FetchPlayerTotals maps leaguedashplayerstats (Base, Totals). NBA.com has a
+// single row per player-season under the player's latest team, and no
+// games-started, position or 2-point split; 2P is derived from FG - 3P.
+// A traded player's row is stored as the season aggregate; see tradedPlayers.
+func (p *NBAStatsProvider) FetchPlayerTotals(league string, season int, isPlayoff bool) ([]models.PlayerTotalStat, error) {
+	if err := nbaOnly(league); err != nil {
+		return nil, err
+	}
+	rs, err := p.fetchResultSet("leaguedashplayerstats", p.leagueDashParams(season, isPlayoff, "Base"), "LeagueDashPlayerStats")
+	if err != nil {
+		return nil, err
+	}
+	traded, err := p.tradedPlayers(season, isPlayoff)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]models.PlayerTotalStat, 0, len(rs.RowSet))
+	for _, row := range rs.rows() {
+		fg, fga := row.int("FGM"), row.int("FGA")
+		fg3, fg3a := row.int("FG3M"), row.int("FG3A")
+		stat := models.PlayerTotalStat{
+			League:        models.LeagueNBA,
+			PlayerID:      row.str("PLAYER_ID"),
+			PlayerName:    row.str("PLAYER_NAME"),
+			Age:           row.int("AGE"),
+			Games:         row.int("GP"),
+			MinutesPG:     row.float("MIN"),
+			FieldGoals:    fg,
+			FieldAttempts: fga,
+			FieldPercent:  row.float("FG_PCT"),
+			ThreeFG:       fg3,
+			ThreeAttempts: fg3a,
+			ThreePercent:  row.float("FG3_PCT"),
+			TwoFG:         fg - fg3,
+			TwoAttempts:   fga - fg3a,
+			TwoPercent:    ratio(fg-fg3, fga-fg3a),
+			FT:            row.int("FTM"),
+			FTAttempts:    row.int("FTA"),
+			FTPercent:     row.float("FT_PCT"),
+			OffensiveRB:   row.int("OREB"),
+			DefensiveRB:   row.int("DREB"),
+			TotalRB:       row.int("REB"),
+			Assists:       row.int("AST"),
+			Steals:        row.int("STL"),
+			Blocks:        row.int("BLK"),
+			Turnovers:     row.int("TOV"),
+			PersonalFouls: row.int("PF"),
+			Points:        row.int("PTS"),
+			Team:          brTeamAbbr(row.str("TEAM_ABBREVIATION"), season),
+			Season:        season,
+			IsPlayoff:     isPlayoff,
+		}
+		if fga > 0 {
+			stat.EffectFGPercent = round3((float64(fg) + 0.5*float64(fg3)) / float64(fga))
+		}
+		if traded[stat.PlayerID] {
+			stat.Team, stat.IsAggregate = AggregateTeam, true
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// nbaStatsUntracked are the stats, by dataset and JSON name, that NBA.com
+// does not report; its rows store 0 for them.
+var nbaStatsUntracked = map[string][]string{
+	DatasetTotals: {"gamesStarted"},
+	DatasetAdvanced: {
+		"per", "threePAR", "ftr", "stealPercent", "blockPercent",
+		"offensiveWS", "defensiveWS", "winShares", "winSharesPer",
+		"offensiveBox", "defensiveBox", "box", "vorp",
+	},
+}
+
+// nbaStatsTracks reports whether a row of playerID has a value for the
+// dataset's stat: always for BR rows, and for NBA.com rows unless the
+// stat is one nbaStatsUntracked lists.
+func nbaStatsTracks(dataset, name, playerID string) bool {
+	return !isNumericID(playerID) || !slices.Contains(nbaStatsUntracked[dataset], name)
+}
+
+// FetchPlayerAdvanced maps leaguedashplayerstats (Advanced, Totals). Rates
+// are converted to BR's percent units; BR-only metrics (PER, WS, BPM, VORP,
+// STL%, BLK%, 3PAr, FTr) are left zero. Traded players are handled as in
+// FetchPlayerTotals.
+func (p *NBAStatsProvider) FetchPlayerAdvanced(league string, season int, isPlayoff bool) ([]models.PlayerAdvancedStat, error) {
+	if err := nbaOnly(league); err != nil {
+		return nil, err
+	}
+	rs, err := p.fetchResultSet("leaguedashplayerstats", p.leagueDashParams(season, isPlayoff, "Advanced"), "LeagueDashPlayerStats")
+	if err != nil {
+		return nil, err
+	}
+	traded, err := p.tradedPlayers(season, isPlayoff)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]models.PlayerAdvancedStat, 0, len(rs.RowSet))
+	for _, row := range rs.rows() {
+		stat := models.PlayerAdvancedStat{
+			League:             models.LeagueNBA,
+			PlayerID:           row.str("PLAYER_ID"),
+			PlayerName:         row.str("PLAYER_NAME"),
+			Age:                row.int("AGE"),
+			Games:              row.int("GP"),
+			MinutesPlayed:      int(row.float("MIN")),
+			TSPercent:          row.float("TS_PCT"),
+			OffensiveRBPercent: pct(row.float("OREB_PCT")),
+			DefensiveRBPercent: pct(row.float("DREB_PCT")),
+			TotalRBPercent:     pct(row.float("REB_PCT")),
+			AssistPercent:      pct(row.float("AST_PCT")),
+			TurnoverPercent:    pct(row.float("TM_TOV_PCT")),
+			UsagePercent:       pct(row.float("USG_PCT")),
+			Team:               brTeamAbbr(row.str("TEAM_ABBREVIATION"), season),
+			Season:             season,
+			IsPlayoff:          isPlayoff,
+		}
+		if traded[stat.PlayerID] {
+			stat.Team, stat.IsAggregate = AggregateTeam, true
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// tradedPlayers returns the players who played for more than one team in
+// a regular season, from the league's game logs. leaguedashplayerstats
+// files their whole season under the latest team, so their rows are
+// stored as the season aggregate (AggregateTeam) instead; NBA.com has no
+// per-team split, so they get no per-team rows. Nobody changes teams
+// during the playoffs.
+func (p *NBAStatsProvider) tradedPlayers(season int, isPlayoff bool) (map[string]bool, error) {
+	if isPlayoff {
+		return nil, nil
+	}
+	params := url.Values{
+		"Season":     {nbaSeason(season)},
+		"SeasonType": {"Regular Season"},
+		"LeagueID":   {"00"},
+	}
+	rs, err := p.fetchResultSet("playergamelogs", params, "PlayerGameLogs")
+	if err != nil {
+		return nil, err
+	}
+	firstTeam := map[string]string{}
+	traded := map[string]bool{}
+	for _, row := range rs.rows() {
+		player, team := row.str("PLAYER_ID"), row.str("TEAM_ID")
+		if first, ok := firstTeam[player]; !ok {
+			firstTeam[player] = team
+		} else if first != team {
+			traded[player] = true
+		}
+	}
+	return traded, nil
+}
+
+// FetchShotChart maps shotchartdetail for one player-season, regular season
+// and playoffs. NBA.com gives exact coordinates (LOC_X/LOC_Y in tenths of a
+// foot) and game IDs but no running score.
+func (p *NBAStatsProvider) FetchShotChart(playerID string, season int) ([]models.PlayerShotChart, error) {
+	var shots []models.PlayerShotChart
+	for _, seasonType := range []string{"Regular Season", "Playoffs"} {
+		params := url.Values{
+			"PlayerID":       {playerID},
+			"Season":         {nbaSeason(season)},
+			"SeasonType":     {seasonType},
+			"ContextMeasure": {"FGA"},
+			"TeamID":         {"0"},
+			"GameID":         {""},
+			"LeagueID":       {"00"},
+			"Outcome":        {""},
+			"Location":       {""},
+			"Month":          {"0"},
+			"OpponentTeamID": {"0"},
+			"Period":         {"0"},
+			"LastNGames":     {"0"},
+			"PlayerPosition": {""},
+			"DateFrom":       {""},
+			"DateTo":         {""},
+			"SeasonSegment":  {""},
+			"VsConference":   {""},
+			"VsDivision":     {""},
+			"RookieYear":     {""},
+		}
+		rs, err := p.fetchResultSet("shotchartdetail", params, "Shot_Chart_Detail")
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rs.rows() {
+			shot, err := nbaShot(row, season)
+			if err != nil {
+				metrics.ShotsSkippedTotal.WithLabelValues("malformed_nba_row").Inc()
+				log.Printf("⚠️  Skipping shot for player %s in season %d: %v", playerID, season, err)
+				continue
+			}
+			shot.PlayerID = playerID
+			shot.Season = season
+			shot.IsPlayoff = seasonType == "Playoffs"
+			shots = append(shots, shot)
+		}
+	}
+	return shots, nil
+}
+
+// nbaShot maps one shotchartdetail row of a season.
+func nbaShot(row nbaRow, season int) (models.PlayerShotChart, error) {
+	gameDate, err := time.Parse("20060102", row.str("GAME_DATE"))
+	if err != nil {
+		return models.PlayerShotChart{}, fmt.Errorf("shotchartdetail: bad GAME_DATE %q", row.str("GAME_DATE"))
+	}
+	period := row.int("PERIOD")
+	remaining := row.int("MINUTES_REMAINING")*60 + row.int("SECONDS_REMAINING")
+	x, y := float64(row.int("LOC_X"))/10, float64(row.int("LOC_Y"))/10
+
+	shot := models.PlayerShotChart{
+		PlayerName:       row.str("PLAYER_NAME"),
+		GameID:           row.str("GAME_ID"),
+		Date:             gameDate.Format("Jan 2,2006"), // same form as BR rows
+		Quarter:          periodLabel(period),
+		TimeRemaining:    fmt.Sprintf("%d:%02d", remaining/60, remaining%60),
+		Top:              int(basketTopPx + y*courtPxPerFt),
+		Left:             int(basketLeftPx + x*courtPxPerFt),
+		Result:           row.int("SHOT_MADE_FLAG") == 1,
+		DistanceFt:       row.int("SHOT_DISTANCE"),
+		GameDate:         &gameDate,
+		Period:           period,
+		IsOvertime:       period > regulationPeriods,
+		SecondsRemaining: remaining,
+		ElapsedSeconds:   elapsedGameSeconds(period, remaining),
+		CourtX:           round1(x),
+		CourtY:           round1(y),
+		ShotAngle:        round1(math.Atan2(x, y) * 180 / math.Pi),
+	}
+	switch row.str("SHOT_TYPE") {
+	case "3PT Field Goal":
+		shot.ShotType = "3-pointer"
+	default:
+		shot.ShotType = "2-pointer"
+	}
+
+	// Prefer NBA.com's own zone; fall back to our geometry.
+	shot.Zone = nbaZones[row.str("SHOT_ZONE_BASIC")]
+	if shot.Zone == "" {
+		shot.Zone = classifyZone(shot.CourtX, shot.CourtY, shot.ShotType)
+	}
+
+	// Team, opponent and home/away from the home/visitor abbreviations,
+	// stored in BR's spelling.
+	home, visitor := row.str("HTM"), row.str("VTM")
+	if team, ok := nbaTeamAbbr[row.int("TEAM_ID")]; ok && (team == home || team == visitor) {
+		isHome := team == home
+		shot.Team, shot.IsHome = brTeamAbbr(team, season), &isHome
+		if isHome {
+			shot.Opponent = brTeamAbbr(visitor, season)
+		} else {
+			shot.Opponent = brTeamAbbr(home, season)
+		}
+	}
+	return shot, nil
+}
+
+// nbaOnly rejects leagues this provider does not serve.
+func nbaOnly(league string) error {
+	if league != models.LeagueNBA {
+		return fmt.Errorf("provider %s only serves the %s, not %s", ProviderNBA, models.LeagueNBA, league)
+	}
+	return nil
+}
+
+// leagueDashParams builds the leaguedashplayerstats query for one season.
+func (p *NBAStatsProvider) leagueDashParams(season int, isPlayoff bool, measure string) url.Values {
+	seasonType := "Regular Season"
+	if isPlayoff {
+		seasonType = "Playoffs"
+	}
+	return url.Values{
+		"Season":           {nbaSeason(season)},
+		"SeasonType":       {seasonType},
+		"MeasureType":      {measure},
+		"PerMode":          {"Totals"},
+		"LeagueID":         {"00"},
+		"PlusMinus":        {"N"},
+		"PaceAdjust":       {"N"},
+		"Rank":             {"N"},
+		"LastNGames":       {"0"},
+		"Month":            {"0"},
+		"OpponentTeamID":   {"0"},
+		"Period":           {"0"},
+		"TeamID":           {"0"},
+		"DateFrom":         {""},
+		"DateTo":           {""},
+		"GameSegment":      {""},
+		"Location":         {""},
+		"Outcome":          {""},
+		"PORound":          {"0"},
+		"SeasonSegment":    {""},
+		"ShotClockRange":   {""},
+		"VsConference":     {""},
+		"VsDivision":       {""},
+		"PlayerExperience": {""},
+		"PlayerPosition":   {""},
+		"StarterBench":     {""},
+		"College":          {""},
+		"Country":          {""},
+		"DraftYear":        {""},
+		"DraftPick":        {""},
+		"Height":           {""},
+		"Weight":           {""},
+		"Conference":       {""},
+		"Division":         {""},
+		"GameScope":        {""},
+		"TwoWay":           {"0"},
+	}
+}
+
+// ─────────────────────────  stats.nba.com result sets  ─────────────────────────
+
+// nbaResponse is the common stats.nba.com envelope.
+type nbaResponse struct {
+	ResultSets []nbaResultSet `json:"resultSets"`
+}
+
+type nbaResultSet struct {
+	Name    string              `json:"name"`
+	Headers []string            `json:"headers"`
+	RowSet  [][]json.RawMessage `json:"rowSet"`
+}
+
+// nbaRow is one result-set row addressed by header name.
+type nbaRow struct {
+	index map[string]int
+	cells []json.RawMessage
+}
+
+// fetchResultSet GETs endpoint and returns the named result set.
+func (p *NBAStatsProvider) fetchResultSet(endpoint string, params url.Values, name string) (nbaResultSet, error) {
+	body, err := utils.GetJSONWithHeaders(p.BaseURL+"/"+endpoint+"?"+params.Encode(), p.Headers, nbaStatsTimeout)
+	if err != nil {
+		return nbaResultSet{}, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	var resp nbaResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nbaResultSet{}, fmt.Errorf("%s: decode: %w", endpoint, err)
+	}
+	for _, rs := range resp.ResultSets {
+		if rs.Name == name {
+			return rs, nil
+		}
+	}
+	return nbaResultSet{}, fmt.Errorf("%s: no %s result set", endpoint, name)
+}
+
+func (rs nbaResultSet) rows() []nbaRow {
+	index := make(map[string]int, len(rs.Headers))
+	for i, h := range rs.Headers {
+		index[h] = i
+	}
+	rows := make([]nbaRow, len(rs.RowSet))
+	for i, cells := range rs.RowSet {
+		rows[i] = nbaRow{index: index, cells: cells}
+	}
+	return rows
+}
+
+// raw returns the cell for header, or nil when absent.
+func (r nbaRow) raw(header string) json.RawMessage {
+	i, ok := r.index[header]
+	if !ok || i >= len(r.cells) {
+		return nil
+	}
+	return r.cells[i]
+}
+
+// str renders a string or numeric cell as a string ("" for null).
+func (r nbaRow) str(header string) string {
+	raw := r.raw(header)
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var f json.Number
+	if json.Unmarshal(raw, &f) == nil {
+		return f.String()
+	}
+	return ""
+}
+
+func (r nbaRow) float(header string) float64 {
+	return mustParseFloat(r.str(header))
+}
+
+func (r nbaRow) int(header string) int {
+	return int(r.float(header))
+}
+
+// ─────────────────────────────────  helpers  ─────────────────────────────────
+
+// nbaSeason turns BR's end-year season (2024) into NBA.com's "2023-24".
+func nbaSeason(season int) string {
+	return fmt.Sprintf("%d-%02d", season-1, season%100)
+}
+
+// periodLabel renders a period number the way BR tooltips do.
+func periodLabel(period int) string {
+	if period > regulationPeriods {
+		return ordinal(period-regulationPeriods) + " OT"
+	}
+	return ordinal(period) + " Qtr"
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// pct converts an NBA.com fraction (0.253) into BR's percent units (25.3).
+func pct(f float64) float64 {
+	return round1(f * 100)
+}
+
+func ratio(made, att int) float64 {
+	if att == 0 {
+		return 0
+	}
+	return round3(float64(made) / float64(att))
+}
+
+func round3(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
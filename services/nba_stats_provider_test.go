@@ -0,0 +1,181 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+// newNBAStub serves the recorded stats.nba.com responses in testdata/nbastats.
+func newNBAStub(t *testing.T) *NBAStatsProvider {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var fixture string
+		switch r.URL.Path {
+		case "/leaguedashplayerstats":
+			if q.Get("Season") != "2023-24" || q.Get("PerMode") != "Totals" {
+				http.Error(w, "unexpected query", http.StatusBadRequest)
+				return
+			}
+			switch q.Get("MeasureType") {
+			case "Base":
+				fixture = "leaguedashplayerstats_base.json"
+			case "Advanced":
+				fixture = "leaguedashplayerstats_advanced.json"
+			}
+		case "/playergamelogs":
+			if q.Get("Season") != "2023-24" || q.Get("SeasonType") != "Regular Season" {
+				http.Error(w, "unexpected query", http.StatusBadRequest)
+				return
+			}
+			fixture = "playergamelogs.json"
+		case "/shotchartdetail":
+			switch q.Get("SeasonType") {
+			case "Regular Season":
+				fixture = "shotchartdetail_regular.json"
+			case "Playoffs":
+				fixture = "shotchartdetail_playoffs.json"
+			}
+		}
+		if fixture == "" {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", "nbastats", fixture))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewNBAStatsProvider()
+	p.BaseURL = srv.URL
+	return p
+}
+
+func TestNBAStatsFetchPlayerTotals(t *testing.T) {
+	stats, err := newNBAStub(t).FetchPlayerTotals(models.LeagueNBA, 2024, false)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	tatum := stats[0]
+	assert.Equal(t, "1628369", tatum.PlayerID)
+	assert.Equal(t, "BOS", tatum.Team)
+	assert.Equal(t, 74, tatum.Games)
+	assert.Equal(t, 675-223, tatum.TwoFG)
+	assert.Equal(t, 1433-597, tatum.TwoAttempts)
+	assert.Equal(t, 2018, tatum.Points)
+	assert.InDelta(t, 0.549, tatum.EffectFGPercent, 0.001)
+	assert.Equal(t, 2024, tatum.Season)
+	assert.False(t, tatum.IsAggregate)
+
+	// Harden played for PHI and LAC: his one row is the season aggregate.
+	harden := stats[1]
+	assert.Equal(t, AggregateTeam, harden.Team)
+	assert.True(t, harden.IsAggregate)
+}
+
+func TestNBAStatsFetchPlayerAdvanced(t *testing.T) {
+	stats, err := newNBAStub(t).FetchPlayerAdvanced(models.LeagueNBA, 2024, false)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	assert.Equal(t, "1628369", stats[0].PlayerID)
+	assert.Equal(t, 2645, stats[0].MinutesPlayed)
+	assert.InDelta(t, 0.604, stats[0].TSPercent, 1e-9)
+	assert.InDelta(t, 29.6, stats[0].UsagePercent, 1e-9)
+	assert.InDelta(t, 21.8, stats[0].AssistPercent, 1e-9)
+}
+
+func TestNBAStatsFetchShotChart(t *testing.T) {
+	shots, err := newNBAStub(t).FetchShotChart("201935", 2024)
+	require.NoError(t, err)
+	// The row with a bad GAME_DATE is skipped.
+	require.Len(t, shots, 3)
+
+	step := shots[0]
+	assert.Equal(t, "0022300061", step.GameID)
+	assert.Equal(t, "Nov 5,2023", step.Date)
+	assert.Equal(t, "1st Qtr", step.Quarter)
+	assert.Equal(t, "11:15", step.TimeRemaining)
+	assert.Equal(t, "3-pointer", step.ShotType)
+	assert.True(t, step.Result)
+	assert.Equal(t, ZoneAboveBreak3, step.Zone)
+	assert.Equal(t, "LAC", step.Team)
+	assert.Equal(t, "NYK", step.Opponent)
+	require.NotNil(t, step.IsHome)
+	assert.False(t, *step.IsHome)
+	assert.InDelta(t, -1.2, step.CourtX, 1e-9)
+	assert.InDelta(t, 25.9, step.CourtY, 1e-9)
+	assert.False(t, step.IsPlayoff)
+
+	ot := shots[1]
+	assert.Equal(t, "1st OT", ot.Quarter)
+	assert.True(t, ot.IsOvertime)
+	assert.Equal(t, ZoneRestrictedArea, ot.Zone)
+
+	playoff := shots[2]
+	assert.True(t, playoff.IsPlayoff)
+	assert.Equal(t, ZoneLeftCorner3, playoff.Zone)
+	require.NotNil(t, playoff.IsHome)
+	assert.True(t, *playoff.IsHome)
+	assert.Equal(t, "DAL", playoff.Opponent)
+}
+
+func TestBRTeamAbbr(t *testing.T) {
+	cases := []struct {
+		abbr   string
+		season int
+		want   string
+	}{
+		{"BKN", 2024, "BRK"},
+		{"PHX", 2024, "PHO"},
+		{"CHA", 2024, "CHO"},
+		{"CHA", 2010, "CHA"}, // the Bobcats
+		{"BOS", 2024, "BOS"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, brTeamAbbr(tc.abbr, tc.season), tc.abbr)
+	}
+}
+
+func TestImportPlayerTotalsFromNBA(t *testing.T) {
+	db := newQualityTestDB(t)
+	p := newNBAStub(t)
+
+	require.NoError(t, ImportPlayerTotals(db, p, models.LeagueNBA, 2024, false))
+	// Re-importing upserts in place.
+	require.NoError(t, ImportPlayerTotals(db, p, models.LeagueNBA, 2024, false))
+
+	var rows []models.PlayerTotalStat
+	require.NoError(t, db.Order("player_id").Find(&rows).Error)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "1628369", rows[0].PlayerID)
+	assert.Equal(t, "201935", rows[1].PlayerID)
+}
+
+func TestProviderByName(t *testing.T) {
+	p, err := ProviderByName("")
+	require.NoError(t, err)
+	assert.Equal(t, ProviderBR, p.Name())
+	assert.True(t, p.OwnsPlayerID("hardeja01"))
+
+	p, err = ProviderByName("nba")
+	require.NoError(t, err)
+	assert.Equal(t, ProviderNBA, p.Name())
+	assert.True(t, p.OwnsPlayerID("201935"))
+	assert.False(t, p.OwnsPlayerID("hardeja01"))
+
+	_, err = ProviderByName("espn")
+	assert.Error(t, err)
+}
@@ -0,0 +1,258 @@
+// File: services/percentiles.go
+package services
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// populationMinMinutes is what a full-schedule regular season needs for a
+// row to count among a season's qualified players, for percentiles and
+// similarity search. It is prorated and scaled for the playoffs like the
+// leaderboard floors.
+const populationMinMinutes = 500
+
+var (
+	totalPercentileFields    = percentileFields(&models.PlayerTotalStat{})
+	advancedPercentileFields = percentileFields(&models.PlayerAdvancedStat{})
+	percentileSchemas        sync.Map
+)
+
+// statField is a numeric model field, named by its JSON name.
+type statField struct {
+	name  string
+	field *schema.Field
+}
+
+// percentileFields are a model's numeric stat fields, sorted by JSON name;
+// the source row ID, season and age are not stats.
+func percentileFields(model interface{}) []statField {
+	s, err := schema.Parse(model, &percentileSchemas, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+	var out []statField
+	for _, f := range s.Fields {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case name == "" || name == "-" || f.DBName == "",
+			slices.Contains([]string{"id", "season", "age"}, name):
+			continue
+		}
+		switch f.DataType {
+		case schema.Int, schema.Uint, schema.Float:
+			out = append(out, statField{name, f})
+		}
+	}
+	slices.SortFunc(out, func(a, b statField) int { return strings.Compare(a.name, b.name) })
+	return out
+}
+
+// percentileRow is one stats row as the percentile computation sees it.
+type percentileRow struct {
+	id        uint
+	playerID  string
+	aggregate bool
+	position  string
+	games     int
+	minutes   float64
+	values    map[string]float64 // stat JSON name → value
+}
+
+// RefreshPercentiles rebuilds the percentile rows of one league season and
+// season type from the stored totals and advanced rows.
+func RefreshPercentiles(db *gorm.DB, league string, season int, isPlayoff bool) error {
+	scope := db.Where("league = ? AND season = ? AND is_playoff = ?", league, season, isPlayoff)
+
+	var totals []models.PlayerTotalStat
+	if err := scope.Session(&gorm.Session{}).Order("id").Find(&totals).Error; err != nil {
+		return err
+	}
+	var advanced []models.PlayerAdvancedStat
+	if err := scope.Session(&gorm.Session{}).Order("id").Find(&advanced).Error; err != nil {
+		return err
+	}
+
+	totalRows := percentileRows(totals, totalPercentileFields, func(s *models.PlayerTotalStat) percentileRow {
+		return percentileRow{
+			id: s.ID, playerID: s.PlayerID, aggregate: s.IsAggregate, position: s.Position,
+			games: s.Games, minutes: s.MinutesPG,
+		}
+	})
+	advancedRows := percentileRows(advanced, advancedPercentileFields, func(s *models.PlayerAdvancedStat) percentileRow {
+		return percentileRow{
+			id: s.ID, playerID: s.PlayerID, aggregate: s.IsAggregate, position: s.Position,
+			games: s.Games, minutes: float64(s.MinutesPlayed),
+		}
+	})
+	out := append(
+		computePercentiles(DatasetTotals, league, season, isPlayoff, totalRows, totalPercentileFields),
+		computePercentiles(DatasetAdvanced, league, season, isPlayoff, advancedRows, advancedPercentileFields)...,
+	)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("league = ? AND season = ? AND is_playoff = ?", league, season, isPlayoff).
+			Delete(&models.PlayerStatPercentile{}).Error; err != nil {
+			return err
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(out, 500).Error; err != nil {
+			return err
+		}
+		log.Printf("Rebuilt %d %s percentile rows for season %d (playoffs: %v)", len(out), league, season, isPlayoff)
+		return nil
+	})
+}
+
+// RefreshAllPercentiles rebuilds the percentiles of every stored season.
+func RefreshAllPercentiles(db *gorm.DB) error {
+	type scope struct {
+		League    string
+		Season    int
+		IsPlayoff bool
+	}
+	var scopes []scope
+	for _, model := range []interface{}{&models.PlayerTotalStat{}, &models.PlayerAdvancedStat{}} {
+		var found []scope
+		if err := db.Model(model).Distinct("league", "season", "is_playoff").Find(&found).Error; err != nil {
+			return err
+		}
+		for _, s := range found {
+			if !slices.Contains(scopes, s) {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	for _, s := range scopes {
+		if err := RefreshPercentiles(db, s.League, s.Season, s.IsPlayoff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PopulationQualification is the floor a row needs to count among the
+// qualified players of a league season; rate stats' percentiles also need
+// their leaderboard floor.
+func PopulationQualification(league string, season int, isPlayoff bool) Qualification {
+	return Qualification{MinMinutes: populationMinMinutes}.scaled(qualificationFactor(league, season, isPlayoff))
+}
+
+// LowestPopulationQualification is the loosest PopulationQualification of
+// any season of the league.
+func LowestPopulationQualification(league string, isPlayoff bool) Qualification {
+	return Qualification{MinMinutes: populationMinMinutes}.scaled(lowestQualificationFactor(league, isPlayoff))
+}
+
+// percentileRows reads fields off each row; base fills in the rest.
+func percentileRows[T any](rows []T, fields []statField, base func(*T) percentileRow) []percentileRow {
+	out := make([]percentileRow, len(rows))
+	for i := range rows {
+		out[i] = base(&rows[i])
+		out[i].values = make(map[string]float64, len(fields))
+		rv := reflect.ValueOf(&rows[i]).Elem()
+		for _, f := range fields {
+			out[i].values[f.name] = NumberValue(f.field.ReflectValueOf(context.Background(), rv).Interface())
+		}
+	}
+	return out
+}
+
+// computePercentiles ranks every row's stats against the qualified rows of
+// its season. The population has one row per player (a traded player's
+// aggregate row) meeting PopulationQualification and, for rate stats, the
+// stat's leaderboard floor. Every row, qualified or not, is placed in
+// that population, except for the stats NBA.com rows store 0 for: those
+// are neither counted nor ranked.
+func computePercentiles(dataset, league string, season int, isPlayoff bool, rows []percentileRow, fields []statField) []models.PlayerStatPercentile {
+	hasAggregate := map[string]bool{}
+	for _, r := range rows {
+		if r.aggregate {
+			hasAggregate[r.playerID] = true
+		}
+	}
+	minMinutes := PopulationQualification(league, season, isPlayoff)
+
+	// Sorted qualified values per stat, overall and per primary position.
+	overall := map[string][]float64{}
+	byPosition := map[string]map[string][]float64{}
+	for _, r := range rows {
+		if !r.aggregate && hasAggregate[r.playerID] || !minMinutes.Meets(r.games, r.minutes, 0) {
+			continue
+		}
+		pos := primaryPosition(r.position)
+		for _, f := range fields {
+			q := LeaderQualification(dataset, f.name, league, season, isPlayoff)
+			if !q.Meets(r.games, r.minutes, int(r.values[q.MadeField])) || !nbaStatsTracks(dataset, f.name, r.playerID) {
+				continue
+			}
+			v := r.values[f.name]
+			overall[f.name] = append(overall[f.name], v)
+			if pos != "" {
+				if byPosition[pos] == nil {
+					byPosition[pos] = map[string][]float64{}
+				}
+				byPosition[pos][f.name] = append(byPosition[pos][f.name], v)
+			}
+		}
+	}
+	for _, vs := range overall {
+		sort.Float64s(vs)
+	}
+	for _, stats := range byPosition {
+		for _, vs := range stats {
+			sort.Float64s(vs)
+		}
+	}
+
+	out := make([]models.PlayerStatPercentile, 0, len(rows))
+	for _, r := range rows {
+		pos := primaryPosition(r.position)
+		p := models.PlayerStatPercentile{
+			Dataset: dataset, RecordID: r.id, League: league, Season: season, IsPlayoff: isPlayoff,
+			Position: pos, Percentiles: map[string]float64{},
+		}
+		for _, f := range fields {
+			if !nbaStatsTracks(dataset, f.name, r.playerID) {
+				continue
+			}
+			v := r.values[f.name]
+			if vs := overall[f.name]; len(vs) > 0 {
+				p.Percentiles[f.name] = percentileOf(vs, v)
+			}
+			if vs := byPosition[pos][f.name]; len(vs) > 0 {
+				if p.PositionPercentiles == nil {
+					p.PositionPercentiles = map[string]float64{}
+				}
+				p.PositionPercentiles[f.name] = percentileOf(vs, v)
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// percentileOf is the share of sorted below v, counting ties as half,
+// on a 0–100 scale with one decimal.
+func percentileOf(sorted []float64, v float64) float64 {
+	below := sort.SearchFloat64s(sorted, v)
+	upTo := sort.Search(len(sorted), func(i int) bool { return sorted[i] > v })
+	return round1(100 * (float64(below) + float64(upTo-below)/2) / float64(len(sorted)))
+}
+
+// primaryPosition is the first of a listed position such as "SG-PG".
+func primaryPosition(pos string) string {
+	first, _, _ := strings.Cut(pos, "-")
+	return strings.ToUpper(strings.TrimSpace(first))
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestRefreshPercentiles(t *testing.T) {
+	db := newQualityTestDB(t)
+
+	totals := []models.PlayerTotalStat{
+		rankedTotal("a", "PG", "BOS", 70, 2000, 2000, 500, 0.50),
+		rankedTotal("b", "PG-SG", "BOS", 60, 1800, 1000, 310, 0.45),
+		rankedTotal("c", "C", "DEN", 70, 2100, 1500, 600, 0.60),
+		rankedTotal("d", "C", "DEN", 10, 100, 50, 20, 0.70),      // too few minutes to qualify
+		rankedTotal("e", "SG", "TOT", 60, 1500, 1200, 200, 0.55), // too few makes for FG%
+		rankedTotal("e", "SG", "NYK", 40, 1000, 1100, 150, 0.55),
+	}
+	totals[4].IsAggregate = true
+	require.NoError(t, db.Create(&totals).Error)
+	advanced := []models.PlayerAdvancedStat{
+		{League: models.LeagueNBA, PlayerID: "a", Team: "BOS", Season: 2024, Games: 70, MinutesPlayed: 2000, PER: 20},
+		{League: models.LeagueNBA, PlayerID: "b", Team: "BOS", Season: 2024, Games: 60, MinutesPlayed: 1800, PER: 15},
+		{League: models.LeagueNBA, PlayerID: "c", Team: "DEN", Season: 2024, Games: 70, MinutesPlayed: 400, PER: 25},
+		// NBA.com has no PER: the row is ranked on usage only.
+		{League: models.LeagueNBA, PlayerID: "1628983", Team: "OKC", Season: 2024, Games: 70, MinutesPlayed: 2500, UsagePercent: 30},
+	}
+	require.NoError(t, db.Create(&advanced).Error)
+
+	require.NoError(t, RefreshPercentiles(db, models.LeagueNBA, 2024, false))
+	require.NoError(t, RefreshPercentiles(db, models.LeagueNBA, 2024, false)) // replaces, never duplicates
+
+	var rows []models.PlayerStatPercentile
+	require.NoError(t, db.Order("dataset DESC, record_id").Find(&rows).Error)
+	require.Len(t, rows, 10)
+	byPlayer := map[string]models.PlayerStatPercentile{}
+	for i, r := range rows[:6] {
+		assert.Equal(t, DatasetTotals, r.Dataset)
+		assert.Equal(t, totals[i].ID, r.RecordID)
+		byPlayer[totals[i].PlayerID+totals[i].Team] = r
+	}
+
+	a := byPlayer["aBOS"]
+	assert.Equal(t, 87.5, a.Percentiles["points"])
+	assert.Equal(t, 75.0, a.PositionPercentiles["points"]) // among a and b
+	assert.Equal(t, "PG", byPlayer["bBOS"].Position)
+	assert.Equal(t, 0.0, byPlayer["dDEN"].Percentiles["points"])
+	assert.Equal(t, 100.0, byPlayer["dDEN"].Percentiles["fieldPercent"]) // placed, not counted
+	assert.Equal(t, 66.7, byPlayer["eTOT"].Percentiles["fieldPercent"])
+	// The traded player's team row is ranked but not counted.
+	assert.Equal(t, 25.0, byPlayer["eNYK"].Percentiles["points"])
+	assert.Equal(t, 0.0, byPlayer["eNYK"].PositionPercentiles["points"])
+	assert.NotContains(t, a.Percentiles, "age")
+
+	// Advanced PER needs 1500 minutes.
+	adv := rows[6:]
+	assert.Equal(t, DatasetAdvanced, adv[0].Dataset)
+	assert.Equal(t, 75.0, adv[0].Percentiles["per"])
+	assert.Equal(t, 100.0, adv[2].Percentiles["per"])
+	assert.NotContains(t, adv[3].Percentiles, "per")
+	assert.Equal(t, 83.3, adv[3].Percentiles["usagePercent"])
+}
@@ -16,42 +16,42 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-const (
-	advancedURLFmt        = "https://www.basketball-reference.com/leagues/NBA_%d_advanced.html"
-	advancedPlayoffURLFmt = "https://www.basketball-reference.com/playoffs/NBA_%d_advanced.html"
-)
-
-// urlForAdvSeason picks the correct URL based on isPlayoff.
-func urlForAdvSeason(season int, isPlayoff bool) string {
-	if isPlayoff {
-		return fmt.Sprintf(advancedPlayoffURLFmt, season)
-	}
-	return fmt.Sprintf(advancedURLFmt, season)
+// urlForAdvSeason picks the league's regular vs. playoff advanced URL.
+func urlForAdvSeason(baseURL, league string, season int, isPlayoff bool) (string, error) {
+	return brSeasonURL(baseURL, league, season, isPlayoff, "advanced")
 }
 
 // FetchAndStorePlayerAdvancedScrapedStats scrapes the advanced table (regular or playoffs)
 // and batch upserts the data into the PlayerAdvancedStat model.
 func FetchAndStorePlayerAdvancedScrapedStats(db *gorm.DB, season int, isPlayoff bool) error {
-	url := urlForAdvSeason(season, isPlayoff)
+	return ImportPlayerAdvanced(db, BRProvider{}, models.LeagueNBA, season, isPlayoff)
+}
+
+// scrapePlayerAdvanced parses the BR advanced table for one league-season.
+func scrapePlayerAdvanced(baseURL, league string, season int, isPlayoff bool) ([]models.PlayerAdvancedStat, error) {
+	url, err := urlForAdvSeason(baseURL, league, season, isPlayoff)
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible)")
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	htmlBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(htmlBytes))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 1) Determine parent wrapper and table selector. This logic is complex because
@@ -75,17 +75,17 @@ func FetchAndStorePlayerAdvancedScrapedStats(db *gorm.DB, season int, isPlayoff
 			})
 
 		if commentSel.Length() == 0 {
-			return fmt.Errorf("could not find advanced stats table (even inside comment) for season %d", season)
+			return nil, fmt.Errorf("could not find advanced stats table (even inside comment) for season %d", season)
 		}
 
 		commentedHTML := commentSel.Nodes[0].FirstChild.Data
 		innerDoc, err := goquery.NewDocumentFromReader(strings.NewReader(commentedHTML))
 		if err != nil {
-			return fmt.Errorf("failed to parse commented advanced HTML for season %d: %w", season, err)
+			return nil, fmt.Errorf("failed to parse commented advanced HTML for season %d: %w", season, err)
 		}
 		table = innerDoc.Find(tableSelector)
 		if table.Length() == 0 {
-			return fmt.Errorf("could not find advanced stats table after un-commenting for season %d", season)
+			return nil, fmt.Errorf("could not find advanced stats table after un-commenting for season %d", season)
 		}
 	}
 
@@ -138,6 +138,9 @@ func FetchAndStorePlayerAdvancedScrapedStats(db *gorm.DB, season int, isPlayoff
 		if teamID == "" {
 			teamID = data["team_name_abbr"]
 		}
+		// Traded players get one aggregate row ("TOT", or "2TM"/"3TM" on
+		// newer pages) on top of their per-team rows.
+		teamID, isAggregate := normalizeTeam(teamID)
 
 		// 5) Games column is always "g" in advanced tables.
 		g := mustAtoi(data["games"])
@@ -147,6 +150,7 @@ func FetchAndStorePlayerAdvancedScrapedStats(db *gorm.DB, season int, isPlayoff
 
 		// 6) Map into your GORM model.
 		stat := models.PlayerAdvancedStat{
+			League:             league,
 			ExternalID:         extID,
 			PlayerID:           playerID,
 			PlayerName:         playerName,
@@ -177,32 +181,44 @@ func FetchAndStorePlayerAdvancedScrapedStats(db *gorm.DB, season int, isPlayoff
 			Team:               teamID,
 			Season:             season,
 			IsPlayoff:          isPlayoff,
+			IsAggregate:        isAggregate,
 		}
 
 		// Add the parsed stat object to our slice.
 		statsToUpsert = append(statsToUpsert, stat)
 	})
 
+	return statsToUpsert, nil
+}
+
+// storePlayerAdvanced batch upserts one season of advanced stats, behind the data quality gate.
+func storePlayerAdvanced(db *gorm.DB, statsToUpsert []models.PlayerAdvancedStat, league string, season int, isPlayoff bool) error {
 	// 7) Perform the batch upsert operation after collecting all rows.
 	if len(statsToUpsert) > 0 {
-		log.Printf("Attempting to batch upsert %d advanced player stats for season %d...", len(statsToUpsert), season)
-
-		if err := db.Clauses(clause.OnConflict{
-			Columns: []clause.Column{
-				{Name: "player_id"},
-				{Name: "season"},
-				{Name: "team"},
-				{Name: "is_playoff"},
-			},
-			DoUpdates: clause.AssignmentColumns([]string{
-				"external_id", "player_name", "position", "age", "games",
-				"minutes_played", "per", "ts_percent", "three_par", "ftr",
-				"offensive_rb_percent", "defensive_rb_percent", "total_rb_percent",
-				"assist_percent", "steal_percent", "block_percent", "turnover_percent",
-				"usage_percent", "offensive_ws", "defensive_ws", "win_shares",
-				"win_shares_per", "offensive_box", "defensive_box", "box", "vorp",
-			}),
-		}).Create(&statsToUpsert).Error; err != nil {
+		log.Printf("Attempting to batch upsert %d %s advanced player stats for season %d...", len(statsToUpsert), league, season)
+
+		// Upsert and validate in one transaction; a strict import that fails
+		// the data quality gate leaves the season untouched.
+		if err := commitWithQualityGate(db, DatasetAdvanced, league, season, isPlayoff, func(tx *gorm.DB) error {
+			return tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{
+					{Name: "league"},
+					{Name: "player_id"},
+					{Name: "season"},
+					{Name: "team"},
+					{Name: "is_playoff"},
+				},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"external_id", "player_name", "position", "age", "games",
+					"minutes_played", "per", "ts_percent", "three_par", "ftr",
+					"offensive_rb_percent", "defensive_rb_percent", "total_rb_percent",
+					"assist_percent", "steal_percent", "block_percent", "turnover_percent",
+					"usage_percent", "offensive_ws", "defensive_ws", "win_shares",
+					"win_shares_per", "offensive_box", "defensive_box", "box", "vorp",
+					"is_aggregate",
+				}),
+			}).Create(&statsToUpsert).Error
+		}); err != nil {
 			log.Printf("Failed to batch upsert advanced player stats: %v", err)
 			return err
 		}
@@ -213,4 +229,4 @@ func FetchAndStorePlayerAdvancedScrapedStats(db *gorm.DB, season int, isPlayoff
 	// --- BATCHING LOGIC END ---
 
 	return nil
-}
\ No newline at end of file
+}
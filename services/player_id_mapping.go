@@ -0,0 +1,501 @@
+package services
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrMappingNotFound  = errors.New("player id mapping not found")
+	ErrMappingConflict  = errors.New("player already has a confirmed mapping to a different id")
+	ErrUnmappedPlayerID = errors.New("no confirmed id mapping for player")
+)
+
+// Inference weights; a match is the sum of the three signals.
+const (
+	mappingNameWeight = 0.4
+	mappingAgeWeight  = 0.3
+	mappingTeamWeight = 0.3
+
+	// AutoConfirmConfidence is the score at which a lone candidate is
+	// confirmed without review.
+	AutoConfirmConfidence = 0.85
+)
+
+// MappingInference summarises one inference run.
+type MappingInference struct {
+	Confirmed int `json:"confirmed"`
+	Pending   int `json:"pending"`
+	Unmatched int `json:"unmatched"`
+}
+
+// PlayerIDForProvider translates id into p's ID style via a confirmed mapping.
+func PlayerIDForProvider(db *gorm.DB, id string, p StatsProvider) (string, error) {
+	if p.OwnsPlayerID(id) {
+		return id, nil
+	}
+	ids, err := repository.NewGorm(db).PlayerIDs.ResolvePlayerIDs(id)
+	if err != nil {
+		return "", err
+	}
+	for _, other := range ids[1:] {
+		if p.OwnsPlayerID(other) {
+			return other, nil
+		}
+	}
+	return "", fmt.Errorf("%w %s (provider %s)", ErrUnmappedPlayerID, id, p.Name())
+}
+
+// ImportPlayerIDMappingsCSV loads confirmed mappings from CSV with a header
+// row. br_id and nba_id are required; name and birth_date (YYYY-MM-DD) are
+// optional. CSV rows are authoritative: any other confirmed or pending row
+// for either ID is rejected, and an ID may appear on only one line.
+func ImportPlayerIDMappingsCSV(db *gorm.DB, r io.Reader) (int, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return 0, fmt.Errorf("read csv header: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := col["br_id"]; !ok {
+		return 0, errors.New("csv header must include br_id and nba_id")
+	}
+	if _, ok := col["nba_id"]; !ok {
+		return 0, errors.New("csv header must include br_id and nba_id")
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var mappings []models.PlayerIDMapping
+	seenBR := make(map[string]int)
+	seenNBA := make(map[string]int)
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("csv line %d: %w", line, err)
+		}
+		m := models.PlayerIDMapping{
+			BRID:       field(rec, "br_id"),
+			NBAID:      field(rec, "nba_id"),
+			PlayerName: field(rec, "name"),
+			Source:     models.MappingSourceCSV,
+			Confidence: 1,
+			Status:     models.MappingConfirmed,
+		}
+		if m.BRID == "" || isNumericID(m.BRID) {
+			return 0, fmt.Errorf("csv line %d: br_id %q is not a Basketball-Reference id", line, m.BRID)
+		}
+		if !isNumericID(m.NBAID) {
+			return 0, fmt.Errorf("csv line %d: nba_id %q is not an NBA.com person id", line, m.NBAID)
+		}
+		if bd := field(rec, "birth_date"); bd != "" {
+			t, err := time.Parse("2006-01-02", bd)
+			if err != nil {
+				return 0, fmt.Errorf("csv line %d: birth_date %q: want YYYY-MM-DD", line, bd)
+			}
+			m.BirthDate = &t
+		}
+		if prev, ok := seenBR[m.BRID]; ok {
+			return 0, fmt.Errorf("csv line %d: br_id %s already mapped on line %d", line, m.BRID, prev)
+		}
+		if prev, ok := seenNBA[m.NBAID]; ok {
+			return 0, fmt.Errorf("csv line %d: nba_id %s already mapped on line %d", line, m.NBAID, prev)
+		}
+		seenBR[m.BRID], seenNBA[m.NBAID] = line, line
+		mappings = append(mappings, m)
+	}
+	if len(mappings) == 0 {
+		return 0, nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Clear the way first: each ID has at most one confirmed row.
+		for _, m := range mappings {
+			if err := rejectCompeting(tx, m); err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "br_id"}, {Name: "nba_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"player_name", "birth_date", "source", "confidence", "status", "reason", "updated_at",
+			}),
+		}).Create(&mappings).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(mappings), nil
+}
+
+// InferPlayerIDMappings matches NBA.com players to Basketball-Reference
+// players from the stored NBA totals. Candidates must share a folded name.
+// A birth date recorded on any mapping row for either ID must agree with
+// both IDs' stored ages; without one, ages in overlapping seasons stand in
+// for it, since neither stats source stores birth dates. Candidates are
+// also scored on team-season overlap. A lone candidate at
+// AutoConfirmConfidence or above, claimed by no other NBA.com player, is
+// confirmed; everything else is queued as pending for review. Reviewed
+// rows are never overwritten.
+func InferPlayerIDMappings(db *gorm.DB) (MappingInference, error) {
+	var result MappingInference
+
+	profiles, err := loadPlayerProfiles(db)
+	if err != nil {
+		return result, err
+	}
+
+	var existing []models.PlayerIDMapping
+	if err := db.Find(&existing).Error; err != nil {
+		return result, err
+	}
+	mappedBR := make(map[string]bool)
+	mappedNBA := make(map[string]bool)
+	rejected := make(map[[2]string]bool)
+	born := make(map[string]time.Time)
+	for _, m := range existing {
+		if m.BirthDate != nil {
+			born[m.BRID], born[m.NBAID] = *m.BirthDate, *m.BirthDate
+		}
+		switch m.Status {
+		case models.MappingConfirmed:
+			mappedBR[m.BRID] = true
+			mappedNBA[m.NBAID] = true
+		case models.MappingRejected:
+			rejected[[2]string{m.BRID, m.NBAID}] = true
+		}
+	}
+
+	byName := make(map[string][]*playerProfile)
+	var nbaPlayers []*playerProfile
+	for _, p := range profiles {
+		if d, ok := born[p.id]; ok {
+			p.born = &d
+		}
+		if isNumericID(p.id) {
+			if !mappedNBA[p.id] {
+				nbaPlayers = append(nbaPlayers, p)
+			}
+		} else if !mappedBR[p.id] {
+			byName[p.folded] = append(byName[p.folded], p)
+		}
+	}
+
+	type candidate struct {
+		br     *playerProfile
+		score  float64
+		reason string
+	}
+	candidates := make(map[*playerProfile][]candidate)
+	claims := make(map[string]int) // BR id → NBA.com players it is a candidate for
+	for _, nba := range nbaPlayers {
+		for _, br := range byName[nba.folded] {
+			if rejected[[2]string{br.id, nba.id}] {
+				continue
+			}
+			score, reason, ok := matchConfidence(nba, br)
+			if !ok {
+				continue
+			}
+			candidates[nba] = append(candidates[nba], candidate{br, score, reason})
+			claims[br.id]++
+		}
+	}
+
+	var mappings []models.PlayerIDMapping
+	for _, nba := range nbaPlayers {
+		cands := candidates[nba]
+		if len(cands) == 0 {
+			result.Unmatched++
+			continue
+		}
+		for _, c := range cands {
+			status := models.MappingPending
+			if len(cands) == 1 && claims[c.br.id] == 1 && c.score >= AutoConfirmConfidence {
+				status = models.MappingConfirmed
+				result.Confirmed++
+			} else {
+				result.Pending++
+			}
+			birthDate := nba.born
+			if birthDate == nil {
+				birthDate = c.br.born
+			}
+			mappings = append(mappings, models.PlayerIDMapping{
+				BRID:       c.br.id,
+				NBAID:      nba.id,
+				PlayerName: c.br.name,
+				BirthDate:  birthDate,
+				Source:     models.MappingSourceInferred,
+				Confidence: c.score,
+				Status:     status,
+				Reason:     c.reason,
+			})
+		}
+	}
+	if len(mappings) == 0 {
+		return result, nil
+	}
+
+	// Only pending rows are refreshed; confirmed and rejected pairs keep
+	// their reviewed state.
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "br_id"}, {Name: "nba_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"player_name", "birth_date", "confidence", "status", "reason", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: "player_id_mappings", Name: "status"}, Value: models.MappingPending},
+		}},
+	}).CreateInBatches(&mappings, 500).Error
+	if err != nil {
+		return result, err
+	}
+	log.Printf("🔗 Player ID inference: %d confirmed, %d pending, %d unmatched",
+		result.Confirmed, result.Pending, result.Unmatched)
+	return result, nil
+}
+
+// ListPlayerIDMappings returns mappings, optionally filtered by status,
+// highest confidence first.
+func ListPlayerIDMappings(db *gorm.DB, status string, limit int) ([]models.PlayerIDMapping, error) {
+	query := db.Model(&models.PlayerIDMapping{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+	var mappings []models.PlayerIDMapping
+	err := query.Order("confidence DESC").Order("id").Limit(limit).Find(&mappings).Error
+	return mappings, err
+}
+
+// ReviewPlayerIDMapping confirms or rejects a queued mapping. Confirming
+// rejects the other pending candidates for either ID.
+func ReviewPlayerIDMapping(db *gorm.DB, id uint, approve bool) (models.PlayerIDMapping, error) {
+	var m models.PlayerIDMapping
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&m, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMappingNotFound
+			}
+			return err
+		}
+		if !approve {
+			m.Status = models.MappingRejected
+			return tx.Model(&m).Update("status", m.Status).Error
+		}
+
+		var clash int64
+		if err := tx.Model(&models.PlayerIDMapping{}).
+			Where("status = ? AND id <> ? AND (br_id = ? OR nba_id = ?)", models.MappingConfirmed, m.ID, m.BRID, m.NBAID).
+			Count(&clash).Error; err != nil {
+			return err
+		}
+		if clash > 0 {
+			return ErrMappingConflict
+		}
+		m.Status = models.MappingConfirmed
+		if err := tx.Model(&m).Update("status", m.Status).Error; err != nil {
+			return err
+		}
+		return rejectCompeting(tx, m)
+	})
+	return m, err
+}
+
+// rejectCompeting rejects pending and confirmed rows that pair either side
+// of m with someone else.
+func rejectCompeting(tx *gorm.DB, m models.PlayerIDMapping) error {
+	return tx.Model(&models.PlayerIDMapping{}).
+		Where("status IN ? AND (br_id = ? OR nba_id = ?) AND NOT (br_id = ? AND nba_id = ?)",
+			[]string{models.MappingPending, models.MappingConfirmed}, m.BRID, m.NBAID, m.BRID, m.NBAID).
+		Update("status", models.MappingRejected).Error
+}
+
+// ─────────────────────────  inference helpers  ─────────────────────────
+
+// playerProfile is what the totals table knows about one player ID.
+type playerProfile struct {
+	id     string
+	name   string
+	folded string
+	ages   map[int]int     // season → age
+	stints map[string]bool // "season/team"
+	born   *time.Time      // from a mapping row, if any
+}
+
+func loadPlayerProfiles(db *gorm.DB) ([]*playerProfile, error) {
+	var rows []struct {
+		PlayerID   string
+		PlayerName string
+		Season     int
+		Team       string
+		Age        int
+	}
+	err := db.Model(&models.PlayerTotalStat{}).
+		Select("player_id, player_name, season, team, age").
+		Where("league = ? AND is_playoff = ? AND is_aggregate = ?", models.LeagueNBA, false, false).
+		Order("player_id").Order("season").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*playerProfile)
+	var profiles []*playerProfile
+	for _, r := range rows {
+		p, ok := byID[r.PlayerID]
+		if !ok {
+			p = &playerProfile{id: r.PlayerID, ages: map[int]int{}, stints: map[string]bool{}}
+			byID[r.PlayerID] = p
+			profiles = append(profiles, p)
+		}
+		// Latest season's spelling wins.
+		p.name, p.folded = r.PlayerName, FoldName(r.PlayerName)
+		if r.Age > 0 {
+			p.ages[r.Season] = r.Age
+		}
+		p.stints[fmt.Sprintf("%d/%s", r.Season, r.Team)] = true
+	}
+	return profiles, nil
+}
+
+// matchConfidence scores a same-name candidate pair and explains the
+// score. ok is false when a known birth date rules the pair out.
+func matchConfidence(nba, br *playerProfile) (score float64, reason string, ok bool) {
+	score = mappingNameWeight
+	reasons := []string{"name"}
+
+	// A known birth date is a required match: the other ID's stored ages
+	// must agree with it, give or take the sources' reference dates.
+	if nba.born != nil && br.born != nil {
+		if !nba.born.Equal(*br.born) {
+			return 0, "", false
+		}
+		score += mappingAgeWeight
+		reasons = append(reasons, "birth date")
+	} else if d := cmp.Or(nba.born, br.born); d != nil {
+		for _, ages := range []map[int]int{nba.ages, br.ages} {
+			for season, age := range ages {
+				if diff := age - ageOn(*d, season); diff < -1 || diff > 1 {
+					return 0, "", false
+				}
+			}
+		}
+		score += mappingAgeWeight
+		reasons = append(reasons, "birth date")
+	} else {
+		score, reasons = ageProxy(nba, br, score, reasons)
+	}
+
+	// NBA.com lists a traded player under his latest team only, so measure
+	// overlap against the NBA.com stints.
+	if len(nba.stints) > 0 {
+		var overlap int
+		for stint := range nba.stints {
+			if br.stints[stint] {
+				overlap++
+			}
+		}
+		if overlap > 0 {
+			score += mappingTeamWeight * float64(overlap) / float64(len(nba.stints))
+			reasons = append(reasons, fmt.Sprintf("teams %d/%d", overlap, len(nba.stints)))
+		}
+	}
+	return round3(score), strings.Join(reasons, ", "), true
+}
+
+// ageProxy scores ages in shared seasons in place of a birth date. BR and
+// NBA.com use different reference dates, so an off-by-one still counts for
+// half.
+func ageProxy(nba, br *playerProfile, score float64, reasons []string) (float64, []string) {
+	var shared, exact, near int
+	for season, age := range nba.ages {
+		brAge, ok := br.ages[season]
+		if !ok {
+			continue
+		}
+		shared++
+		switch d := age - brAge; {
+		case d == 0:
+			exact++
+		case d == 1 || d == -1:
+			near++
+		}
+	}
+	switch {
+	case shared > 0 && exact == shared:
+		score += mappingAgeWeight
+		reasons = append(reasons, "age")
+	case shared > 0 && exact+near == shared:
+		score += mappingAgeWeight / 2
+		reasons = append(reasons, "age±1")
+	}
+	return score, reasons
+}
+
+// ageOn is a player's age on February 1 of the year a season ends, the
+// date Basketball-Reference lists season ages at.
+func ageOn(born time.Time, season int) int {
+	age := season - born.Year()
+	if born.Month() > time.February || (born.Month() == time.February && born.Day() > 1) {
+		age--
+	}
+	return age
+}
+
+// nameSuffixes are dropped when folding names.
+var nameSuffixes = map[string]bool{"jr": true, "sr": true, "ii": true, "iii": true, "iv": true}
+
+// foldExtra covers letters that have no Unicode decomposition and drops
+// in-word punctuation, so "J.J." and "JJ" fold alike.
+var foldExtra = strings.NewReplacer("ø", "o", "đ", "d", "ł", "l", "ß", "ss", "æ", "ae", "ı", "i", ".", "", "'", "", "’", "")
+
+// FoldName normalises a player name for matching: accents stripped,
+// lower-cased, punctuation and generational suffixes removed.
+// "Luka Dončić" and "Luka Doncic" fold to "luka doncic".
+func FoldName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(name))
+	if err != nil {
+		folded = strings.ToLower(name)
+	}
+	folded = foldExtra.Replace(folded)
+
+	var words []string
+	for _, w := range strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !nameSuffixes[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
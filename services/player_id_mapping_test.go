@@ -0,0 +1,198 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+func newMappingTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.PlayerTotalStat{}, &models.PlayerIDMapping{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func seedTotal(t *testing.T, db *gorm.DB, pid, name, team string, season, age int) {
+	t.Helper()
+	require.NoError(t, db.Create(&models.PlayerTotalStat{
+		PlayerID: pid, PlayerName: name, Team: team, Season: season, Age: age,
+	}).Error)
+}
+
+func TestFoldName(t *testing.T) {
+	cases := map[string]string{
+		"Luka Dončić":           "luka doncic",
+		"Nikola Jokić":          "nikola jokic",
+		"J.J. Redick":           "jj redick",
+		"Gary Trent Jr.":        "gary trent",
+		"Marcus Morris Sr.":     "marcus morris",
+		"D'Angelo Russell":      "dangelo russell",
+		"Karl-Anthony Towns":    "karl anthony towns",
+		"  Kristaps  Porziņģis": "kristaps porzingis",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, FoldName(in), in)
+	}
+}
+
+func TestInferPlayerIDMappings(t *testing.T) {
+	db := newMappingTestDB(t)
+
+	// One clear match across two seasons.
+	seedTotal(t, db, "doncilu01", "Luka Dončić", "DAL", 2023, 23)
+	seedTotal(t, db, "doncilu01", "Luka Dončić", "DAL", 2024, 24)
+	seedTotal(t, db, "1629029", "Luka Doncic", "DAL", 2023, 24)
+	seedTotal(t, db, "1629029", "Luka Doncic", "DAL", 2024, 25)
+
+	// Two BR players share the name: ambiguous, goes to review.
+	seedTotal(t, db, "johnsma01", "Marcus Johnson", "BOS", 2024, 27)
+	seedTotal(t, db, "johnsma02", "Marcus Johnson", "UTA", 2024, 22)
+	seedTotal(t, db, "1630001", "Marcus Johnson", "UTA", 2024, 22)
+
+	// No BR counterpart.
+	seedTotal(t, db, "1630002", "Nobody Here", "MIA", 2024, 20)
+
+	result, err := InferPlayerIDMappings(db)
+	require.NoError(t, err)
+	assert.Equal(t, MappingInference{Confirmed: 1, Pending: 2, Unmatched: 1}, result)
+
+	ids, err := repository.NewGorm(db).PlayerIDs.ResolvePlayerIDs("1629029")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1629029", "doncilu01"}, ids)
+
+	queue, err := ListPlayerIDMappings(db, models.MappingPending, 0)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, "johnsma02", queue[0].BRID, "the age and team match ranks first")
+	assert.Greater(t, queue[0].Confidence, queue[1].Confidence)
+
+	// Approving one candidate rejects the other.
+	_, err = ReviewPlayerIDMapping(db, queue[0].ID, true)
+	require.NoError(t, err)
+	var other models.PlayerIDMapping
+	require.NoError(t, db.First(&other, queue[1].ID).Error)
+	assert.Equal(t, models.MappingRejected, other.Status)
+
+	// Re-running keeps reviewed rows untouched.
+	result, err = InferPlayerIDMappings(db)
+	require.NoError(t, err)
+	assert.Equal(t, MappingInference{Unmatched: 1}, result)
+
+	ids, err = repository.NewGorm(db).PlayerIDs.ResolvePlayerIDs("johnsma02")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"johnsma02", "1630001"}, ids)
+}
+
+func TestImportPlayerIDMappingsCSV(t *testing.T) {
+	db := newMappingTestDB(t)
+
+	csvData := "br_id,nba_id,name,birth_date\n" +
+		"hardeja01,201935,James Harden,1989-08-26\n" +
+		"curryst01,201939,Stephen Curry,\n"
+	n, err := ImportPlayerIDMappingsCSV(db, strings.NewReader(csvData))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// Re-importing upserts.
+	n, err = ImportPlayerIDMappingsCSV(db, strings.NewReader(csvData))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	var count int64
+	db.Model(&models.PlayerIDMapping{}).Count(&count)
+	assert.EqualValues(t, 2, count)
+
+	nba := NewNBAStatsProvider()
+	id, err := PlayerIDForProvider(db, "hardeja01", nba)
+	require.NoError(t, err)
+	assert.Equal(t, "201935", id)
+	id, err = PlayerIDForProvider(db, "201939", BRProvider{})
+	require.NoError(t, err)
+	assert.Equal(t, "curryst01", id)
+	_, err = PlayerIDForProvider(db, "jamesle01", nba)
+	assert.ErrorIs(t, err, ErrUnmappedPlayerID)
+
+	_, err = ImportPlayerIDMappingsCSV(db, strings.NewReader("br_id,nba_id\n201935,hardeja01\n"))
+	assert.ErrorContains(t, err, "line 2")
+	_, err = ImportPlayerIDMappingsCSV(db, strings.NewReader("player,nba\nx,1\n"))
+	assert.Error(t, err)
+}
+
+func TestImportPlayerIDMappingsCSVOverridesConfirmed(t *testing.T) {
+	db := newMappingTestDB(t)
+	require.NoError(t, db.Create(&models.PlayerIDMapping{
+		BRID: "smithja04", NBAID: "1630174", Source: models.MappingSourceInferred, Confidence: 0.9, Status: models.MappingConfirmed,
+	}).Error)
+
+	n, err := ImportPlayerIDMappingsCSV(db, strings.NewReader("br_id,nba_id\nsmithja04,1629000\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	var inferred models.PlayerIDMapping
+	require.NoError(t, db.Where("nba_id = ?", "1630174").First(&inferred).Error)
+	assert.Equal(t, models.MappingRejected, inferred.Status)
+
+	_, err = ImportPlayerIDMappingsCSV(db, strings.NewReader("br_id,nba_id\nsmithja04,1629000\nsmithja04,1629001\n"))
+	assert.ErrorContains(t, err, "line 3")
+
+	// The database holds one confirmed row per ID whatever the caller.
+	err = db.Create(&models.PlayerIDMapping{
+		BRID: "smithja04", NBAID: "1629002", Source: models.MappingSourceManual, Confidence: 1, Status: models.MappingConfirmed,
+	}).Error
+	assert.Error(t, err)
+}
+
+func TestInferPlayerIDMappingsBirthDate(t *testing.T) {
+	db := newMappingTestDB(t)
+	born := func(s string) *time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return &d
+	}
+	// Birth dates come from earlier mapping rows, here rejected ones.
+	require.NoError(t, db.Create(&[]models.PlayerIDMapping{
+		{BRID: "smithja04", NBAID: "1", BirthDate: born("2000-03-26"), Source: models.MappingSourceCSV, Status: models.MappingRejected},
+		{BRID: "brownbr01", NBAID: "2", BirthDate: born("1980-01-01"), Source: models.MappingSourceCSV, Status: models.MappingRejected},
+	}).Error)
+
+	// Born March 2000: 23 on Feb 1, 2024. Name, birth date and team.
+	seedTotal(t, db, "smithja04", "Jalen Smith", "CHI", 2024, 23)
+	seedTotal(t, db, "1630188", "Jalen Smith", "CHI", 2024, 24)
+	// A 1980 birth date rules out a 25-year-old.
+	seedTotal(t, db, "brownbr01", "Bruce Brown", "TOR", 2024, 44)
+	seedTotal(t, db, "1628971", "Bruce Brown", "TOR", 2024, 27)
+
+	result, err := InferPlayerIDMappings(db)
+	require.NoError(t, err)
+	assert.Equal(t, MappingInference{Confirmed: 1, Unmatched: 1}, result)
+
+	var m models.PlayerIDMapping
+	require.NoError(t, db.Where("nba_id = ?", "1630188").First(&m).Error)
+	assert.Equal(t, models.MappingConfirmed, m.Status)
+	assert.Equal(t, "name, birth date, teams 1/1", m.Reason)
+	require.NotNil(t, m.BirthDate)
+	assert.Equal(t, "2000-03-26", m.BirthDate.Format(time.DateOnly))
+}
+
+func TestInferPlayerIDMappingsSharedCandidate(t *testing.T) {
+	db := newMappingTestDB(t)
+
+	// Two NBA.com IDs match the same lone BR player: neither is confirmed.
+	seedTotal(t, db, "greenda01", "Danny Green", "PHI", 2023, 35)
+	seedTotal(t, db, "201980", "Danny Green", "PHI", 2023, 35)
+	seedTotal(t, db, "1699999", "Danny Green", "PHI", 2023, 35)
+
+	result, err := InferPlayerIDMappings(db)
+	require.NoError(t, err)
+	assert.Equal(t, MappingInference{Pending: 2}, result)
+}
@@ -0,0 +1,199 @@
+// File: services/player_search.go
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+	"gorm.io/gorm"
+)
+
+// ErrEmptySearch is returned for a query with nothing left after folding.
+var ErrEmptySearch = errors.New("search query is empty")
+
+// Relevance tiers for player search; fuzzy matches score below every
+// exact, prefix or substring match.
+const (
+	searchExact     = 1.0
+	searchPrefix    = 0.95
+	searchWords     = 0.9
+	searchSubstring = 0.8
+	searchFuzzy     = 0.75 // scaled by the edit-distance similarity
+	// searchMinSimilarity is the lowest average word similarity a fuzzy
+	// match needs: one typo in a five-letter word is 0.8.
+	searchMinSimilarity = 0.7
+	// searchMinFuzzyLen keeps very short words from fuzzy-matching everything.
+	searchMinFuzzyLen = repository.SearchMinFuzzyLen
+)
+
+// PlayerSearchResult is one player matched by SearchPlayers.
+type PlayerSearchResult struct {
+	PlayerID    string  `json:"playerId"`
+	PlayerName  string  `json:"playerName"`
+	League      string  `json:"league"`
+	FirstSeason int     `json:"firstSeason"`
+	LastSeason  int     `json:"lastSeason"`
+	Minutes     float64 `json:"minutes"` // career minutes, regular season and playoffs
+	Score       float64 `json:"score"`   // relevance, 0–1
+}
+
+// SearchPlayers finds players in a league by name. Matching ignores
+// accents, case and punctuation, accepts prefixes ("jok") and tolerates
+// typos ("jokc"); results are ranked by relevance, then career minutes.
+// SQL narrows the career table to candidate names and Go scores those, so
+// the ranking is the same on every driver.
+func SearchPlayers(db *gorm.DB, league, q string, limit int) ([]PlayerSearchResult, error) {
+	query := FoldName(q)
+	if query == "" {
+		return nil, ErrEmptySearch
+	}
+	rows, err := repository.NewGorm(db).Careers.SearchCandidates(league, query)
+	if err != nil {
+		return nil, err
+	}
+	return RankPlayerSearch(query, league, rows, limit), nil
+}
+
+// RankPlayerSearch scores career rows against a folded query and returns
+// the best limit players; a player's rows are merged into one result.
+// Rows need only be a superset of the matches.
+func RankPlayerSearch(query, league string, rows []models.PlayerCareerStat, limit int) []PlayerSearchResult {
+	byPlayer := map[string]*PlayerSearchResult{}
+	var results []*PlayerSearchResult
+	for _, r := range rows {
+		if res, ok := byPlayer[r.PlayerID]; ok {
+			res.Minutes += r.Minutes
+			res.FirstSeason = min(res.FirstSeason, r.FirstSeason)
+			res.LastSeason = max(res.LastSeason, r.LastSeason)
+			continue
+		}
+		score := nameScore(query, r.SearchName)
+		if score == 0 {
+			continue
+		}
+		res := &PlayerSearchResult{
+			PlayerID:    r.PlayerID,
+			PlayerName:  r.PlayerName,
+			League:      league,
+			FirstSeason: r.FirstSeason,
+			LastSeason:  r.LastSeason,
+			Minutes:     r.Minutes,
+			Score:       round3(score),
+		}
+		byPlayer[r.PlayerID] = res
+		results = append(results, res)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Minutes != b.Minutes {
+			return a.Minutes > b.Minutes
+		}
+		return a.PlayerID < b.PlayerID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	out := make([]PlayerSearchResult, len(results))
+	for i, r := range results {
+		out[i] = *r
+	}
+	return out
+}
+
+// nameScore rates how well a folded query matches a folded name; 0 is no match.
+func nameScore(query, name string) float64 {
+	switch {
+	case name == "":
+		return 0
+	case name == query:
+		return searchExact
+	case strings.HasPrefix(name, query):
+		return searchPrefix
+	}
+
+	qWords, nWords := strings.Fields(query), strings.Fields(name)
+	if wordsArePrefixes(qWords, nWords) {
+		return searchWords
+	}
+	if strings.Contains(name, query) {
+		return searchSubstring
+	}
+
+	var total float64
+	for _, qw := range qWords {
+		best := 0.0
+		for _, nw := range nWords {
+			best = max(best, wordSimilarity(qw, nw))
+		}
+		total += best
+	}
+	if sim := total / float64(len(qWords)); sim >= searchMinSimilarity {
+		return searchFuzzy * sim
+	}
+	return 0
+}
+
+// wordsArePrefixes reports whether every query word starts a different
+// name word, in any order ("jokic nik" → "nikola jokic").
+func wordsArePrefixes(qWords, nWords []string) bool {
+	used := make([]bool, len(nWords))
+	for _, qw := range qWords {
+		found := false
+		for i, nw := range nWords {
+			if !used[i] && strings.HasPrefix(nw, qw) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// wordSimilarity is 1 minus the edit distance over the longer length, taken
+// against the whole word or, for typed prefixes, its first len(q)+1 runes.
+func wordSimilarity(q, word string) float64 {
+	qr, wr := []rune(q), []rune(word)
+	if len(qr) < searchMinFuzzyLen {
+		return 0
+	}
+	sim := func(a, b []rune) float64 {
+		return 1 - float64(editDistance(a, b))/float64(max(len(a), len(b)))
+	}
+	best := sim(qr, wr)
+	if n := len(qr) + 1; n < len(wr) {
+		best = max(best, sim(qr, wr[:n]))
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
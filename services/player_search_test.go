@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+func TestSearchPlayers(t *testing.T) {
+	db := newQualityTestDB(t)
+	require.NoError(t, db.Create(&[]models.PlayerTotalStat{
+		{PlayerID: "jokicni01", PlayerName: "Nikola Jokić", Team: "DEN", Season: 2024, MinutesPG: 2737},
+		{PlayerID: "doncilu01", PlayerName: "Luka Dončić", Team: "DAL", Season: 2024, MinutesPG: 2624},
+		{PlayerID: "jamesle01", PlayerName: "LeBron James", Team: "LAL", Season: 2024, MinutesPG: 2504},
+		{PlayerID: "jamesle01", PlayerName: "LeBron James", Team: "LAL", Season: 2024, MinutesPG: 200, IsPlayoff: true},
+		{PlayerID: "jamesbr02", PlayerName: "Bronny James", Team: "LAL", Season: 2025, MinutesPG: 52},
+		{PlayerID: "jamesmi02", PlayerName: "Mike James", Team: "PHO", Season: 2018, MinutesPG: 640},
+		{PlayerID: "antetgi01", PlayerName: "Giannis Antetokounmpo", Team: "MIL", Season: 2024, MinutesPG: 2567},
+		{League: models.LeagueWNBA, PlayerID: "jonesjo01w", PlayerName: "Jonquel Jones", Team: "NYL", Season: 2024},
+	}).Error)
+	require.NoError(t, RebuildAllCareerStats(db))
+
+	ids := func(results []PlayerSearchResult) []string {
+		out := make([]string, len(results))
+		for i, r := range results {
+			out[i] = r.PlayerID
+		}
+		return out
+	}
+
+	cases := []struct {
+		q    string
+		want []string
+	}{
+		{"Jokic", []string{"jokicni01"}},
+		{"DONCIC", []string{"doncilu01"}},
+		{"lebron", []string{"jamesle01"}},
+		{"luka dončić", []string{"doncilu01"}},
+		// Same relevance: more career minutes first.
+		{"james", []string{"jamesle01", "jamesmi02", "jamesbr02"}},
+		{"jokc", []string{"jokicni01"}},
+		{"antetokunmpo", []string{"antetgi01"}},
+		{"giannis ante", []string{"antetgi01"}},
+		{"xyzzy", []string{}},
+	}
+	for _, tc := range cases {
+		results, err := SearchPlayers(db, models.LeagueNBA, tc.q, 10)
+		require.NoError(t, err, tc.q)
+		assert.Equal(t, tc.want, ids(results), tc.q)
+	}
+
+	// Prefix beats typo; the best match comes first.
+	results, err := SearchPlayers(db, models.LeagueNBA, "bron", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "jamesbr02", results[0].PlayerID)
+
+	// Minutes cover the regular season and playoffs.
+	results, err = SearchPlayers(db, models.LeagueNBA, "LeBron James", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "LeBron James", results[0].PlayerName)
+	assert.Equal(t, 1.0, results[0].Score)
+	assert.InDelta(t, 2704, results[0].Minutes, 1e-9)
+
+	results, err = SearchPlayers(db, models.LeagueWNBA, "jones", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jonesjo01w"}, ids(results))
+
+	// Only candidate names are read from the database.
+	for q, want := range map[string]int{"jokc": 1, "james": 4, "giannis ante": 1, "xyzzy": 0} {
+		rows, err := repository.NewGorm(db).Careers.SearchCandidates(models.LeagueNBA, q)
+		require.NoError(t, err)
+		assert.Len(t, rows, want, q)
+	}
+
+	_, err = SearchPlayers(db, models.LeagueNBA, " .'", 10)
+	assert.ErrorIs(t, err, ErrEmptySearch)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+// brShotPage renders a BR shooting page with the shot chart hidden in a
+// comment, as BR serves it; each marker is a style and tooltip pair.
+func brShotPage(markers ...[2]string) string {
+	var b strings.Builder
+	for _, m := range markers {
+		fmt.Fprintf(&b, `<div class="tooltip make" style="%s" tip="%s"></div>`, m[0], m[1])
+	}
+	return `<html><body><div id="meta"><span itemprop="name">Test Player</span></div>` +
+		`<!-- <div id="div_shot-chart"><div id="shot-wrapper">` + b.String() + `</div></div> -->` +
+		`</body></html>`
+}
+
+// brPlayoffSchedule renders BR's playoff schedule page with one game on
+// each date ("Sat, Apr 20, 2024").
+func brPlayoffSchedule(dates ...string) string {
+	var b strings.Builder
+	for _, d := range dates {
+		fmt.Fprintf(&b, `<tr><th data-stat="date_game">%s</th></tr>`, d)
+	}
+	return `<html><body><table id="schedule"><tbody>` + b.String() + `</tbody></table></body></html>`
+}
+
+// newBRStub serves pages by path and 404s everything else.
+func newBRStub(t *testing.T, pages map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newShotTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.PlayerShotChart{}, &models.DataQualityViolation{}))
+	return db
+}
+
+func TestImportShotChartKeepsUnparseableContext(t *testing.T) {
+	page := brShotPage(
+		[2]string{tooltipFixtures[0].style, tooltipFixtures[0].tip},
+		[2]string{"top:100px;left:200px;", "Nov 3, 2000, PHI at BOS<br>5th Qtr, 7:41 remaining<br>Made 2-pointer from 9 ft<br>PHI leads 20-18"},
+		[2]string{"top:110px;left:210px;", "Smarch 3, 2000, PHI at BOS<br>2nd Qtr, 1:00 remaining<br>Made 2-pointer from 9 ft<br>PHI leads 40-38"},
+	)
+	srv := newBRStub(t, map[string]string{
+		"/players/i/iversal01/shooting/2001": page,
+		"/playoffs/NBA_2001_games.html":      brPlayoffSchedule("Sat, Apr 21, 2001"),
+	})
+	db := newShotTestDB(t)
+
+	require.NoError(t, ImportShotChart(db, BRProvider{BaseURL: srv.URL}, "iversal01", 2001, 2001))
+
+	var shots []models.PlayerShotChart
+	require.NoError(t, db.Order("id").Find(&shots).Error)
+	require.Len(t, shots, 3)
+	assert.Equal(t, 3, shots[0].Period)
+
+	badQuarter := shots[1]
+	assert.Equal(t, "5th Qtr", badQuarter.Quarter)
+	assert.Zero(t, badQuarter.Period)
+	assert.Zero(t, badQuarter.SecondsRemaining)
+	assert.NotNil(t, badQuarter.GameDate) // the date still parsed
+	assert.Equal(t, 20, badQuarter.TeamScore)
+	assert.NotEmpty(t, badQuarter.Zone)
+
+	badDate := shots[2]
+	assert.Nil(t, badDate.GameDate)
+	assert.Equal(t, 2, badDate.Period)
+	assert.Equal(t, "BOS", badDate.Opponent)
+	assert.Equal(t, "Test Player", badDate.PlayerName)
+
+	// The import flags both for the data quality report.
+	var flagged []uint
+	require.NoError(t, db.Model(&models.DataQualityViolation{}).
+		Where("dataset = ? AND rule = ? AND player_id = ?", DatasetShots, "context_parsed", "iversal01").
+		Order("record_id").Pluck("record_id", &flagged).Error)
+	assert.Equal(t, []uint{badQuarter.ID, badDate.ID}, flagged)
+}
+
+func TestRunPlayerShotDataQuality(t *testing.T) {
+	db := newShotTestDB(t)
+	other := models.DataQualityViolation{Dataset: DatasetShots, League: models.LeagueNBA, Season: 2001,
+		PlayerID: "mutomdi01", Rule: "context_parsed"}
+	stale := models.DataQualityViolation{Dataset: DatasetShots, League: models.LeagueNBA, Season: 2001,
+		PlayerID: "iversal01", Rule: "three_distance"}
+	require.NoError(t, db.Create(&[]models.DataQualityViolation{other, stale}).Error)
+	require.NoError(t, db.Create(&models.PlayerShotChart{League: models.LeagueNBA, PlayerID: "iversal01", Season: 2001,
+		ShotType: "3-pointer", DistanceFt: 9}).Error)
+
+	violations, err := RunPlayerShotDataQuality(db, models.LeagueNBA, "iversal01", 2001)
+	require.NoError(t, err)
+	assert.Len(t, violations, 2) // short three, no game context
+
+	var stored []models.DataQualityViolation
+	require.NoError(t, db.Order("player_id, rule").Find(&stored).Error)
+	require.Len(t, stored, 3)
+	assert.Equal(t, "context_parsed", stored[0].Rule)
+	assert.Equal(t, "three_distance", stored[1].Rule)
+	assert.Equal(t, "mutomdi01", stored[2].PlayerID)
+}
+
+func TestStoreShotChartBatchesLargeSeasons(t *testing.T) {
+	db := newShotTestDB(t)
+
+	// One statement for all of these would bind more parameters than
+	// SQLite or Postgres accept.
+	shots := make([]models.PlayerShotChart, 2100)
+	for i := range shots {
+		shots[i] = models.PlayerShotChart{
+			PlayerID: "bryanko01", Season: 2006, Date: "Jan 22, 2006",
+			Quarter: "1st Qtr", TimeRemaining: fmt.Sprintf("%d:00", i%12), Top: i, Left: i % 500,
+		}
+	}
+	require.NoError(t, storeShotChart(db, shots, "bryanko01", 2006))
+	require.NoError(t, storeShotChart(db, shots, "bryanko01", 2006))
+
+	var count int64
+	require.NoError(t, db.Model(&models.PlayerShotChart{}).Count(&count).Error)
+	assert.EqualValues(t, 2100, count)
+}
@@ -16,47 +16,47 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-const (
-	regularURLFmt = "https://www.basketball-reference.com/leagues/NBA_%d_totals.html"
-	playoffURLFmt = "https://www.basketball-reference.com/playoffs/NBA_%d_totals.html"
-)
-
-// urlForSeason chooses regular vs. playoff URL.
-func urlForSeason(season int, isPlayoff bool) string {
-	if isPlayoff {
-		return fmt.Sprintf(playoffURLFmt, season)
-	}
-	return fmt.Sprintf(regularURLFmt, season)
+// urlForSeason chooses the league's regular vs. playoff totals URL.
+func urlForSeason(baseURL, league string, season int, isPlayoff bool) (string, error) {
+	return brSeasonURL(baseURL, league, season, isPlayoff, "totals")
 }
 
 // FetchAndStorePlayerTotalScrapedStats scrapes BR totals (regular or playoffs)
 // and batch upserts them into PlayerTotalStat for significantly better performance.
 func FetchAndStorePlayerTotalScrapedStats(db *gorm.DB, season int, isPlayoff bool) error {
-	url := urlForSeason(season, isPlayoff)
+	return ImportPlayerTotals(db, BRProvider{}, models.LeagueNBA, season, isPlayoff)
+}
+
+// scrapePlayerTotals parses the BR totals table for one league-season.
+func scrapePlayerTotals(baseURL, league string, season int, isPlayoff bool) ([]models.PlayerTotalStat, error) {
+	url, err := urlForSeason(baseURL, league, season, isPlayoff)
+	if err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible)")
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	table := doc.Find("table#totals_stats")
 	if table.Length() == 0 {
-		return fmt.Errorf("could not find table#totals_stats for season %d", season)
+		return nil, fmt.Errorf("could not find table#totals_stats for season %d", season)
 	}
 
 	// 1) Collect the data-stat keys in header order.
@@ -114,6 +114,9 @@ func FetchAndStorePlayerTotalScrapedStats(db *gorm.DB, season int, isPlayoff boo
 		if teamID == "" {
 			teamID = data["team_name_abbr"]
 		}
+		// Traded players get one aggregate row ("TOT", or "2TM"/"3TM" on
+		// newer pages) on top of their per-team rows.
+		teamID, isAggregate := normalizeTeam(teamID)
 
 		// Pick “games”.
 		g := mustAtoi(data["games"])
@@ -129,6 +132,7 @@ func FetchAndStorePlayerTotalScrapedStats(db *gorm.DB, season int, isPlayoff boo
 
 		stat := models.PlayerTotalStat{
 			ExternalID:      extID,
+			League:          league,
 			PlayerID:        playerID,
 			PlayerName:      playerName,
 			Position:        data["pos"],
@@ -161,37 +165,48 @@ func FetchAndStorePlayerTotalScrapedStats(db *gorm.DB, season int, isPlayoff boo
 			Team:            teamID,
 			Season:          season,
 			IsPlayoff:       isPlayoff,
+			IsAggregate:     isAggregate,
 		}
 
 		// Add the parsed stat object to our slice instead of writing to the DB immediately.
 		statsToUpsert = append(statsToUpsert, stat)
 	})
 
+	return statsToUpsert, nil
+}
+
+// storePlayerTotals batch upserts one season of totals, behind the data quality gate.
+func storePlayerTotals(db *gorm.DB, statsToUpsert []models.PlayerTotalStat, league string, season int, isPlayoff bool) error {
 	// 3) Perform the batch upsert operation after collecting all rows.
 	if len(statsToUpsert) > 0 {
-		log.Printf("Attempting to batch upsert %d player total stats for season %d...", len(statsToUpsert), season)
+		log.Printf("Attempting to batch upsert %d %s player total stats for season %d...", len(statsToUpsert), league, season)
 
 		// GORM's OnConflict clause works with slices, performing the batch operation efficiently.
-		if err := db.Clauses(clause.OnConflict{
-			Columns: []clause.Column{
-				{Name: "player_id"},
-				{Name: "season"},
-				{Name: "team"},
-				{Name: "is_playoff"},
-			},
-			DoUpdates: clause.AssignmentColumns([]string{
-				"external_id", "player_name", "position", "age",
-				"games", "games_started", "minutes_pg",
-				"field_goals", "field_attempts", "field_percent",
-				"three_fg", "three_attempts", "three_percent",
-				"two_fg", "two_attempts", "two_percent",
-				"effect_fg_percent",
-				"ft", "ft_attempts", "ft_percent",
-				"offensive_rb", "defensive_rb", "total_rb",
-				"assists", "steals", "blocks", "turnovers",
-				"personal_fouls", "points",
-			}),
-		}).Create(&statsToUpsert).Error; err != nil {
+		// Upsert and validate in one transaction; a strict import that fails
+		// the data quality gate leaves the season untouched.
+		if err := commitWithQualityGate(db, DatasetTotals, league, season, isPlayoff, func(tx *gorm.DB) error {
+			return tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{
+					{Name: "league"},
+					{Name: "player_id"},
+					{Name: "season"},
+					{Name: "team"},
+					{Name: "is_playoff"},
+				},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"external_id", "player_name", "position", "age",
+					"games", "games_started", "minutes_pg",
+					"field_goals", "field_attempts", "field_percent",
+					"three_fg", "three_attempts", "three_percent",
+					"two_fg", "two_attempts", "two_percent",
+					"effect_fg_percent",
+					"ft", "ft_attempts", "ft_percent",
+					"offensive_rb", "defensive_rb", "total_rb",
+					"assists", "steals", "blocks", "turnovers",
+					"personal_fouls", "points", "is_aggregate",
+				}),
+			}).Create(&statsToUpsert).Error
+		}); err != nil {
 			// If the batch operation fails, log the error and return it.
 			log.Printf("Failed to batch upsert player total stats: %v", err)
 			return err
@@ -204,4 +219,4 @@ func FetchAndStorePlayerTotalScrapedStats(db *gorm.DB, season int, isPlayoff boo
 	// --- BATCHING LOGIC END ---
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,176 @@
+// File: services/provider.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"gorm.io/gorm"
+)
+
+// Provider names accepted by ProviderByName.
+const (
+	ProviderBR  = "br"
+	ProviderNBA = "nba"
+)
+
+// StatsProvider is an upstream source for the stored datasets. Providers
+// only fetch and map rows; storing, dedup and validation are shared.
+type StatsProvider interface {
+	// Name is the short provider name ("br", "nba").
+	Name() string
+	// OwnsPlayerID reports whether id is in this provider's ID style.
+	OwnsPlayerID(id string) bool
+	// FetchPlayerTotals and FetchPlayerAdvanced return one league-season;
+	// league is one of models.Leagues.
+	FetchPlayerTotals(league string, season int, isPlayoff bool) ([]models.PlayerTotalStat, error)
+	FetchPlayerAdvanced(league string, season int, isPlayoff bool) ([]models.PlayerAdvancedStat, error)
+	// FetchShotChart returns one NBA player-season of shots; no shots is not an error.
+	FetchShotChart(playerID string, season int) ([]models.PlayerShotChart, error)
+}
+
+// ProviderByName resolves a provider option; "" means Basketball-Reference.
+func ProviderByName(name string) (StatsProvider, error) {
+	switch name {
+	case "", ProviderBR:
+		return BRProvider{}, nil
+	case ProviderNBA:
+		return NewNBAStatsProvider(), nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (want %s or %s)", name, ProviderBR, ProviderNBA)
+}
+
+// ParseLeague upper-cases a league option and checks it against
+// models.Leagues; "" means the NBA.
+func ParseLeague(name string) (string, error) {
+	if name == "" {
+		return models.LeagueNBA, nil
+	}
+	league := strings.ToUpper(name)
+	if !slices.Contains(models.Leagues, league) {
+		return "", fmt.Errorf("unknown league %q (want one of %s)", name, strings.Join(models.Leagues, ", "))
+	}
+	return league, nil
+}
+
+// ImportPlayerTotals fetches one league-season of totals from p, stores
+// them and rebuilds the imported players' career rows and the season's
+// percentiles.
+func ImportPlayerTotals(db *gorm.DB, p StatsProvider, league string, season int, isPlayoff bool) error {
+	stats, err := p.FetchPlayerTotals(league, season, isPlayoff)
+	if err != nil {
+		return err
+	}
+	if err := storePlayerTotals(db, stats, league, season, isPlayoff); err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(stats))
+	for _, s := range stats {
+		if !slices.Contains(ids, s.PlayerID) {
+			ids = append(ids, s.PlayerID)
+		}
+	}
+	if err := RebuildCareerStats(db, league, ids...); err != nil {
+		return err
+	}
+	return RefreshPercentiles(db, league, season, isPlayoff)
+}
+
+// ImportPlayerAdvanced fetches one league-season of advanced stats from p,
+// stores them and rebuilds the season's percentiles.
+func ImportPlayerAdvanced(db *gorm.DB, p StatsProvider, league string, season int, isPlayoff bool) error {
+	stats, err := p.FetchPlayerAdvanced(league, season, isPlayoff)
+	if err != nil {
+		return err
+	}
+	if err := storePlayerAdvanced(db, stats, league, season, isPlayoff); err != nil {
+		return err
+	}
+	advancedStatsVersion.Add(1)
+	if len(stats) == 0 {
+		return nil
+	}
+	return RefreshPercentiles(db, league, season, isPlayoff)
+}
+
+// ImportShotChart fetches and stores a player's shots for seasons
+// startSeason down to endSeason, then validates each imported season's
+// shots. Seasons without a shot chart, where the player did not play, are
+// skipped.
+func ImportShotChart(db *gorm.DB, p StatsProvider, playerID string, startSeason, endSeason int) error {
+	// Loop newest → oldest season
+	for season := startSeason; season >= endSeason; season-- {
+		err := importShotChartSeason(db, p, playerID, season)
+		if errors.Is(err, ErrNoShotChart) {
+			log.Printf("⚠️  Skipping season %d for player %s: %v", season, playerID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := RunPlayerShotDataQuality(db, models.LeagueNBA, playerID, season); err != nil {
+			log.Printf("shot data quality check for %s in %d failed: %v", playerID, season, err)
+		}
+	}
+	return nil
+}
+
+// importShotChartSeason fetches and stores one season of a player's shots.
+func importShotChartSeason(db *gorm.DB, p StatsProvider, playerID string, season int) error {
+	shots, err := p.FetchShotChart(playerID, season)
+	if err != nil {
+		return err
+	}
+	return storeShotChart(db, shots, playerID, season)
+}
+
+// BRProvider scrapes Basketball-Reference HTML pages.
+type BRProvider struct {
+	// BaseURL defaults to basketball-reference.com; tests point it at a
+	// stub server.
+	BaseURL string
+}
+
+func (p BRProvider) baseURL() string {
+	if p.BaseURL == "" {
+		return brBaseURL
+	}
+	return p.BaseURL
+}
+
+func (BRProvider) Name() string { return ProviderBR }
+
+// OwnsPlayerID accepts BR slugs such as "hardeja01".
+func (BRProvider) OwnsPlayerID(id string) bool { return !isNumericID(id) }
+
+func (p BRProvider) FetchPlayerTotals(league string, season int, isPlayoff bool) ([]models.PlayerTotalStat, error) {
+	return scrapePlayerTotals(p.baseURL(), league, season, isPlayoff)
+}
+
+func (p BRProvider) FetchPlayerAdvanced(league string, season int, isPlayoff bool) ([]models.PlayerAdvancedStat, error) {
+	return scrapePlayerAdvanced(p.baseURL(), league, season, isPlayoff)
+}
+
+func (p BRProvider) FetchShotChart(playerID string, season int) ([]models.PlayerShotChart, error) {
+	return scrapeShotChartSeason(p.baseURL(), playerID, season)
+}
+
+// isNumericID reports whether id is an NBA.com person ID such as "201935".
+func isNumericID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+// File: services/shot_bins.go
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// Shot bin kinds for BinShots.
+const (
+	BinHex      = "hex"      // hexagons over the chart's Top/Left pixels
+	BinGrid     = "grid"     // squares over the chart's Top/Left pixels
+	BinDistance = "distance" // DistanceFt bands
+)
+
+// ShotBinKinds lists the accepted bin kinds.
+var ShotBinKinds = []string{BinHex, BinGrid, BinDistance}
+
+// DefaultBinSize is the bin size used when none is given: the hexagon
+// radius or grid cell side in chart pixels (10 px to the foot), or the
+// band width in feet.
+var DefaultBinSize = map[string]float64{BinHex: 15, BinGrid: 20, BinDistance: 3}
+
+// maxShotBinSize bounds bin sizes; the chart is 500 px (50 ft) wide.
+const maxShotBinSize = 1000
+
+// ShotBinGroups are the columns BinShots needs its tallies grouped on, by
+// bin kind.
+var ShotBinGroups = map[string][]string{
+	BinHex:      {"top", "left"},
+	BinGrid:     {"top", "left"},
+	BinDistance: {"distance_ft"},
+}
+
+// ShotBin is the shooting in one bin. Hex and grid bins are located by
+// their centre in chart pixels; distance bins by their band in feet.
+type ShotBin struct {
+	Key           string   `json:"key"`
+	Left          *float64 `json:"left,omitempty"`
+	Top           *float64 `json:"top,omitempty"`
+	MinDistanceFt *int     `json:"minDistanceFt,omitempty"`
+	MaxDistanceFt *int     `json:"maxDistanceFt,omitempty"`
+	Attempts      int      `json:"attempts"`
+	Makes         int      `json:"makes"`
+	Points        int      `json:"points"`
+	FGPercent     float64  `json:"fgPercent"`
+	PointsPerShot float64  `json:"pointsPerShot"`
+	// LeagueFGPercent is the league's FG% in the same bin, when asked for.
+	LeagueFGPercent *float64 `json:"leagueFgPercent,omitempty"`
+}
+
+// add counts a tally of shots.
+func (b *ShotBin) add(t *repository.ShotTally) {
+	b.Attempts += t.Attempts
+	b.Makes += t.Makes
+	b.Points += t.Points
+}
+
+// finish fills the rates from the counts.
+func (b *ShotBin) finish() {
+	b.FGPercent = ratio(b.Makes, b.Attempts)
+	b.PointsPerShot = ratio(b.Points, b.Attempts)
+}
+
+// CheckShotBin validates a bin kind and size. Distance bands are whole
+// feet, since DistanceFt is.
+func CheckShotBin(kind string, size float64) error {
+	switch {
+	case !slices.Contains(ShotBinKinds, kind):
+		return fmt.Errorf("unknown bin %q (want hex, grid or distance)", kind)
+	case !(size > 0 && size <= maxShotBinSize):
+		return fmt.Errorf("bin size must be above 0 and at most %d", maxShotBinSize)
+	case kind == BinDistance && size != math.Trunc(size):
+		return fmt.Errorf("distance bin size must be a whole number of feet")
+	}
+	return nil
+}
+
+// BinShots groups shot tallies, grouped at least on ShotBinGroups[kind],
+// into bins of the given kind and size and returns the non-empty bins,
+// ordered top to bottom then left to right (or by distance), with the
+// totals over every shot.
+func BinShots(tallies []repository.ShotTally, kind string, size float64) ([]ShotBin, ShotBin, error) {
+	if err := CheckShotBin(kind, size); err != nil {
+		return nil, ShotBin{}, err
+	}
+
+	total := ShotBin{Key: "all"}
+	byKey := map[string]*ShotBin{}
+	var order []*ShotBin
+	for i := range tallies {
+		t := &tallies[i]
+		if t.Attempts == 0 {
+			continue
+		}
+		total.add(t)
+		b := binFor(t, kind, size)
+		if existing, ok := byKey[b.Key]; ok {
+			existing.add(t)
+			continue
+		}
+		b.add(t)
+		byKey[b.Key] = &b
+		order = append(order, &b)
+	}
+	total.finish()
+
+	bins := make([]ShotBin, len(order))
+	for i, b := range order {
+		b.finish()
+		bins[i] = *b
+	}
+	slices.SortFunc(bins, func(a, b ShotBin) int {
+		if a.MinDistanceFt != nil {
+			return cmp.Compare(*a.MinDistanceFt, *b.MinDistanceFt)
+		}
+		return cmp.Or(cmp.Compare(*a.Top, *b.Top), cmp.Compare(*a.Left, *b.Left))
+	})
+	return bins, total, nil
+}
+
+// binFor returns the empty bin the tallied shots fall in.
+func binFor(s *repository.ShotTally, kind string, size float64) ShotBin {
+	switch kind {
+	case BinDistance:
+		width := int(size)
+		lo := s.DistanceFt / width * width
+		hi := lo + width - 1
+		return ShotBin{Key: fmt.Sprintf("%d-%d", lo, hi), MinDistanceFt: &lo, MaxDistanceFt: &hi}
+	case BinGrid:
+		col := math.Floor(float64(s.Left) / size)
+		row := math.Floor(float64(s.Top) / size)
+		left, top := round1((col+0.5)*size), round1((row+0.5)*size)
+		return ShotBin{Key: fmt.Sprintf("%d,%d", int(row), int(col)), Left: &left, Top: &top}
+	}
+	// Pointy-top hexagons in axial coordinates, rounded through cube
+	// coordinates to the nearest centre.
+	x, y := float64(s.Left), float64(s.Top)
+	q := (math.Sqrt(3)/3*x - y/3) / size
+	r := 2.0 / 3 * y / size
+	cq, cr := hexRound(q, r)
+	left := round1(size * math.Sqrt(3) * (float64(cq) + float64(cr)/2))
+	top := round1(size * 1.5 * float64(cr))
+	return ShotBin{Key: fmt.Sprintf("%d,%d", cq, cr), Left: &left, Top: &top}
+}
+
+// hexRound rounds fractional axial coordinates to the containing hexagon.
+func hexRound(q, r float64) (int, int) {
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	switch {
+	case dq > dr && dq > ds:
+		rq = -rr - rs
+	case dr > ds:
+		rr = -rq - rs
+	}
+	return int(rq), int(rr)
+}
+
+// AttachLeagueFG sets LeagueFGPercent on each bin from the league's bins
+// of the same kind and size; bins the league never shot from stay nil.
+func AttachLeagueFG(bins, league []ShotBin) {
+	byKey := make(map[string]float64, len(league))
+	for _, b := range league {
+		byKey[b.Key] = b.FGPercent
+	}
+	for i := range bins {
+		if fg, ok := byKey[bins[i].Key]; ok {
+			bins[i].LeagueFGPercent = &fg
+		}
+	}
+}
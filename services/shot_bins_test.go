@@ -0,0 +1,106 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+func binShot(top, left, dist int, made bool, shotType string) repository.ShotTally {
+	return repository.TallyShot(&models.PlayerShotChart{Top: top, Left: left, DistanceFt: dist, Result: made, ShotType: shotType})
+}
+
+var binTestShots = []repository.ShotTally{
+	binShot(55, 235, 1, true, "2-pointer"),
+	binShot(58, 252, 0, false, "2-pointer"),
+	binShot(59, 248, 0, true, "2-pointer"),
+	binShot(300, 250, 25, true, "3-pointer"),
+	binShot(302, 252, 25, false, "3-pointer"),
+}
+
+func TestBinShotsGrid(t *testing.T) {
+	bins, totals, err := BinShots(binTestShots, BinGrid, 20)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, totals.Attempts)
+	assert.Equal(t, 3, totals.Makes)
+	assert.Equal(t, 0.6, totals.FGPercent)
+	assert.Equal(t, 1.4, totals.PointsPerShot)
+
+	require.Len(t, bins, 3)
+	// Ordered top to bottom, then left to right.
+	assert.Equal(t, "2,11", bins[0].Key)
+	assert.Equal(t, 50.0, *bins[0].Top)
+	assert.Equal(t, 230.0, *bins[0].Left)
+	assert.Equal(t, 1, bins[0].Attempts)
+	assert.Equal(t, "2,12", bins[1].Key)
+	assert.Equal(t, 2, bins[1].Attempts)
+	assert.Equal(t, 0.5, bins[1].FGPercent)
+	assert.Equal(t, "15,12", bins[2].Key)
+	assert.Equal(t, 1.5, bins[2].PointsPerShot)
+}
+
+func TestBinShotsHex(t *testing.T) {
+	bins, _, err := BinShots(binTestShots, BinHex, 15)
+	require.NoError(t, err)
+
+	var attempts int
+	for _, b := range bins {
+		attempts += b.Attempts
+	}
+	assert.Equal(t, 5, attempts)
+
+	// Every shot lies within one radius of its hexagon's centre.
+	for i := range binTestShots {
+		s := &binTestShots[i]
+		b := binFor(s, BinHex, 15)
+		assert.LessOrEqual(t, math.Hypot(*b.Left-float64(s.Left), *b.Top-float64(s.Top)), 15.0)
+	}
+	assert.Equal(t, binFor(&binTestShots[3], BinHex, 15).Key, binFor(&binTestShots[4], BinHex, 15).Key)
+}
+
+func TestBinShotsDistance(t *testing.T) {
+	bins, _, err := BinShots(binTestShots, BinDistance, 3)
+	require.NoError(t, err)
+	require.Len(t, bins, 2)
+	assert.Equal(t, "0-2", bins[0].Key)
+	assert.Equal(t, 0, *bins[0].MinDistanceFt)
+	assert.Equal(t, 2, *bins[0].MaxDistanceFt)
+	assert.Equal(t, 3, bins[0].Attempts)
+	assert.Equal(t, "24-26", bins[1].Key)
+
+	league, _, err := BinShots(append(binTestShots, binShot(300, 250, 24, true, "3-pointer")), BinDistance, 3)
+	require.NoError(t, err)
+	AttachLeagueFG(bins, league)
+	require.NotNil(t, bins[1].LeagueFGPercent)
+	assert.Equal(t, 0.667, *bins[1].LeagueFGPercent)
+
+	_, _, err = BinShots(binTestShots, "triangle", 3)
+	assert.Error(t, err)
+	for _, size := range []float64{0, -1, math.NaN(), math.Inf(1), 5000} {
+		_, _, err = BinShots(binTestShots, BinHex, size)
+		assert.Error(t, err, size)
+	}
+	_, _, err = BinShots(binTestShots, BinDistance, 2.5) // bands are whole feet
+	assert.Error(t, err)
+}
+
+func TestBinShotsTallies(t *testing.T) {
+	// Shots tallied per spot bin the same as one by one.
+	grouped := []repository.ShotTally{
+		{Top: 58, Left: 252, Attempts: 3, Makes: 2, Points: 4},
+		{Top: 300, Left: 250, Attempts: 2, Makes: 1, Points: 3},
+	}
+	bins, totals, err := BinShots(grouped, BinGrid, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 5, totals.Attempts)
+	assert.Equal(t, 1.4, totals.PointsPerShot)
+	require.Len(t, bins, 2)
+	assert.Equal(t, 3, bins[0].Attempts)
+	assert.Equal(t, 0.667, bins[0].FGPercent)
+}
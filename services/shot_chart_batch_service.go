@@ -0,0 +1,180 @@
+// File: services/shot_chart_batch_service.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// shotChartScrapeDelay is the base pause between player pages; BR throttles
+// clients that go above roughly 20 requests a minute.
+const shotChartScrapeDelay = 3500 * time.Millisecond
+
+// shotChartPause waits between player pages; tests turn it off.
+var shotChartPause = func() {
+	time.Sleep(1100 * time.Millisecond)
+	utils.SleepWithJitter(shotChartScrapeDelay)
+}
+
+// ShotChartBatchResult summarizes a season-wide shot chart import.
+type ShotChartBatchResult struct {
+	Season   int `json:"season"`
+	Players  int `json:"players"`
+	Skipped  int `json:"skipped"`
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"` // no shot chart; skipped when resuming
+	Retry    int `json:"retry"`  // transient errors; tried again when resuming
+}
+
+// ShotChartBatchProgress is the per-status player count for a season.
+type ShotChartBatchProgress struct {
+	Season  int            `json:"season"`
+	Players int            `json:"players"`
+	Status  map[string]int `json:"status"`
+}
+
+// SeasonPlayerIDs returns every NBA player with a PlayerTotalStat row for
+// the season, regular season and playoffs alike. Shot charts are NBA-only.
+func SeasonPlayerIDs(db *gorm.DB, season int) ([]string, error) {
+	var ids []string
+	err := db.Model(&models.PlayerTotalStat{}).
+		Where("league = ? AND season = ?", models.LeagueNBA, season).
+		Distinct().
+		Order("player_id").
+		Pluck("player_id", &ids).Error
+	return ids, err
+}
+
+// ImportSeasonShotCharts scrapes the shot chart of every rostered player for
+// one season from p, limited to players whose IDs are in p's style. Each
+// player is recorded as done, failed (no shot chart) or retry (any other
+// error). Done and failed players are skipped unless force is set, so an
+// interrupted run can simply be started again.
+func ImportSeasonShotCharts(db *gorm.DB, p StatsProvider, season int, force bool) (ShotChartBatchResult, error) {
+	result := ShotChartBatchResult{Season: season}
+
+	all, err := SeasonPlayerIDs(db, season)
+	if err != nil {
+		return result, err
+	}
+	var ids []string
+	for _, id := range all {
+		if p.OwnsPlayerID(id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return result, fmt.Errorf("no PlayerTotalStat rows for season %d; import totals first", season)
+	}
+	result.Players = len(ids)
+
+	done := map[string]bool{}
+	if !force {
+		var finished []string
+		if err := db.Model(&models.ShotChartImportProgress{}).
+			Where("season = ? AND status IN ?", season, []string{models.ShotChartImportDone, models.ShotChartImportFailed}).
+			Pluck("player_id", &finished).Error; err != nil {
+			return result, err
+		}
+		for _, id := range finished {
+			done[id] = true
+		}
+	}
+
+	for i, pid := range ids {
+		if done[pid] {
+			result.Skipped++
+			continue
+		}
+
+		log.Printf("▶️  [%d/%d] shot chart %s (season %d)", i+1, len(ids), pid, season)
+		status, msg := models.ShotChartImportDone, ""
+		err := importShotChartSeason(db, p, pid, season)
+		switch {
+		case err == nil:
+			result.Imported++
+		case errors.Is(err, ErrNoShotChart):
+			status, msg = models.ShotChartImportFailed, err.Error()
+			result.Failed++
+		default:
+			status, msg = models.ShotChartImportRetry, err.Error()
+			result.Retry++
+		}
+		if err != nil {
+			log.Printf("shot chart import %s for %s in %d: %v", status, pid, season, err)
+		}
+
+		if err := recordShotChartProgress(db, season, pid, status, msg); err != nil {
+			return result, err
+		}
+		shotChartPause()
+	}
+
+	if _, err := RunDataQuality(db, DatasetShots, models.LeagueNBA, season, false); err != nil {
+		log.Printf("shot data quality check for %d failed: %v", season, err)
+	}
+
+	log.Printf("🎯 Shot charts for %d: %d imported, %d skipped, %d failed, %d to retry (of %d players)",
+		season, result.Imported, result.Skipped, result.Failed, result.Retry, result.Players)
+	return result, nil
+}
+
+// GetSeasonShotChartProgress reports how far a season-wide import has got.
+func GetSeasonShotChartProgress(db *gorm.DB, season int) (ShotChartBatchProgress, error) {
+	progress := ShotChartBatchProgress{Season: season, Status: map[string]int{}}
+
+	ids, err := SeasonPlayerIDs(db, season)
+	if err != nil {
+		return progress, err
+	}
+	progress.Players = len(ids)
+
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	if err := db.Model(&models.ShotChartImportProgress{}).
+		Select("status, COUNT(*) AS count").
+		Where("season = ?", season).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return progress, err
+	}
+
+	seen := 0
+	for _, r := range rows {
+		progress.Status[r.Status] = r.Count
+		seen += r.Count
+	}
+	if pending := progress.Players - seen; pending > 0 {
+		progress.Status["pending"] = pending
+	}
+	return progress, nil
+}
+
+// recordShotChartProgress upserts the outcome for one player-season.
+func recordShotChartProgress(db *gorm.DB, season int, playerID, status, msg string) error {
+	rec := models.ShotChartImportProgress{
+		Season:   season,
+		PlayerID: playerID,
+		Status:   status,
+		Error:    msg,
+		Attempts: 1,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "season"}, {Name: "player_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":     status,
+			"error":      msg,
+			"attempts":   gorm.Expr("shot_chart_import_progresses.attempts + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&rec).Error
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestImportSeasonShotCharts(t *testing.T) {
+	saved := shotChartPause
+	shotChartPause = func() {}
+	t.Cleanup(func() { shotChartPause = saved })
+
+	pages := map[string]string{
+		"/players/o/okokok01/shooting/2024": brShotPage([2]string{"top:62px;left:468px;",
+			"Jan 3, 2024, GSW vs UTA<br>1st Qtr, 9:00 remaining<br>Made 3-pointer from 24 ft<br>GSW leads 3-0"}),
+		"/players/e/emptyy01/shooting/2024": `<html><body><div id="meta"></div></body></html>`,
+		"/players/b/busyyy01/shooting/2024": brShotPage(),
+		"/playoffs/NBA_2024_games.html":     brPlayoffSchedule("Sat, Apr 20, 2024"),
+	}
+	var mu sync.Mutex
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		n := hits[r.URL.Path]
+		mu.Unlock()
+		if r.URL.Path == "/players/b/busyyy01/shooting/2024" && n == 1 {
+			http.Error(w, "slow down", http.StatusTooManyRequests) // throttled once
+			return
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	t.Cleanup(srv.Close)
+
+	db := newQualityTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.PlayerShotChart{}, &models.ShotChartImportProgress{}))
+	for _, pid := range []string{"okokok01", "goneee01", "emptyy01", "busyyy01"} {
+		require.NoError(t, db.Create(&models.PlayerTotalStat{League: models.LeagueNBA, PlayerID: pid, Team: "GSW", Season: 2024}).Error)
+	}
+	p := BRProvider{BaseURL: srv.URL}
+	statuses := func() map[string]string {
+		var rows []models.ShotChartImportProgress
+		require.NoError(t, db.Where("season = ?", 2024).Find(&rows).Error)
+		out := map[string]string{}
+		for _, r := range rows {
+			out[r.PlayerID] = r.Status
+		}
+		return out
+	}
+
+	// A missing page or a page without a chart fails; a throttled one is
+	// kept for a retry. Neither counts as done.
+	result, err := ImportSeasonShotCharts(db, p, 2024, false)
+	require.NoError(t, err)
+	assert.Equal(t, ShotChartBatchResult{Season: 2024, Players: 4, Imported: 1, Failed: 2, Retry: 1}, result)
+	assert.Equal(t, map[string]string{
+		"okokok01": models.ShotChartImportDone,
+		"goneee01": models.ShotChartImportFailed,
+		"emptyy01": models.ShotChartImportFailed,
+		"busyyy01": models.ShotChartImportRetry,
+	}, statuses())
+	var shots int64
+	require.NoError(t, db.Model(&models.PlayerShotChart{}).Count(&shots).Error)
+	assert.EqualValues(t, 1, shots)
+
+	progress, err := GetSeasonShotChartProgress(db, 2024)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"done": 1, "failed": 2, "retry": 1}, progress.Status)
+
+	// Resuming only retries the throttled player.
+	result, err = ImportSeasonShotCharts(db, p, 2024, false)
+	require.NoError(t, err)
+	assert.Equal(t, ShotChartBatchResult{Season: 2024, Players: 4, Skipped: 3, Imported: 1}, result)
+	assert.Equal(t, models.ShotChartImportDone, statuses()["busyyy01"])
+	assert.Equal(t, 1, hits["/players/o/okokok01/shooting/2024"])
+	assert.Equal(t, 1, hits["/players/g/goneee01/shooting/2024"])
+
+	// Force redoes everyone.
+	result, err = ImportSeasonShotCharts(db, p, 2024, true)
+	require.NoError(t, err)
+	assert.Equal(t, ShotChartBatchResult{Season: 2024, Players: 4, Imported: 2, Failed: 2}, result)
+	assert.Equal(t, 2, hits["/players/o/okokok01/shooting/2024"])
+	var ok models.ShotChartImportProgress
+	require.NoError(t, db.Where("player_id = ?", "okokok01").First(&ok).Error)
+	assert.Equal(t, 2, ok.Attempts)
+	require.NoError(t, db.Model(&models.PlayerShotChart{}).Count(&shots).Error)
+	assert.EqualValues(t, 1, shots) // upserted, not duplicated
+}
@@ -0,0 +1,131 @@
+// File: services/shot_context.go
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	regulationPeriods   = 4
+	regulationPeriodSec = 12 * 60
+	overtimePeriodSec   = 5 * 60
+)
+
+// shotDateLayouts covers the stored "Apr 9,2024" form (the tooltip's comma
+// join drops the space) as well as the raw "Apr 9, 2024".
+var shotDateLayouts = []string{"Jan 2,2006", "Jan 2, 2006"}
+
+// shotContext is the typed form of a shot's date, period and game clock.
+type shotContext struct {
+	GameDate         *time.Time
+	Period           int
+	IsOvertime       bool
+	SecondsRemaining int
+	ElapsedSeconds   int
+}
+
+// parseShotDate parses a tooltip date into a UTC calendar date.
+func parseShotDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range shotDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized shot date %q", s)
+}
+
+// parsePeriod turns "1st Qtr"…"4th Qtr" into 1–4 and "1st OT", "2nd OT", …
+// into 5, 6, ….
+func parsePeriod(s string) (period int, overtime bool, err error) {
+	f := strings.Fields(s)
+	if len(f) != 2 {
+		return 0, false, fmt.Errorf("unrecognized period %q", s)
+	}
+	n, err := strconv.Atoi(strings.TrimRight(f[0], "stndrh"))
+	if err != nil || n < 1 {
+		return 0, false, fmt.Errorf("unrecognized period %q", s)
+	}
+	switch strings.ToUpper(f[1]) {
+	case "QTR":
+		if n > regulationPeriods {
+			return 0, false, fmt.Errorf("unrecognized period %q", s)
+		}
+		return n, false, nil
+	case "OT":
+		return regulationPeriods + n, true, nil
+	}
+	return 0, false, fmt.Errorf("unrecognized period %q", s)
+}
+
+// parseClock turns "5:32" (or "0:04.2") into whole seconds.
+func parseClock(s string) (int, error) {
+	m, sec, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("unrecognized game clock %q", s)
+	}
+	mins, err := strconv.Atoi(m)
+	if err != nil || mins < 0 {
+		return 0, fmt.Errorf("unrecognized game clock %q", s)
+	}
+	secs, err := strconv.ParseFloat(sec, 64)
+	if err != nil || secs < 0 || secs >= 60 {
+		return 0, fmt.Errorf("unrecognized game clock %q", s)
+	}
+	return mins*60 + int(secs), nil
+}
+
+// periodLength is the length of a period in seconds.
+func periodLength(period int) int {
+	if period > regulationPeriods {
+		return overtimePeriodSec
+	}
+	return regulationPeriodSec
+}
+
+// elapsedGameSeconds is the time since tip-off at the given clock reading.
+func elapsedGameSeconds(period, secondsRemaining int) int {
+	var before int
+	if period > regulationPeriods {
+		before = regulationPeriods*regulationPeriodSec + (period-regulationPeriods-1)*overtimePeriodSec
+	} else {
+		before = (period - 1) * regulationPeriodSec
+	}
+	return before + periodLength(period) - secondsRemaining
+}
+
+// parseShotContext derives the typed columns from the stored text fields.
+// Fields that fail to parse are left zero; the error reports the first one.
+func parseShotContext(date, quarter, timeRemaining string) (shotContext, error) {
+	var ctx shotContext
+	var firstErr error
+
+	if d, err := parseShotDate(date); err == nil {
+		ctx.GameDate = &d
+	} else {
+		firstErr = err
+	}
+
+	period, ot, err := parsePeriod(quarter)
+	if err != nil {
+		if firstErr == nil {
+			firstErr = err
+		}
+		return ctx, firstErr
+	}
+	ctx.Period, ctx.IsOvertime = period, ot
+
+	secs, err := parseClock(timeRemaining)
+	if err != nil {
+		if firstErr == nil {
+			firstErr = err
+		}
+		return ctx, firstErr
+	}
+	ctx.SecondsRemaining = secs
+	ctx.ElapsedSeconds = elapsedGameSeconds(period, secs)
+	return ctx, firstErr
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShotContext(t *testing.T) {
+	tests := []struct {
+		date, qtr, clock string
+		wantDate         string
+		wantPeriod       int
+		wantOT           bool
+		wantRemaining    int
+		wantElapsed      int
+	}{
+		{"Apr 9,2024", "1st Qtr", "5:32", "2024-04-09", 1, false, 332, 388},
+		{"Apr 9, 2024", "4th Qtr", "0:00", "2024-04-09", 4, false, 0, 2880},
+		{"Dec 25,2023", "1st OT", "4:59", "2023-12-25", 5, true, 299, 2881},
+		{"Dec 25,2023", "2nd OT", "0:01.5", "2023-12-25", 6, true, 1, 3479},
+	}
+
+	for _, tc := range tests {
+		ctx, err := parseShotContext(tc.date, tc.qtr, tc.clock)
+		assert.NoError(t, err, tc.date+" "+tc.qtr)
+		if assert.NotNil(t, ctx.GameDate) {
+			assert.Equal(t, tc.wantDate, ctx.GameDate.Format(time.DateOnly))
+		}
+		assert.Equal(t, tc.wantPeriod, ctx.Period)
+		assert.Equal(t, tc.wantOT, ctx.IsOvertime)
+		assert.Equal(t, tc.wantRemaining, ctx.SecondsRemaining)
+		assert.Equal(t, tc.wantElapsed, ctx.ElapsedSeconds)
+	}
+
+	_, err := parseShotContext("Apr 9,2024", "5th Qtr", "1:00")
+	assert.Error(t, err)
+	_, err = parseShotContext("not a date", "1st Qtr", "1:00")
+	assert.Error(t, err)
+}
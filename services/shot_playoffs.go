@@ -0,0 +1,88 @@
+// File: services/shot_playoffs.go
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// playoffStarts caches each season's first playoff day by
+// "<baseURL>|<season>"; a season's schedule never moves once played.
+var playoffStarts sync.Map
+
+// playoffStartDate returns the first day of a season's NBA playoffs (the
+// year the season ends) as YYYY-MM-DD, read off BR's playoff schedule.
+// Play-in games are not on it, so they count as regular season. ok is
+// false while the playoffs have not started: the schedule page does not
+// exist yet for a season still in progress. Any other miss is an error,
+// so playoff shots are never silently filed as regular season.
+func playoffStartDate(baseURL string, season int) (start string, ok bool, err error) {
+	key := fmt.Sprintf("%s|%d", baseURL, season)
+	if v, cached := playoffStarts.Load(key); cached {
+		return v.(string), true, nil
+	}
+
+	url := fmt.Sprintf("%s/playoffs/NBA_%d_games.html", baseURL, season)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible)")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("playoff schedule for %d: %w", season, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && seasonInProgress(season, time.Now()) {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("playoff schedule for %d: status %s", season, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, fmt.Errorf("playoff schedule for %d: %w", season, err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", false, fmt.Errorf("playoff schedule for %d: %w", season, err)
+	}
+
+	doc.Find("table#schedule tbody th[data-stat='date_game']").Each(func(_ int, s *goquery.Selection) {
+		d, err := time.Parse("Mon, Jan 2, 2006", strings.TrimSpace(s.Text()))
+		if err != nil {
+			return
+		}
+		if day := d.Format(time.DateOnly); start == "" || day < start {
+			start = day
+		}
+	})
+	if start == "" {
+		return "", false, fmt.Errorf("playoff schedule for %d lists no games", season)
+	}
+	playoffStarts.Store(key, start)
+	return start, true, nil
+}
+
+// seasonInProgress reports whether a season can still be waiting on its
+// playoffs at now: they end by July of the year the season ends.
+func seasonInProgress(season int, now time.Time) bool {
+	return now.Before(time.Date(season, time.July, 1, 0, 0, 0, 0, time.UTC))
+}
+
+// isPlayoffShot reports whether a BR shot was taken on or after the first
+// playoff day; start is "" before the playoffs begin. NBA.com shots come
+// flagged from the SeasonType they were requested with.
+func isPlayoffShot(start string, gameDate *time.Time) bool {
+	if start == "" || gameDate == nil {
+		return false
+	}
+	return gameDate.Format(time.DateOnly) >= start
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestIsPlayoffShot(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return &d
+	}
+	tests := []struct {
+		name    string
+		start   string
+		date    *time.Time
+		playoff bool
+	}{
+		{"regular season", "2024-04-20", date("2024-04-14"), false},
+		{"play-in", "2024-04-20", date("2024-04-17"), false},
+		{"first playoff day", "2024-04-20", date("2024-04-20"), true},
+		{"playoffs not started", "", date("2024-05-01"), false},
+		{"no date", "2024-04-20", nil, false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.playoff, isPlayoffShot(tc.start, tc.date), tc.name)
+	}
+}
+
+func TestPlayoffStartDate(t *testing.T) {
+	srv := newBRStub(t, map[string]string{
+		"/playoffs/NBA_2020_games.html": brPlayoffSchedule("Tue, Aug 18, 2020", "Mon, Aug 17, 2020"),
+		"/playoffs/NBA_2019_games.html": brPlayoffSchedule(),
+	})
+
+	start, ok, err := playoffStartDate(srv.URL, 2020)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "2020-08-17", start)
+
+	// A finished season without a schedule is an error, not regular season.
+	_, _, err = playoffStartDate(srv.URL, 2018)
+	assert.Error(t, err)
+	_, _, err = playoffStartDate(srv.URL, 2019)
+	assert.Error(t, err)
+
+	// One still in progress has no playoffs yet.
+	_, ok, err = playoffStartDate(srv.URL, time.Now().Year()+1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSeasonInProgress(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, seasonInProgress(2025, now))
+	assert.True(t, seasonInProgress(2026, now))
+	assert.False(t, seasonInProgress(2024, now))
+}
+
+func TestImportShotChartFlagsPlayoffShots(t *testing.T) {
+	srv := newBRStub(t, map[string]string{
+		"/players/c/curryst01/shooting/2024": brShotPage(
+			[2]string{"top:62px;left:468px;", "Apr 14, 2024, GSW vs UTA<br>1st Qtr, 9:00 remaining<br>Made 3-pointer from 24 ft<br>GSW leads 3-0"},
+			[2]string{"top:62px;left:468px;", "Apr 21, 2024, GSW at DEN<br>1st Qtr, 9:00 remaining<br>Made 3-pointer from 24 ft<br>GSW leads 3-0"},
+		),
+		"/playoffs/NBA_2024_games.html": brPlayoffSchedule("Sat, Apr 20, 2024"),
+	})
+	db := newShotTestDB(t)
+
+	require.NoError(t, ImportShotChart(db, BRProvider{BaseURL: srv.URL}, "curryst01", 2024, 2024))
+
+	var flags []bool
+	require.NoError(t, db.Model(&models.PlayerShotChart{}).Order("game_date").Pluck("is_playoff", &flags).Error)
+	assert.Equal(t, []bool{false, true}, flags)
+}
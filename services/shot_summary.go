@@ -0,0 +1,100 @@
+// File: services/shot_summary.go
+package services
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+// Shot distance bands as Basketball-Reference's shooting tables draw them.
+const (
+	BandRim     = "0-3"
+	BandShort   = "3-10"
+	BandMid     = "10-16"
+	BandLongTwo = "16-3P"
+	BandThreePt = "3P"
+)
+
+// ShotBands lists the bands in summary order.
+var ShotBands = []string{BandRim, BandShort, BandMid, BandLongTwo, BandThreePt}
+
+// ShotSummaryGroups are the columns SummarizeShotSeasons needs its
+// tallies grouped on.
+var ShotSummaryGroups = []string{"season", "is_playoff", "distance_ft", "shot_type"}
+
+// ShotSeasonSummary is a player's shooting in one season and season type.
+type ShotSeasonSummary struct {
+	Season    int       `json:"season"`
+	IsPlayoff bool      `json:"isPlayoff"`
+	Totals    ShotBin   `json:"totals"`
+	Bands     []ShotBin `json:"bands"` // one per ShotBands entry, empty ones included
+}
+
+// shotBand is the distance band tallied shots fall in.
+func shotBand(s *repository.ShotTally) string {
+	switch {
+	case s.ShotType == "3-pointer":
+		return BandThreePt
+	case s.DistanceFt < 3:
+		return BandRim
+	case s.DistanceFt < 10:
+		return BandShort
+	case s.DistanceFt < 16:
+		return BandMid
+	}
+	return BandLongTwo
+}
+
+// SummarizeShotSeasons totals shot tallies, grouped at least on
+// ShotSummaryGroups, per season and season type, by distance band,
+// ordered by season with the regular season first.
+func SummarizeShotSeasons(tallies []repository.ShotTally) []ShotSeasonSummary {
+	type key struct {
+		season    int
+		isPlayoff bool
+	}
+	byKey := map[key]*ShotSeasonSummary{}
+	for i := range tallies {
+		s := &tallies[i]
+		if s.Attempts == 0 {
+			continue
+		}
+		k := key{s.Season, s.IsPlayoff}
+		sum, ok := byKey[k]
+		if !ok {
+			sum = &ShotSeasonSummary{Season: s.Season, IsPlayoff: s.IsPlayoff, Totals: ShotBin{Key: "all"}}
+			for _, band := range ShotBands {
+				sum.Bands = append(sum.Bands, ShotBin{Key: band})
+			}
+			byKey[k] = sum
+		}
+		sum.Totals.add(s)
+		sum.Bands[slices.Index(ShotBands, shotBand(s))].add(s)
+	}
+
+	summaries := make([]ShotSeasonSummary, 0, len(byKey))
+	for _, sum := range byKey {
+		sum.Totals.finish()
+		for i := range sum.Bands {
+			sum.Bands[i].finish()
+		}
+		summaries = append(summaries, *sum)
+	}
+	slices.SortFunc(summaries, func(a, b ShotSeasonSummary) int {
+		return cmp.Or(cmp.Compare(a.Season, b.Season), CmpBool(a.IsPlayoff, b.IsPlayoff))
+	})
+	return summaries
+}
+
+// CmpBool orders false before true, for cmp.Or chains.
+func CmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+	"github.com/nprasad2077/NBA_Go/repository"
+)
+
+func TestSummarizeShotSeasons(t *testing.T) {
+	shot := func(season int, playoff bool, dist int, made bool, shotType string) repository.ShotTally {
+		return repository.TallyShot(&models.PlayerShotChart{Season: season, IsPlayoff: playoff, DistanceFt: dist, Result: made, ShotType: shotType})
+	}
+	summaries := SummarizeShotSeasons([]repository.ShotTally{
+		shot(2024, true, 1, true, "2-pointer"),
+		shot(2024, false, 2, true, "2-pointer"),
+		shot(2024, false, 12, false, "2-pointer"),
+		shot(2024, false, 18, true, "2-pointer"),
+		shot(2024, false, 25, true, "3-pointer"),
+		shot(2024, false, 24, false, "3-pointer"),
+		shot(2023, false, 5, true, "2-pointer"),
+	})
+
+	require.Len(t, summaries, 3)
+	assert.Equal(t, 2023, summaries[0].Season)
+	assert.False(t, summaries[1].IsPlayoff)
+	assert.True(t, summaries[2].IsPlayoff)
+
+	regular := summaries[1]
+	assert.Equal(t, 5, regular.Totals.Attempts)
+	assert.Equal(t, 0.6, regular.Totals.FGPercent)
+	assert.Equal(t, 1.4, regular.Totals.PointsPerShot)
+	require.Len(t, regular.Bands, len(ShotBands))
+	want := map[string][2]int{ // attempts, makes
+		BandRim: {1, 1}, BandShort: {0, 0}, BandMid: {1, 0}, BandLongTwo: {1, 1}, BandThreePt: {2, 1},
+	}
+	for i, b := range regular.Bands {
+		assert.Equal(t, ShotBands[i], b.Key)
+		assert.Equal(t, want[b.Key], [2]int{b.Attempts, b.Makes}, b.Key)
+	}
+	assert.Equal(t, 0.5, regular.Bands[4].FGPercent)
+}
+
+func TestSummarizeShotSeasonsGroupedTallies(t *testing.T) {
+	summaries := SummarizeShotSeasons([]repository.ShotTally{
+		{Season: 2024, DistanceFt: 1, ShotType: "2-pointer", Attempts: 4, Makes: 3, Points: 6},
+		{Season: 2024, DistanceFt: 25, ShotType: "3-pointer", Attempts: 6, Makes: 2, Points: 6},
+		{Season: 2024, DistanceFt: 26, ShotType: "3-pointer", Attempts: 2, Makes: 1, Points: 3},
+	})
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 12, summaries[0].Totals.Attempts)
+	assert.Equal(t, 1.25, summaries[0].Totals.PointsPerShot)
+	assert.Equal(t, 8, summaries[0].Bands[4].Attempts)
+	assert.Equal(t, 0.375, summaries[0].Bands[4].FGPercent)
+}
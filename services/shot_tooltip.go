@@ -0,0 +1,156 @@
+// File: services/shot_tooltip.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+// ErrMalformedShot wraps every reason parseShotTooltip rejects a marker.
+var ErrMalformedShot = errors.New("malformed shot tooltip")
+
+// parseShotTooltip turns one BR shot-chart marker into a shot. style is the
+// marker's "top:18px;left:244px;" attribute and tip its tooltip, e.g.
+//
+//	Apr 9, 2024, GSW at UTA<br>1st Qtr, 5:32 remaining<br>Made 3-pointer from 25 ft<br>GSW leads 8-6
+//
+// Only the fields carried by the marker are set; the caller adds player and
+// season. It never panics: a missing or unparseable piece is an error,
+// except that a date, quarter or clock that does not parse into the typed
+// columns leaves those columns zero rather than dropping the shot.
+func parseShotTooltip(style, tip string) (models.PlayerShotChart, error) {
+	var shot models.PlayerShotChart
+
+	top, left, err := parseMarkerStyle(style)
+	if err != nil {
+		return shot, err
+	}
+	shot.Top, shot.Left = top, left
+
+	tipParts := strings.Split(tip, "<br>")
+	if len(tipParts) < 4 {
+		return shot, malformed("want 4 tooltip lines, got %d in %q", len(tipParts), tip)
+	}
+
+	// Date, team, opponent: "Apr 9, 2024, GSW at UTA"
+	dateSegs := strings.SplitN(tipParts[0], ", ", 3)
+	if len(dateSegs) < 2 {
+		return shot, malformed("no date in %q", tipParts[0])
+	}
+	shot.Date = dateSegs[0] + "," + dateSegs[1] // stored form, part of the dedup key
+	if len(dateSegs) == 3 {
+		game := dateSegs[2]
+		if p := strings.SplitN(game, " at ", 2); len(p) == 2 {
+			shot.Team, shot.Opponent = p[0], p[1]
+			shot.IsHome = new(bool) // "GSW at UTA": away game
+		} else if p := strings.SplitN(game, " vs ", 2); len(p) == 2 {
+			shot.Team, shot.Opponent = p[0], p[1]
+			home := true
+			shot.IsHome = &home
+		}
+	}
+
+	// Quarter & time remaining: "1st Qtr, 5:32 remaining"
+	quarter, clock, ok := strings.Cut(tipParts[1], ",")
+	clockFields := strings.Fields(clock)
+	if !ok || len(clockFields) == 0 {
+		return shot, malformed("no game clock in %q", tipParts[1])
+	}
+	shot.Quarter = strings.TrimSpace(quarter)
+	shot.TimeRemaining = clockFields[0]
+
+	// Result, shot type & distance: "Made 3-pointer from 25 ft"
+	rt := strings.Fields(tipParts[2])
+	if len(rt) < 5 || rt[len(rt)-3] != "from" || rt[len(rt)-1] != "ft" {
+		return shot, malformed("unrecognized shot line %q", tipParts[2])
+	}
+	switch rt[0] {
+	case "Made":
+		shot.Result = true
+	case "Missed":
+		shot.Result = false
+	default:
+		return shot, malformed("unrecognized shot result in %q", tipParts[2])
+	}
+	if rt[1] != "2-pointer" && rt[1] != "3-pointer" {
+		return shot, malformed("unrecognized shot type in %q", tipParts[2])
+	}
+	shot.ShotType = rt[1]
+	if shot.DistanceFt, err = strconv.Atoi(rt[len(rt)-2]); err != nil || shot.DistanceFt < 0 {
+		return shot, malformed("unrecognized distance in %q", tipParts[2])
+	}
+
+	// Score & lead flag: "GSW leads 8-6" (team score first)
+	last := strings.Fields(tipParts[3])
+	if len(last) == 0 {
+		return shot, malformed("no score in %q", tipParts[3])
+	}
+	teamStr, oppStr, ok := strings.Cut(last[len(last)-1], "-")
+	teamScore, err1 := strconv.Atoi(teamStr)
+	oppScore, err2 := strconv.Atoi(oppStr)
+	if !ok || err1 != nil || err2 != nil || teamScore < 0 || oppScore < 0 {
+		return shot, malformed("unrecognized score in %q", tipParts[3])
+	}
+	shot.TeamScore, shot.OpponentTeamScore, shot.HasScore = teamScore, oppScore, true
+	shot.Lead = teamScore > oppScore
+
+	// Typed context and court position; unparseable context fields stay zero.
+	ctx, _ := parseShotContext(shot.Date, shot.Quarter, shot.TimeRemaining)
+	shot.GameDate = ctx.GameDate
+	shot.Period = ctx.Period
+	shot.IsOvertime = ctx.IsOvertime
+	shot.SecondsRemaining = ctx.SecondsRemaining
+	shot.ElapsedSeconds = ctx.ElapsedSeconds
+
+	geo := courtGeometry(shot.Top, shot.Left, shot.ShotType)
+	shot.CourtX, shot.CourtY, shot.ShotAngle, shot.Zone = geo.X, geo.Y, geo.Angle, geo.Zone
+
+	return shot, nil
+}
+
+// parseMarkerStyle reads the top and left pixel offsets from a marker's
+// inline style, in either order.
+func parseMarkerStyle(style string) (top, left int, err error) {
+	var haveTop, haveLeft bool
+	for _, decl := range strings.Split(style, ";") {
+		key, val, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		px, err := parsePx(val)
+		switch strings.TrimSpace(key) {
+		case "top":
+			if err != nil {
+				return 0, 0, err
+			}
+			top, haveTop = px, true
+		case "left":
+			if err != nil {
+				return 0, 0, err
+			}
+			left, haveLeft = px, true
+		}
+	}
+	if !haveTop || !haveLeft {
+		return 0, 0, malformed("no top/left in style %q", style)
+	}
+	return top, left, nil
+}
+
+// parsePx turns "244px" into 244.
+func parsePx(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s), "px"))
+	if err != nil {
+		return 0, malformed("unrecognized pixel offset %q", s)
+	}
+	return n, nil
+}
+
+// malformed builds an ErrMalformedShot with detail.
+func malformed(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrMalformedShot, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tooltips as BR renders them on shooting pages from different eras.
+var tooltipFixtures = []struct {
+	name, style, tip string
+	wantDate         string
+	wantTeam, wantOp string
+	wantHome         bool
+	wantMade         bool
+	wantType         string
+	wantDist         int
+	wantScore        [2]int
+	wantPeriod       int
+}{
+	{
+		name:     "2001 road game",
+		style:    "top:146px;left:214px;",
+		tip:      "Nov 2, 2000, PHI at NYK<br>3rd Qtr, 7:41 remaining<br>Missed 2-pointer from 9 ft<br>PHI trails 55-61",
+		wantDate: "Nov 2,2000", wantTeam: "PHI", wantOp: "NYK", wantHome: false,
+		wantMade: false, wantType: "2-pointer", wantDist: 9, wantScore: [2]int{55, 61}, wantPeriod: 3,
+	},
+	{
+		name:     "2013 home game, tied",
+		style:    "top:62px;left:468px;",
+		tip:      "Feb 27, 2013, GSW vs NYK<br>4th Qtr, 0:44 remaining<br>Made 3-pointer from 22 ft<br>Tied 105-105",
+		wantDate: "Feb 27,2013", wantTeam: "GSW", wantOp: "NYK", wantHome: true,
+		wantMade: true, wantType: "3-pointer", wantDist: 22, wantScore: [2]int{105, 105}, wantPeriod: 4,
+	},
+	{
+		name:     "2024 overtime",
+		style:    "top:318px;left:250px;",
+		tip:      "Apr 9, 2024, DAL at MIA<br>1st OT, 3:05 remaining<br>Made 3-pointer from 27 ft<br>DAL leads 118-115",
+		wantDate: "Apr 9,2024", wantTeam: "DAL", wantOp: "MIA", wantHome: false,
+		wantMade: true, wantType: "3-pointer", wantDist: 27, wantScore: [2]int{118, 115}, wantPeriod: 5,
+	},
+	{
+		name:     "style with left before top",
+		style:    "left:250px;top:55px",
+		tip:      "Jan 5, 2019, HOU vs POR<br>2nd Qtr, 11:58 remaining<br>Made 2-pointer from 0 ft<br>HOU leads 30-28",
+		wantDate: "Jan 5,2019", wantTeam: "HOU", wantOp: "POR", wantHome: true,
+		wantMade: true, wantType: "2-pointer", wantDist: 0, wantScore: [2]int{30, 28}, wantPeriod: 2,
+	},
+}
+
+func TestParseShotTooltipFixtures(t *testing.T) {
+	for _, tc := range tooltipFixtures {
+		shot, err := parseShotTooltip(tc.style, tc.tip)
+		if !assert.NoError(t, err, tc.name) {
+			continue
+		}
+		assert.Equal(t, tc.wantDate, shot.Date, tc.name)
+		assert.Equal(t, tc.wantTeam, shot.Team, tc.name)
+		assert.Equal(t, tc.wantOp, shot.Opponent, tc.name)
+		if assert.NotNil(t, shot.IsHome, tc.name) {
+			assert.Equal(t, tc.wantHome, *shot.IsHome, tc.name)
+		}
+		assert.Equal(t, tc.wantMade, shot.Result, tc.name)
+		assert.Equal(t, tc.wantType, shot.ShotType, tc.name)
+		assert.Equal(t, tc.wantDist, shot.DistanceFt, tc.name)
+		assert.Equal(t, tc.wantScore, [2]int{shot.TeamScore, shot.OpponentTeamScore}, tc.name)
+		assert.Equal(t, tc.wantPeriod, shot.Period, tc.name)
+		assert.NotEmpty(t, shot.Zone, tc.name)
+	}
+}
+
+func TestParseShotTooltipMalformed(t *testing.T) {
+	good := tooltipFixtures[0]
+	tests := []struct{ name, style, tip string }{
+		{"blank style", "", good.tip},
+		{"style without left", "top:10px;", good.tip},
+		{"non-numeric px", "top:abcpx;left:10px", good.tip},
+		{"empty tip", good.style, ""},
+		{"missing score line", good.style, "Nov 2, 2000, PHI at NYK<br>3rd Qtr, 7:41 remaining<br>Missed 2-pointer from 9 ft"},
+		{"made without distance", good.style, "Nov 2, 2000, PHI at NYK<br>3rd Qtr, 7:41 remaining<br>Made 3-pointer<br>PHI trails 55-61"},
+		{"no clock", good.style, "Nov 2, 2000, PHI at NYK<br>3rd Qtr<br>Missed 2-pointer from 9 ft<br>PHI trails 55-61"},
+		{"bad score", good.style, "Nov 2, 2000, PHI at NYK<br>3rd Qtr, 7:41 remaining<br>Missed 2-pointer from 9 ft<br>PHI trails"},
+		{"no date", good.style, "PHI at NYK<br>3rd Qtr, 7:41 remaining<br>Missed 2-pointer from 9 ft<br>PHI trails 55-61"},
+	}
+
+	for _, tc := range tests {
+		_, err := parseShotTooltip(tc.style, tc.tip)
+		assert.ErrorIs(t, err, ErrMalformedShot, tc.name)
+	}
+}
+
+func FuzzParseShotTooltip(f *testing.F) {
+	for _, tc := range tooltipFixtures {
+		f.Add(tc.style, tc.tip)
+	}
+	f.Add("", "")
+	f.Add("top:1px;left:1px", "<br><br><br>")
+	f.Add("top:px;left:", "a, b, c<br>,<br>Made x from ft<br>-")
+
+	f.Fuzz(func(t *testing.T, style, tip string) {
+		shot, err := parseShotTooltip(style, tip)
+		if err != nil {
+			if !errors.Is(err, ErrMalformedShot) {
+				t.Fatalf("error does not wrap ErrMalformedShot: %v", err)
+			}
+			return
+		}
+		if shot.Zone == "" {
+			t.Fatalf("accepted shot without a zone: %+v", shot)
+		}
+		if shot.SecondsRemaining < 0 || shot.DistanceFt < 0 {
+			t.Fatalf("negative clock or distance: %+v", shot)
+		}
+	})
+}
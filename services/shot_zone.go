@@ -0,0 +1,95 @@
+// File: services/shot_zone.go
+package services
+
+import "math"
+
+// BR draws shots on a 500x472 px half-court image at 10 px per foot, with the
+// baseline along the top edge and the rim 5.25 ft in from it.
+const (
+	courtPxPerFt     = 10.0
+	basketLeftPx     = 250.0
+	basketTopPx      = 52.5
+	basketToHalfFt   = 47.0 - 5.25
+	restrictedRadius = 4.0
+	laneHalfWidthFt  = 8.0
+	freeThrowLineFt  = 19.0 - 5.25 // rim to free throw line
+	cornerThreeMaxY  = 14.0 - 5.25 // corner 3 runs 14 ft out from the baseline
+	cornerThreeX     = 22.0
+	arcThreeRadiusFt = 23.75
+)
+
+// Shot zones, following the NBA's court regions. Left and right are from the
+// shooter's point of view facing the basket, i.e. the left of BR's image.
+const (
+	ZoneRestrictedArea = "restricted_area"
+	ZonePaintNonRA     = "paint_non_ra"
+	ZoneMidRange       = "mid_range"
+	ZoneLeftCorner3    = "left_corner_3"
+	ZoneRightCorner3   = "right_corner_3"
+	ZoneAboveBreak3    = "above_break_3"
+	ZoneBackcourt      = "backcourt"
+)
+
+// ShotZones lists every zone value, for request validation.
+var ShotZones = []string{
+	ZoneRestrictedArea, ZonePaintNonRA, ZoneMidRange,
+	ZoneLeftCorner3, ZoneRightCorner3, ZoneAboveBreak3, ZoneBackcourt,
+}
+
+// shotGeometry is a shot's position in feet relative to the basket.
+type shotGeometry struct {
+	X     float64 // feet right of the basket, shooter's view
+	Y     float64 // feet out from the basket toward half court
+	Angle float64 // degrees off the basket's centre line; negative = left
+	Zone  string
+}
+
+// courtGeometry converts BR pixel offsets into feet and classifies the zone.
+// shotType ("2-pointer"/"3-pointer") decides 2 vs 3 when known, since the
+// tooltip is more reliable than a pixel near the arc.
+func courtGeometry(top, left int, shotType string) shotGeometry {
+	g := shotGeometry{
+		X: round1((float64(left) - basketLeftPx) / courtPxPerFt),
+		Y: round1((float64(top) - basketTopPx) / courtPxPerFt),
+	}
+	g.Angle = round1(math.Atan2(g.X, g.Y) * 180 / math.Pi)
+	g.Zone = classifyZone(g.X, g.Y, shotType)
+	return g
+}
+
+// classifyZone maps a position in feet to one of ShotZones.
+func classifyZone(x, y float64, shotType string) string {
+	dist := math.Hypot(x, y)
+
+	var three bool
+	switch shotType {
+	case "3-pointer":
+		three = true
+	case "2-pointer":
+		three = false
+	default:
+		three = dist >= arcThreeRadiusFt || (math.Abs(x) >= cornerThreeX && y <= cornerThreeMaxY)
+	}
+
+	switch {
+	case y > basketToHalfFt:
+		return ZoneBackcourt
+	case three && y <= cornerThreeMaxY && x < 0:
+		return ZoneLeftCorner3
+	case three && y <= cornerThreeMaxY:
+		return ZoneRightCorner3
+	case three:
+		return ZoneAboveBreak3
+	case dist <= restrictedRadius:
+		return ZoneRestrictedArea
+	case math.Abs(x) <= laneHalfWidthFt && y <= freeThrowLineFt:
+		return ZonePaintNonRA
+	default:
+		return ZoneMidRange
+	}
+}
+
+// round1 rounds to one decimal place.
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
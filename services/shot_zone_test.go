@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourtGeometry(t *testing.T) {
+	tests := []struct {
+		name      string
+		top, left int
+		shotType  string
+		wantZone  string
+	}{
+		{"dunk", 55, 250, "2-pointer", ZoneRestrictedArea},
+		{"floater", 150, 240, "2-pointer", ZonePaintNonRA},
+		{"elbow jumper", 190, 330, "2-pointer", ZoneMidRange},
+		{"left corner", 60, 20, "3-pointer", ZoneLeftCorner3},
+		{"right corner", 60, 480, "3-pointer", ZoneRightCorner3},
+		{"top of the key", 320, 250, "3-pointer", ZoneAboveBreak3},
+		{"heave", 470, 250, "3-pointer", ZoneBackcourt},
+		{"no shot type, deep", 330, 250, "", ZoneAboveBreak3},
+	}
+
+	for _, tc := range tests {
+		g := courtGeometry(tc.top, tc.left, tc.shotType)
+		assert.Equal(t, tc.wantZone, g.Zone, tc.name)
+	}
+
+	g := courtGeometry(152, 150, "2-pointer")
+	assert.Equal(t, -10.0, g.X)
+	assert.Equal(t, 10.0, g.Y)
+	assert.Equal(t, -45.0, g.Angle)
+}
@@ -0,0 +1,249 @@
+// File: services/similar.go
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+// similarityFeatures are the advanced stats a season's style is compared
+// on, by JSON name: role (usage, assists, rebounds), efficiency, defense
+// events, shot diet and overall impact (BPM). since is the first season
+// BR has each one, by league; earlier seasons store 0 rather than a value.
+// NBA.com rows store 0 for the ones it does not report (nbaStatsUntracked).
+var similarityFeatures = []struct {
+	name  string
+	since map[string]int
+	value func(*models.PlayerAdvancedStat) float64
+}{
+	// USG% needs turnovers, counted from 1977-78 in the NBA.
+	{"usagePercent", map[string]int{models.LeagueNBA: 1978}, func(s *models.PlayerAdvancedStat) float64 { return s.UsagePercent }},
+	{"tsPercent", nil, func(s *models.PlayerAdvancedStat) float64 { return s.TSPercent }},
+	{"assistPercent", nil, func(s *models.PlayerAdvancedStat) float64 { return s.AssistPercent }},
+	{"totalRBPercent", nil, func(s *models.PlayerAdvancedStat) float64 { return s.TotalRBPercent }},
+	{"blockPercent", map[string]int{models.LeagueNBA: 1974, models.LeagueABA: 1973}, func(s *models.PlayerAdvancedStat) float64 { return s.BlockPercent }},
+	{"stealPercent", map[string]int{models.LeagueNBA: 1974, models.LeagueABA: 1973}, func(s *models.PlayerAdvancedStat) float64 { return s.StealPercent }},
+	{"threePAR", map[string]int{models.LeagueNBA: 1980}, func(s *models.PlayerAdvancedStat) float64 { return s.ThreePAR }},
+	{"ftr", nil, func(s *models.PlayerAdvancedStat) float64 { return s.FTR }},
+	{"box", map[string]int{models.LeagueNBA: 1974, models.LeagueABA: 1973}, func(s *models.PlayerAdvancedStat) float64 { return s.Box }},
+}
+
+// SimilarityFeatures lists the feature names, in order.
+func SimilarityFeatures() []string {
+	names := make([]string, len(similarityFeatures))
+	for i, f := range similarityFeatures {
+		names[i] = f.name
+	}
+	return names
+}
+
+// ParseSimilarityWeights reads "usagePercent:2,box:0.5"; features left
+// out weigh 1 and a weight of 0 drops a feature.
+func ParseSimilarityWeights(s string) (map[string]float64, error) {
+	weights := make(map[string]float64, len(similarityFeatures))
+	for _, f := range similarityFeatures {
+		weights[f.name] = 1
+	}
+	if strings.TrimSpace(s) == "" {
+		return weights, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		name, raw, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q (want feature:weight)", part)
+		}
+		if _, known := weights[name]; !known {
+			return nil, fmt.Errorf("unknown feature %q (want one of %s)", name, strings.Join(SimilarityFeatures(), ", "))
+		}
+		w, err := strconv.ParseFloat(raw, 64)
+		if err != nil || w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("invalid weight %q for %s", raw, name)
+		}
+		weights[name] = w
+	}
+	return weights, nil
+}
+
+// SimilarityFirstSeason is the first season of league in which every
+// feature with a non-zero weight is tracked; 0 when all of them always are.
+func SimilarityFirstSeason(league string, weights map[string]float64) int {
+	var first int
+	for _, f := range similarityFeatures {
+		if weights[f.name] > 0 {
+			first = max(first, f.since[league])
+		}
+	}
+	return first
+}
+
+// UntrackedSimilarityFeatures lists the weighted features s stores 0 for:
+// league did not track them yet that season, or s comes from NBA.com,
+// which does not report them.
+func UntrackedSimilarityFeatures(league string, s *models.PlayerAdvancedStat, weights map[string]float64) []string {
+	var out []string
+	for _, f := range similarityFeatures {
+		if weights[f.name] > 0 && (s.Season < f.since[league] || !nbaStatsTracks(DatasetAdvanced, f.name, s.PlayerID)) {
+			out = append(out, f.name)
+		}
+	}
+	return out
+}
+
+// SimilarityScale is each feature's mean and standard deviation over a
+// population of seasons, in similarityFeatures order.
+type SimilarityScale struct {
+	Mean, Std []float64
+}
+
+// NewSimilarityScale standardizes the features over population.
+func NewSimilarityScale(population []models.PlayerAdvancedStat) SimilarityScale {
+	sc := SimilarityScale{
+		Mean: make([]float64, len(similarityFeatures)),
+		Std:  make([]float64, len(similarityFeatures)),
+	}
+	if len(population) == 0 {
+		return sc
+	}
+	n := float64(len(population))
+	for i, f := range similarityFeatures {
+		for j := range population {
+			sc.Mean[i] += f.value(&population[j])
+		}
+		sc.Mean[i] /= n
+		for j := range population {
+			d := f.value(&population[j]) - sc.Mean[i]
+			sc.Std[i] += d * d
+		}
+		sc.Std[i] = math.Sqrt(sc.Std[i] / n)
+	}
+	return sc
+}
+
+// similarityScaleTTL bounds how long a cached scale outlives imports made
+// by another process; imports in this one drop it at once.
+const similarityScaleTTL = time.Hour
+
+// advancedStatsVersion changes whenever this process imports advanced
+// stats, which invalidates every cached similarity scale.
+var advancedStatsVersion atomic.Int64
+
+type cachedScale struct {
+	scale   SimilarityScale
+	version int64
+	built   time.Time
+}
+
+// SimilarityScaleCache keeps one scale per league, season type and set of
+// weighted features, which decides the population, so a search need not
+// reload it.
+type SimilarityScaleCache struct {
+	mu     sync.Mutex
+	scales map[string]cachedScale
+}
+
+// NewSimilarityScaleCache returns an empty cache.
+func NewSimilarityScaleCache() *SimilarityScaleCache {
+	return &SimilarityScaleCache{scales: map[string]cachedScale{}}
+}
+
+// Get returns the cached scale for the key, calling build when there is
+// none yet or it has gone stale.
+func (c *SimilarityScaleCache) Get(league string, isPlayoff bool, weights map[string]float64, build func() (SimilarityScale, error)) (SimilarityScale, error) {
+	key := fmt.Sprintf("%s|%t", league, isPlayoff)
+	for _, f := range similarityFeatures {
+		if weights[f.name] > 0 {
+			key += "|" + f.name
+		}
+	}
+	version := advancedStatsVersion.Load()
+	c.mu.Lock()
+	e, ok := c.scales[key]
+	c.mu.Unlock()
+	if ok && e.version == version && time.Since(e.built) < similarityScaleTTL {
+		return e.scale, nil
+	}
+	scale, err := build()
+	if err != nil {
+		return SimilarityScale{}, err
+	}
+	c.mu.Lock()
+	c.scales[key] = cachedScale{scale: scale, version: version, built: time.Now()}
+	c.mu.Unlock()
+	return scale, nil
+}
+
+// FeatureContribution is one feature's part in a similarity distance.
+type FeatureContribution struct {
+	Value      float64 `json:"value"`
+	Difference float64 `json:"difference"` // Value minus the target's value
+	// Share is the fraction of the squared distance this feature adds.
+	Share float64 `json:"share"`
+}
+
+// SimilarSeason is a player-season near the target.
+type SimilarSeason struct {
+	PlayerID      string                         `json:"playerId"`
+	PlayerName    string                         `json:"playerName"`
+	Season        int                            `json:"season"`
+	Age           int                            `json:"age"`
+	Team          string                         `json:"team"`
+	Distance      float64                        `json:"distance"`
+	Contributions map[string]FeatureContribution `json:"contributions"`
+}
+
+// FindSimilar returns the k seasons of pool nearest target. Each feature
+// is standardized by scale (z-scores), so a point of usage and a point of
+// block rate count alike, then the distance is the weighted Euclidean
+// distance between z-score vectors. Ties keep pool order.
+func FindSimilar(target *models.PlayerAdvancedStat, pool []models.PlayerAdvancedStat, scale SimilarityScale, weights map[string]float64, k int) []SimilarSeason {
+	if len(pool) == 0 || k <= 0 {
+		return nil
+	}
+	out := make([]SimilarSeason, len(pool))
+	for j := range pool {
+		s := &pool[j]
+		parts := make([]float64, len(similarityFeatures))
+		var sum float64
+		for i, f := range similarityFeatures {
+			if scale.Std[i] == 0 {
+				continue // the population does not vary on it
+			}
+			z := (f.value(s) - f.value(target)) / scale.Std[i]
+			parts[i] = weights[f.name] * z * z
+			sum += parts[i]
+		}
+		out[j] = SimilarSeason{
+			PlayerID: s.PlayerID, PlayerName: s.PlayerName, Season: s.Season, Age: s.Age, Team: s.Team,
+			Distance:      round3(math.Sqrt(sum)),
+			Contributions: make(map[string]FeatureContribution, len(similarityFeatures)),
+		}
+		for i, f := range similarityFeatures {
+			if weights[f.name] == 0 {
+				continue
+			}
+			c := FeatureContribution{Value: f.value(s), Difference: round3(f.value(s) - f.value(target))}
+			if sum > 0 {
+				c.Share = round3(parts[i] / sum)
+			}
+			out[j].Contributions[f.name] = c
+		}
+	}
+	slices.SortStableFunc(out, func(a, b SimilarSeason) int {
+		switch {
+		case a.Distance < b.Distance:
+			return -1
+		case a.Distance > b.Distance:
+			return 1
+		}
+		return 0
+	})
+	return out[:min(k, len(out))]
+}
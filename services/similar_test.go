@@ -0,0 +1,134 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nprasad2077/NBA_Go/models"
+)
+
+func TestFindSimilar(t *testing.T) {
+	season := func(pid string, usage, box float64) models.PlayerAdvancedStat {
+		return models.PlayerAdvancedStat{PlayerID: pid, Season: 2024, UsagePercent: usage, Box: box, TSPercent: 0.55}
+	}
+	target := season("t", 30, 5)
+	pool := []models.PlayerAdvancedStat{
+		season("far", 15, -3),
+		season("near", 29, 5),
+		season("usage", 30, 1),
+		season("box", 20, 5),
+	}
+	weights, err := ParseSimilarityWeights("")
+	require.NoError(t, err)
+
+	scale := NewSimilarityScale(pool)
+	got := FindSimilar(&target, pool, scale, weights, 3)
+	require.Len(t, got, 3)
+	assert.Equal(t, "near", got[0].PlayerID)
+	assert.Equal(t, "far", pool[0].PlayerID) // the pool is left as is
+	assert.Equal(t, 1.0, got[0].Contributions["usagePercent"].Share)
+	assert.Equal(t, -1.0, got[0].Contributions["usagePercent"].Difference)
+	assert.Zero(t, got[0].Contributions["tsPercent"].Share) // no spread in the pool
+	for _, s := range got {
+		var total float64
+		for _, c := range s.Contributions {
+			total += c.Share
+		}
+		assert.InDelta(t, 1, total, 0.01, s.PlayerID)
+	}
+
+	// Weighting usage up ranks the same-usage season ahead of the same-BPM one.
+	order := func(weights map[string]float64) []string {
+		var ids []string
+		for _, s := range FindSimilar(&target, pool, scale, weights, 4)[1:3] {
+			ids = append(ids, s.PlayerID)
+		}
+		return ids
+	}
+	heavy, err := ParseSimilarityWeights("usagePercent:10")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"usage", "box"}, order(heavy))
+	light, err := ParseSimilarityWeights("usagePercent:0.1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"box", "usage"}, order(light))
+
+	dropped, err := ParseSimilarityWeights("usagePercent:0")
+	require.NoError(t, err)
+	got = FindSimilar(&target, pool, scale, dropped, 1)
+	assert.Equal(t, "near", got[0].PlayerID)
+	assert.Zero(t, got[0].Distance)
+	assert.NotContains(t, got[0].Contributions, "usagePercent")
+
+	assert.Empty(t, FindSimilar(&target, nil, scale, weights, 3))
+}
+
+func TestSimilarityFirstSeason(t *testing.T) {
+	all, err := ParseSimilarityWeights("")
+	require.NoError(t, err)
+	assert.Equal(t, 1980, SimilarityFirstSeason(models.LeagueNBA, all))
+	assert.Zero(t, SimilarityFirstSeason(models.LeagueWNBA, all))
+	assert.Equal(t, []string{"usagePercent", "threePAR"}, UntrackedSimilarityFeatures(models.LeagueNBA, &models.PlayerAdvancedStat{PlayerID: "gervige01", Season: 1976}, all))
+	// NBA.com rows have no BLK%, STL%, 3PAr, FTr or BPM in any season.
+	assert.Equal(t, []string{"blockPercent", "stealPercent", "threePAR", "ftr", "box"},
+		UntrackedSimilarityFeatures(models.LeagueNBA, &models.PlayerAdvancedStat{PlayerID: "203999", Season: 2024}, all))
+
+	noThrees, err := ParseSimilarityWeights("threePAR:0,usagePercent:0")
+	require.NoError(t, err)
+	assert.Equal(t, 1974, SimilarityFirstSeason(models.LeagueNBA, noThrees))
+	assert.Empty(t, UntrackedSimilarityFeatures(models.LeagueNBA, &models.PlayerAdvancedStat{PlayerID: "gervige01", Season: 1976}, noThrees))
+}
+
+func TestSimilarityScaleCache(t *testing.T) {
+	all, err := ParseSimilarityWeights("")
+	require.NoError(t, err)
+	cache := NewSimilarityScaleCache()
+	builds := 0
+	build := func() (SimilarityScale, error) {
+		builds++
+		return NewSimilarityScale(nil), nil
+	}
+	for range 2 {
+		_, err := cache.Get(models.LeagueNBA, false, all, build)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, builds)
+
+	_, err = cache.Get(models.LeagueNBA, true, all, build)
+	require.NoError(t, err)
+	assert.Equal(t, 2, builds)
+
+	// Dropping a feature changes which seasons are compared; the weight of
+	// a kept one does not.
+	heavy, err := ParseSimilarityWeights("usagePercent:3")
+	require.NoError(t, err)
+	_, err = cache.Get(models.LeagueNBA, true, heavy, build)
+	require.NoError(t, err)
+	assert.Equal(t, 2, builds)
+	noBox, err := ParseSimilarityWeights("box:0")
+	require.NoError(t, err)
+	_, err = cache.Get(models.LeagueNBA, true, noBox, build)
+	require.NoError(t, err)
+	assert.Equal(t, 3, builds)
+
+	// An advanced import drops every cached scale.
+	advancedStatsVersion.Add(1)
+	_, err = cache.Get(models.LeagueNBA, false, all, build)
+	require.NoError(t, err)
+	assert.Equal(t, 4, builds)
+}
+
+func TestParseSimilarityWeights(t *testing.T) {
+	w, err := ParseSimilarityWeights(" box:0.5 ,ftr:2")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, w["box"])
+	assert.Equal(t, 2.0, w["ftr"])
+	assert.Equal(t, 1.0, w["usagePercent"])
+	assert.Len(t, w, len(SimilarityFeatures()))
+
+	for _, bad := range []string{"box", "per:1", "box:-1", "box:x", "box:Inf", "box:NaN"} {
+		_, err := ParseSimilarityWeights(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -8,11 +8,27 @@ import (
 	"time"
 )
 
+// DefaultTimeout bounds a GetJSON request.
+const DefaultTimeout = 10 * time.Second
+
 func GetJSON(url string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	return GetJSONWithHeaders(url, nil, DefaultTimeout)
+}
+
+// GetJSONWithHeaders is GetJSON with extra request headers and its own
+// timeout; stats.nba.com rejects requests that lack browser-like headers
+// and answers slowly.
+func GetJSONWithHeaders(url string, headers map[string]string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 	client := &http.Client{}
 
 	resp, err := client.Do(req)
@@ -34,4 +34,13 @@ var (
 		},
 		[]string{"operation", "entity"},
 	)
+
+	// ShotsSkippedTotal counts scraped shots dropped instead of stored
+	ShotsSkippedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "nba_shots_skipped_total",
+			Help: "Total number of scraped shots skipped as unparseable",
+		},
+		[]string{"reason"},
+	)
 )
\ No newline at end of file